@@ -0,0 +1,170 @@
+// Package abi decodes the arguments of the calls to a smart contract and the events it emits, as described by the ABI
+// JSON file generated along with the contract.
+package abi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+)
+
+var (
+	ErrInvalidType         = errors.New("invalid ABI type")
+	ErrUnknownType         = errors.New("unknown ABI type")
+	ErrUnknownEndpoint     = errors.New("unknown endpoint")
+	ErrUnknownEvent        = errors.New("unknown event")
+	ErrUnexpectedEnd       = errors.New("unexpected end of encoded value")
+	ErrTrailingBytes       = errors.New("trailing bytes after encoded value")
+	ErrTooManyArguments    = errors.New("too many arguments")
+	ErrMissingArgument     = errors.New("missing argument")
+	ErrUnknownDiscriminant = errors.New("unknown enum discriminant")
+)
+
+// Parameter is an input of an endpoint or of an event.
+type Parameter struct {
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Indexed is set on the inputs of an event sent as topics, the other ones are encoded in the data of the event.
+	Indexed bool `json:"indexed,omitempty"`
+	// MultiArg is set by older ABIs on variadic inputs.
+	MultiArg bool `json:"multi_arg,omitempty"`
+
+	typeExpression *TypeExpression
+}
+
+type Endpoint struct {
+	Name    string       `json:"name"`
+	Inputs  []*Parameter `json:"inputs"`
+	Outputs []*Parameter `json:"outputs"`
+}
+
+type EventDefinition struct {
+	Identifier string       `json:"identifier"`
+	Inputs     []*Parameter `json:"inputs"`
+}
+
+// TypeDefinition is a struct or an enum declared by the contract.
+type TypeDefinition struct {
+	Type     string       `json:"type"`
+	Fields   []*Parameter `json:"fields,omitempty"`
+	Variants []*Variant   `json:"variants,omitempty"`
+}
+
+type Variant struct {
+	Name         string       `json:"name"`
+	Discriminant int          `json:"discriminant"`
+	Fields       []*Parameter `json:"fields,omitempty"`
+}
+
+type ABI struct {
+	Name        string                     `json:"name"`
+	Constructor *Endpoint                  `json:"constructor,omitempty"`
+	Endpoints   []*Endpoint                `json:"endpoints"`
+	Events      []*EventDefinition         `json:"events,omitempty"`
+	Types       map[string]*TypeDefinition `json:"types,omitempty"`
+}
+
+// Load reads the ABI JSON file at the given path.
+func Load(path string) (*ABI, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return Parse(b)
+}
+
+// Parse reads an ABI JSON document and checks that every type it refers to is known.
+func Parse(data []byte) (*ABI, error) {
+	a := ABI{}
+	if err := json.Unmarshal(data, &a); err != nil {
+		return nil, err
+	}
+
+	parameters := make([]*Parameter, 0)
+	if a.Constructor != nil {
+		parameters = append(parameters, a.Constructor.Inputs...)
+	}
+
+	for _, e := range a.Endpoints {
+		parameters = append(parameters, e.Inputs...)
+		parameters = append(parameters, e.Outputs...)
+	}
+
+	for _, e := range a.Events {
+		parameters = append(parameters, e.Inputs...)
+	}
+
+	for _, t := range a.Types {
+		parameters = append(parameters, t.Fields...)
+		for _, v := range t.Variants {
+			parameters = append(parameters, v.Fields...)
+		}
+	}
+
+	for _, p := range parameters {
+		if err := a.resolve(p); err != nil {
+			return nil, err
+		}
+	}
+
+	return &a, nil
+}
+
+func (a *ABI) resolve(p *Parameter) error {
+	t, err := ParseType(p.Type)
+	if err != nil {
+		return err
+	}
+
+	if p.MultiArg && !t.isMultiValue() {
+		t = &TypeExpression{Name: "variadic", Arguments: []*TypeExpression{t}}
+	}
+
+	if err := a.checkType(t); err != nil {
+		return fmt.Errorf("%s: %w", p.Name, err)
+	}
+
+	p.typeExpression = t
+
+	return nil
+}
+
+func (a *ABI) checkType(t *TypeExpression) error {
+	if _, known := a.Types[t.Name]; !known && !isBuiltinType(t.Name) {
+		return fmt.Errorf("%w: %s", ErrUnknownType, t.Name)
+	}
+
+	if n := typeArguments(t.Name); (n == -1 && len(t.Arguments) == 0) || (n != -1 && len(t.Arguments) != n) {
+		return fmt.Errorf("%w: %s has %d type argument(s)", ErrInvalidType, t, len(t.Arguments))
+	}
+
+	for _, argument := range t.Arguments {
+		if err := a.checkType(argument); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (a *ABI) Endpoint(name string) (*Endpoint, bool) {
+	for _, e := range a.Endpoints {
+		if e.Name == name {
+			return e, true
+		}
+	}
+
+	return nil, false
+}
+
+func (a *ABI) Event(identifier string) (*EventDefinition, bool) {
+	for _, e := range a.Events {
+		if e.Identifier == identifier {
+			return e, true
+		}
+	}
+
+	return nil, false
+}
@@ -0,0 +1,523 @@
+package abi
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/thefabric-io/elrond-transaction-processor/bech32"
+)
+
+const (
+	addressHRP    = "erd"
+	addressLength = 32
+)
+
+// integerSizes are the sizes in bytes of the fixed size integers when nested.
+var integerSizes = map[string]int{
+	"u8": 1, "u16": 2, "u32": 4, "u64": 8, "usize": 4,
+	"i8": 1, "i16": 2, "i32": 4, "i64": 8, "isize": 4,
+}
+
+var bufferTypes = map[string]bool{
+	"bytes": true, "BoxedBytes": true, "ManagedBuffer": true,
+}
+
+var stringTypes = map[string]bool{
+	"TokenIdentifier": true, "EgldOrEsdtTokenIdentifier": true, "utf-8 string": true, "String": true,
+	"string": true, "str": true,
+}
+
+func isBuiltinType(name string) bool {
+	if _, found := integerSizes[name]; found {
+		return true
+	}
+
+	if bufferTypes[name] || stringTypes[name] {
+		return true
+	}
+
+	switch name {
+	case "BigUint", "BigInt", "bool", "Address", "H256", "CodeMetadata", "List", "Option", "tuple", "variadic",
+		"optional", "multi", "counted-variadic":
+		return true
+	}
+
+	return isArrayType(name)
+}
+
+// typeArguments returns the number of type arguments expected by a builtin type, -1 when it takes one or more.
+func typeArguments(name string) int {
+	switch name {
+	case "List", "Option", "variadic", "optional", "counted-variadic":
+		return 1
+	case "tuple", "multi":
+		return -1
+	}
+
+	if isArrayType(name) {
+		return 1
+	}
+
+	return 0
+}
+
+func isArrayType(name string) bool {
+	if !strings.HasPrefix(name, "array") {
+		return false
+	}
+
+	_, err := strconv.Atoi(strings.TrimPrefix(name, "array"))
+
+	return err == nil
+}
+
+// DecodeCall decodes the arguments of a call of the endpoint of the given name, each argument being the raw bytes of
+// one of the hex encoded arguments of the data of the transaction.
+func (a *ABI) DecodeCall(endpoint string, arguments [][]byte) (*Call, error) {
+	e, found := a.Endpoint(endpoint)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEndpoint, endpoint)
+	}
+
+	fields, err := a.decodeArguments(e.Inputs, arguments)
+	if err != nil {
+		return nil, fmt.Errorf("could not decode call of %s: %w", endpoint, err)
+	}
+
+	return &Call{Endpoint: endpoint, Arguments: fields}, nil
+}
+
+// DecodeEvent decodes an event from its raw topics and data. The first topic is the identifier of the event, followed
+// by its indexed inputs, the other inputs are encoded in the data.
+func (a *ABI) DecodeEvent(topics [][]byte, data []byte) (*Event, error) {
+	if len(topics) == 0 {
+		return nil, fmt.Errorf("%w: no topic", ErrUnknownEvent)
+	}
+
+	identifier := string(topics[0])
+
+	e, found := a.Event(identifier)
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, identifier)
+	}
+
+	indexed, notIndexed := make([]*Parameter, 0), make([]*Parameter, 0)
+	for _, p := range e.Inputs {
+		if p.Indexed {
+			indexed = append(indexed, p)
+		} else {
+			notIndexed = append(notIndexed, p)
+		}
+	}
+
+	fields, err := a.decodeArguments(indexed, topics[1:])
+	if err != nil {
+		return nil, fmt.Errorf("could not decode topics of event %s: %w", identifier, err)
+	}
+
+	/*
+		A single input is top encoded in the data, several inputs are nested encoded one after the other
+	*/
+	switch len(notIndexed) {
+	case 0:
+	case 1:
+		v, err := a.decodeTop(notIndexed[0].typeExpression, data)
+		if err != nil {
+			return nil, fmt.Errorf("could not decode data of event %s: %w", identifier, err)
+		}
+
+		fields = append(fields, &Field{Name: notIndexed[0].Name, Type: notIndexed[0].Type, Value: v})
+	default:
+		r := &reader{data: data}
+		for _, p := range notIndexed {
+			v, err := a.decodeNested(p.typeExpression, r)
+			if err != nil {
+				return nil, fmt.Errorf("could not decode data of event %s: %w", identifier, err)
+			}
+
+			fields = append(fields, &Field{Name: p.Name, Type: p.Type, Value: v})
+		}
+	}
+
+	return &Event{Identifier: identifier, Fields: fields}, nil
+}
+
+func (a *ABI) decodeArguments(parameters []*Parameter, arguments [][]byte) (Struct, error) {
+	r := &argumentReader{arguments: arguments}
+	fields := make(Struct, 0, len(parameters))
+
+	for _, p := range parameters {
+		v, err := a.decodeMulti(p.typeExpression, r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Name, err)
+		}
+
+		fields = append(fields, &Field{Name: p.Name, Type: p.Type, Value: v})
+	}
+
+	if r.remaining() != 0 {
+		return nil, fmt.Errorf("%w: %d left", ErrTooManyArguments, r.remaining())
+	}
+
+	return fields, nil
+}
+
+type argumentReader struct {
+	arguments [][]byte
+	offset    int
+}
+
+func (r *argumentReader) remaining() int {
+	return len(r.arguments) - r.offset
+}
+
+func (r *argumentReader) next() ([]byte, error) {
+	if r.remaining() == 0 {
+		return nil, ErrMissingArgument
+	}
+
+	r.offset++
+
+	return r.arguments[r.offset-1], nil
+}
+
+// decodeMulti decodes a value from as many arguments as its type spans.
+func (a *ABI) decodeMulti(t *TypeExpression, r *argumentReader) (interface{}, error) {
+	switch t.Name {
+	case "variadic":
+		values := make([]interface{}, 0)
+		for r.remaining() != 0 {
+			v, err := a.decodeMulti(t.Arguments[0], r)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, v)
+		}
+
+		return values, nil
+	case "counted-variadic":
+		count, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+
+		/* The count comes from the data, each value taking at least one argument */
+		n := new(big.Int).SetBytes(count)
+		if !n.IsInt64() || n.Int64() > int64(r.remaining()) {
+			return nil, fmt.Errorf("%w: %s values of %s in %d arguments", ErrMissingArgument, n, t.Arguments[0], r.remaining())
+		}
+
+		values := make([]interface{}, 0, n.Int64())
+		for i := int64(0); i < n.Int64(); i++ {
+			v, err := a.decodeMulti(t.Arguments[0], r)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, v)
+		}
+
+		return values, nil
+	case "optional":
+		if r.remaining() == 0 {
+			return nil, nil
+		}
+
+		return a.decodeMulti(t.Arguments[0], r)
+	case "multi":
+		values := make([]interface{}, len(t.Arguments))
+		for i, argument := range t.Arguments {
+			v, err := a.decodeMulti(argument, r)
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = v
+		}
+
+		return values, nil
+	}
+
+	argument, err := r.next()
+	if err != nil {
+		return nil, err
+	}
+
+	return a.decodeTop(t, argument)
+}
+
+// decodeTop decodes a value encoded alone in b, which is encoded without length prefixes and leading zeros.
+func (a *ABI) decodeTop(t *TypeExpression, b []byte) (interface{}, error) {
+	if size, found := integerSizes[t.Name]; found {
+		if len(b) > size {
+			return nil, fmt.Errorf("%w: %d bytes for %s", ErrTrailingBytes, len(b), t.Name)
+		}
+
+		return decodeInteger(t.Name, b), nil
+	}
+
+	switch {
+	case t.Name == "BigUint":
+		return new(big.Int).SetBytes(b), nil
+	case t.Name == "BigInt":
+		return decodeSignedBigInt(b), nil
+	case t.Name == "bool":
+		return len(b) == 1 && b[0] == 1, nil
+	case bufferTypes[t.Name]:
+		return Bytes(b), nil
+	case stringTypes[t.Name]:
+		return string(b), nil
+	case t.Name == "List":
+		r := &reader{data: b}
+		values := make([]interface{}, 0)
+		for r.remaining() != 0 {
+			v, err := a.decodeNested(t.Arguments[0], r)
+			if err != nil {
+				return nil, err
+			}
+
+			values = append(values, v)
+		}
+
+		return values, nil
+	case t.Name == "Option":
+		if len(b) == 0 {
+			return nil, nil
+		}
+	default:
+		if d, found := a.Types[t.Name]; found && d.Type == "enum" && len(b) == 0 {
+			return a.decodeVariant(d, 0, &reader{})
+		}
+	}
+
+	r := &reader{data: b}
+
+	v, err := a.decodeNested(t, r)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.remaining() != 0 {
+		return nil, fmt.Errorf("%w: %d bytes for %s", ErrTrailingBytes, r.remaining(), t)
+	}
+
+	return v, nil
+}
+
+// decodeNested decodes a value followed by other values, which is encoded with length prefixes and fixed sizes.
+func (a *ABI) decodeNested(t *TypeExpression, r *reader) (interface{}, error) {
+	if size, found := integerSizes[t.Name]; found {
+		b, err := r.read(size)
+		if err != nil {
+			return nil, err
+		}
+
+		return decodeInteger(t.Name, b), nil
+	}
+
+	switch {
+	case t.Name == "BigUint" || t.Name == "BigInt" || bufferTypes[t.Name] || stringTypes[t.Name]:
+		b, err := r.readWithLength()
+		if err != nil {
+			return nil, err
+		}
+
+		return a.decodeTop(t, b)
+	case t.Name == "bool":
+		b, err := r.read(1)
+		if err != nil {
+			return nil, err
+		}
+
+		return b[0] == 1, nil
+	case t.Name == "Address":
+		b, err := r.read(addressLength)
+		if err != nil {
+			return nil, err
+		}
+
+		return bech32.Encode(addressHRP, b)
+	case t.Name == "H256":
+		b, err := r.read(32)
+		if err != nil {
+			return nil, err
+		}
+
+		return Bytes(b), nil
+	case t.Name == "CodeMetadata":
+		b, err := r.read(2)
+		if err != nil {
+			return nil, err
+		}
+
+		return Bytes(b), nil
+	case t.Name == "List":
+		count, err := r.readLength()
+		if err != nil {
+			return nil, err
+		}
+
+		return a.decodeItems(t.Arguments[0], count, r)
+	case isArrayType(t.Name):
+		count, _ := strconv.Atoi(strings.TrimPrefix(t.Name, "array"))
+		if t.Arguments[0].Name == "u8" {
+			b, err := r.read(count)
+			if err != nil {
+				return nil, err
+			}
+
+			return Bytes(b), nil
+		}
+
+		return a.decodeItems(t.Arguments[0], count, r)
+	case t.Name == "tuple" || t.Name == "multi":
+		values := make([]interface{}, len(t.Arguments))
+		for i, argument := range t.Arguments {
+			v, err := a.decodeNested(argument, r)
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = v
+		}
+
+		return values, nil
+	case t.Name == "Option":
+		b, err := r.read(1)
+		if err != nil {
+			return nil, err
+		}
+
+		if b[0] == 0 {
+			return nil, nil
+		}
+
+		return a.decodeNested(t.Arguments[0], r)
+	}
+
+	d, found := a.Types[t.Name]
+	if !found {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownType, t.Name)
+	}
+
+	if d.Type == "enum" {
+		b, err := r.read(1)
+		if err != nil {
+			return nil, err
+		}
+
+		return a.decodeVariant(d, int(b[0]), r)
+	}
+
+	return a.decodeFields(d.Fields, r)
+}
+
+// decodeItems decodes count nested items. The count of a list comes from the data: each item taking at least one byte,
+// a count beyond the bytes left is rejected before allocating anything for the items.
+func (a *ABI) decodeItems(t *TypeExpression, count int, r *reader) ([]interface{}, error) {
+	if count > r.remaining() {
+		return nil, fmt.Errorf("%w: %d items of %s in %d bytes", ErrUnexpectedEnd, count, t, r.remaining())
+	}
+
+	values := make([]interface{}, 0, count)
+	for i := 0; i < count; i++ {
+		v, err := a.decodeNested(t, r)
+		if err != nil {
+			return nil, err
+		}
+
+		values = append(values, v)
+	}
+
+	return values, nil
+}
+
+func (a *ABI) decodeFields(parameters []*Parameter, r *reader) (Struct, error) {
+	fields := make(Struct, 0, len(parameters))
+	for _, p := range parameters {
+		v, err := a.decodeNested(p.typeExpression, r)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", p.Name, err)
+		}
+
+		fields = append(fields, &Field{Name: p.Name, Type: p.Type, Value: v})
+	}
+
+	return fields, nil
+}
+
+func (a *ABI) decodeVariant(d *TypeDefinition, discriminant int, r *reader) (interface{}, error) {
+	for _, v := range d.Variants {
+		if v.Discriminant != discriminant {
+			continue
+		}
+
+		fields, err := a.decodeFields(v.Fields, r)
+		if err != nil {
+			return nil, err
+		}
+
+		return Enum{Name: v.Name, Discriminant: discriminant, Fields: fields}, nil
+	}
+
+	return nil, fmt.Errorf("%w: %d", ErrUnknownDiscriminant, discriminant)
+}
+
+func decodeInteger(name string, b []byte) interface{} {
+	if strings.HasPrefix(name, "u") {
+		return new(big.Int).SetBytes(b).Uint64()
+	}
+
+	return decodeSignedBigInt(b).Int64()
+}
+
+// decodeSignedBigInt decodes the two's complement big endian integer b.
+func decodeSignedBigInt(b []byte) *big.Int {
+	v := new(big.Int).SetBytes(b)
+	if len(b) != 0 && b[0]&0x80 != 0 {
+		v.Sub(v, new(big.Int).Lsh(big.NewInt(1), uint(len(b)*8)))
+	}
+
+	return v
+}
+
+type reader struct {
+	data   []byte
+	offset int
+}
+
+func (r *reader) remaining() int {
+	return len(r.data) - r.offset
+}
+
+func (r *reader) read(n int) ([]byte, error) {
+	if n < 0 || r.remaining() < n {
+		return nil, ErrUnexpectedEnd
+	}
+
+	r.offset += n
+
+	return r.data[r.offset-n : r.offset], nil
+}
+
+func (r *reader) readLength() (int, error) {
+	b, err := r.read(4)
+	if err != nil {
+		return 0, err
+	}
+
+	return int(binary.BigEndian.Uint32(b)), nil
+}
+
+func (r *reader) readWithLength() ([]byte, error) {
+	n, err := r.readLength()
+	if err != nil {
+		return nil, err
+	}
+
+	return r.read(n)
+}
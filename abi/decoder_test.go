@@ -0,0 +1,405 @@
+package abi
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thefabric-io/elrond-transaction-processor/bech32"
+)
+
+const listsABI = `{
+	"name": "lists",
+	"endpoints": [
+		{"name": "nested", "inputs": [{"name": "values", "type": "List<List<u8>>"}]},
+		{"name": "structs", "inputs": [{"name": "values", "type": "List<Pair>"}]}
+	],
+	"types": {
+		"Pair": {"type": "struct", "fields": [{"name": "a", "type": "u32"}, {"name": "b", "type": "BigUint"}]}
+	}
+}`
+
+func TestDecodeCallRejectsHostileLengthPrefix(t *testing.T) {
+	a, err := Parse([]byte(listsABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		endpoint string
+		argument []byte
+		err      error
+		items    int
+	}{
+		{name: "nested list", endpoint: "nested", argument: []byte{0x00, 0x00, 0x00, 0x02, 0x01, 0x02}, items: 1},
+		{name: "nested list with hostile length", endpoint: "nested", argument: []byte{0xff, 0xff, 0xff, 0xf0}, err: ErrUnexpectedEnd},
+		{name: "nested list longer than the data", endpoint: "nested", argument: []byte{0x00, 0x00, 0x00, 0x03, 0x01, 0x02}, err: ErrUnexpectedEnd},
+		{name: "structs with hostile length", endpoint: "structs", argument: []byte{0x00, 0x00, 0x00, 0x01, 0xff, 0xff, 0xff, 0xff}, err: ErrUnexpectedEnd},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call, err := a.DecodeCall(tt.endpoint, [][]byte{tt.argument})
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			values, ok := call.Arguments[0].Value.([]interface{})
+			if !ok || len(values) != tt.items {
+				t.Fatalf("expected %d items, got %#v", tt.items, call.Arguments[0].Value)
+			}
+		})
+	}
+}
+
+// pairABI describes endpoints and events shaped like the ones of the pair contracts of the mainnet.
+const pairABI = `{
+	"name": "pair",
+	"endpoints": [
+		{"name": "swapTokensFixedInput", "inputs": [{"name": "token_out", "type": "TokenIdentifier"}, {"name": "amount_out_min", "type": "BigUint"}]},
+		{"name": "transfer", "inputs": [{"name": "to", "type": "Address"}, {"name": "amount", "type": "BigUint"}]},
+		{"name": "setPosition", "inputs": [{"name": "position", "type": "Position"}]},
+		{"name": "setState", "inputs": [{"name": "state", "type": "State"}]},
+		{"name": "setTokens", "inputs": [{"name": "tokens", "type": "List<TokenIdentifier>"}]},
+		{"name": "setLimit", "inputs": [{"name": "limit", "type": "Option<BigUint>"}]},
+		{"name": "distribute", "inputs": [{"name": "payments", "type": "variadic<multi<Address,BigUint>>"}]},
+		{"name": "enter", "inputs": [{"name": "referrer", "type": "optional<Address>"}]},
+		{"name": "counted", "inputs": [{"name": "items", "type": "counted-variadic<u32>"}, {"name": "rest", "type": "variadic<bytes>"}]}
+	],
+	"events": [
+		{"identifier": "swap", "inputs": [
+			{"name": "caller", "type": "Address", "indexed": true},
+			{"name": "token_in", "type": "TokenIdentifier", "indexed": true},
+			{"name": "amount_in", "type": "BigUint"}
+		]},
+		{"identifier": "swapAmounts", "inputs": [
+			{"name": "caller", "type": "Address", "indexed": true},
+			{"name": "amount_in", "type": "BigUint"},
+			{"name": "amount_out", "type": "BigUint"}
+		]}
+	],
+	"types": {
+		"Position": {"type": "struct", "fields": [
+			{"name": "owner", "type": "Address"},
+			{"name": "token", "type": "TokenIdentifier"},
+			{"name": "amount", "type": "BigUint"},
+			{"name": "nonce", "type": "u64"}
+		]},
+		"State": {"type": "enum", "variants": [
+			{"name": "Inactive", "discriminant": 0},
+			{"name": "Active", "discriminant": 1},
+			{"name": "Paused", "discriminant": 2, "fields": [{"name": "until", "type": "u64"}]}
+		]}
+	}
+}`
+
+const (
+	testAddress = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	testEGLD    = "1000000000000000000"
+)
+
+// oneEGLD is 10^18, the top encoding of 1 EGLD.
+var oneEGLD = []byte{0x0d, 0xe0, 0xb6, 0xb3, 0xa7, 0x64, 0x00, 0x00}
+
+// nested prefixes b with its length, as buffers and big integers are encoded when followed by other values.
+func nested(b []byte) []byte {
+	return append([]byte{0, 0, 0, byte(len(b))}, b...)
+}
+
+func concat(values ...[]byte) []byte {
+	b := make([]byte, 0)
+	for _, v := range values {
+		b = append(b, v...)
+	}
+
+	return b
+}
+
+func testAddressKey(t *testing.T) []byte {
+	t.Helper()
+
+	_, key, err := bech32.Decode(testAddress)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return key
+}
+
+func TestDecodeCall(t *testing.T) {
+	a, err := Parse([]byte(pairABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address := testAddressKey(t)
+
+	tests := []struct {
+		name      string
+		endpoint  string
+		arguments [][]byte
+		expected  []string
+		err       error
+	}{
+		{
+			name:      "token identifier and big unsigned integer",
+			endpoint:  "swapTokensFixedInput",
+			arguments: [][]byte{[]byte("USDC-c76f1f"), {0x0f, 0x42, 0x40}},
+			expected:  []string{"USDC-c76f1f", "1000000"},
+		},
+		{
+			name:      "zero encoded as an empty argument",
+			endpoint:  "swapTokensFixedInput",
+			arguments: [][]byte{[]byte("USDC-c76f1f"), {}},
+			expected:  []string{"USDC-c76f1f", "0"},
+		},
+		{
+			name:      "address",
+			endpoint:  "transfer",
+			arguments: [][]byte{address, oneEGLD},
+			expected:  []string{testAddress, testEGLD},
+		},
+		{
+			name:      "address too short",
+			endpoint:  "transfer",
+			arguments: [][]byte{address[:31], oneEGLD},
+			err:       ErrUnexpectedEnd,
+		},
+		{
+			name:      "missing argument",
+			endpoint:  "transfer",
+			arguments: [][]byte{address},
+			err:       ErrMissingArgument,
+		},
+		{
+			name:      "too many arguments",
+			endpoint:  "transfer",
+			arguments: [][]byte{address, oneEGLD, {0x01}},
+			err:       ErrTooManyArguments,
+		},
+		{
+			name:      "struct",
+			endpoint:  "setPosition",
+			arguments: [][]byte{concat(address, nested([]byte("WEGLD-bd4d79")), nested(oneEGLD), []byte{0, 0, 0, 0, 0, 0, 0, 0x2a})},
+			expected:  []string{`[{"name":"owner","type":"Address","value":"` + testAddress + `"},{"name":"token","type":"TokenIdentifier","value":"WEGLD-bd4d79"},{"name":"amount","type":"BigUint","value":` + testEGLD + `},{"name":"nonce","type":"u64","value":42}]`},
+		},
+		{
+			name:      "struct with trailing bytes",
+			endpoint:  "setPosition",
+			arguments: [][]byte{concat(address, nested([]byte("WEGLD-bd4d79")), nested(oneEGLD), []byte{0, 0, 0, 0, 0, 0, 0, 0x2a, 0x00})},
+			err:       ErrTrailingBytes,
+		},
+		{
+			name:      "enum encoded as an empty argument",
+			endpoint:  "setState",
+			arguments: [][]byte{{}},
+			expected:  []string{"Inactive"},
+		},
+		{
+			name:      "enum",
+			endpoint:  "setState",
+			arguments: [][]byte{{0x01}},
+			expected:  []string{"Active"},
+		},
+		{
+			name:      "enum with fields",
+			endpoint:  "setState",
+			arguments: [][]byte{{0x02, 0, 0, 0, 0, 0x65, 0x3f, 0x4e, 0x00}},
+			expected:  []string{`{"name":"Paused","discriminant":2,"fields":[{"name":"until","type":"u64","value":1698647552}]}`},
+		},
+		{
+			name:      "enum with an unknown discriminant",
+			endpoint:  "setState",
+			arguments: [][]byte{{0x03}},
+			err:       ErrUnknownDiscriminant,
+		},
+		{
+			name:      "list",
+			endpoint:  "setTokens",
+			arguments: [][]byte{concat(nested([]byte("WEGLD-bd4d79")), nested([]byte("USDC-c76f1f")))},
+			expected:  []string{`["WEGLD-bd4d79","USDC-c76f1f"]`},
+		},
+		{
+			name:      "empty list",
+			endpoint:  "setTokens",
+			arguments: [][]byte{{}},
+			expected:  []string{`[]`},
+		},
+		{
+			name:      "option without value",
+			endpoint:  "setLimit",
+			arguments: [][]byte{{}},
+			expected:  []string{""},
+		},
+		{
+			name:      "option with value",
+			endpoint:  "setLimit",
+			arguments: [][]byte{concat([]byte{0x01}, nested(oneEGLD))},
+			expected:  []string{testEGLD},
+		},
+		{
+			name:      "variadic",
+			endpoint:  "distribute",
+			arguments: [][]byte{address, oneEGLD, address, {0x0f, 0x42, 0x40}},
+			expected:  []string{`[["` + testAddress + `",` + testEGLD + `],["` + testAddress + `",1000000]]`},
+		},
+		{
+			name:      "variadic without values",
+			endpoint:  "distribute",
+			arguments: [][]byte{},
+			expected:  []string{`[]`},
+		},
+		{
+			name:      "variadic missing part of a value",
+			endpoint:  "distribute",
+			arguments: [][]byte{address, oneEGLD, address},
+			err:       ErrMissingArgument,
+		},
+		{
+			name:      "optional with value",
+			endpoint:  "enter",
+			arguments: [][]byte{address},
+			expected:  []string{testAddress},
+		},
+		{
+			name:      "optional without value",
+			endpoint:  "enter",
+			arguments: [][]byte{},
+			expected:  []string{""},
+		},
+		{
+			name:      "counted variadic followed by a variadic",
+			endpoint:  "counted",
+			arguments: [][]byte{{0x02}, {0x01}, {0x02}, {0xff}},
+			expected:  []string{`[1,2]`, `["ff"]`},
+		},
+		{
+			name:      "counted variadic without values",
+			endpoint:  "counted",
+			arguments: [][]byte{{}},
+			expected:  []string{`[]`, `[]`},
+		},
+		{
+			name:      "counted variadic with more values than arguments",
+			endpoint:  "counted",
+			arguments: [][]byte{{0x03}, {0x01}, {0x02}},
+			err:       ErrMissingArgument,
+		},
+		{
+			name:      "counted variadic with a hostile count",
+			endpoint:  "counted",
+			arguments: [][]byte{{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+			err:       ErrMissingArgument,
+		},
+		{
+			name:     "unknown endpoint",
+			endpoint: "swapTokensFixedOutput",
+			err:      ErrUnknownEndpoint,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			call, err := a.DecodeCall(tt.endpoint, tt.arguments)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected the error %v, got %v", tt.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if call.Endpoint != tt.endpoint || len(call.Arguments) != len(tt.expected) {
+				t.Fatalf("expected %d arguments of %s, got %d of %s", len(tt.expected), tt.endpoint, len(call.Arguments), call.Endpoint)
+			}
+
+			for i, f := range call.Arguments {
+				if v := FormatValue(f.Value); v != tt.expected[i] {
+					t.Fatalf("expected %s to be %s, got %s", f.Name, tt.expected[i], v)
+				}
+			}
+		})
+	}
+}
+
+func TestDecodeEvent(t *testing.T) {
+	a, err := Parse([]byte(pairABI))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	address := testAddressKey(t)
+
+	tests := []struct {
+		name     string
+		topics   [][]byte
+		data     []byte
+		expected []string
+		err      error
+	}{
+		{
+			name:     "topics and a top encoded input in the data",
+			topics:   [][]byte{[]byte("swap"), address, []byte("WEGLD-bd4d79")},
+			data:     oneEGLD,
+			expected: []string{testAddress, "WEGLD-bd4d79", testEGLD},
+		},
+		{
+			name:     "nested encoded inputs in the data",
+			topics:   [][]byte{[]byte("swapAmounts"), address},
+			data:     concat(nested(oneEGLD), nested([]byte{0x0f, 0x42, 0x40})),
+			expected: []string{testAddress, testEGLD, "1000000"},
+		},
+		{
+			name:   "data shorter than its inputs",
+			topics: [][]byte{[]byte("swapAmounts"), address},
+			data:   nested(oneEGLD),
+			err:    ErrUnexpectedEnd,
+		},
+		{
+			name:   "missing topic",
+			topics: [][]byte{[]byte("swap"), address},
+			data:   oneEGLD,
+			err:    ErrMissingArgument,
+		},
+		{
+			name:   "unknown event",
+			topics: [][]byte{[]byte("addLiquidity"), address},
+			err:    ErrUnknownEvent,
+		},
+		{
+			name: "no topic",
+			err:  ErrUnknownEvent,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e, err := a.DecodeEvent(tt.topics, tt.data)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected the error %v, got %v", tt.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if e.Identifier != string(tt.topics[0]) || len(e.Fields) != len(tt.expected) {
+				t.Fatalf("expected %d fields of %s, got %d of %s", len(tt.expected), tt.topics[0], len(e.Fields), e.Identifier)
+			}
+
+			for i, f := range e.Fields {
+				if v := FormatValue(f.Value); v != tt.expected[i] {
+					t.Fatalf("expected %s to be %s, got %s", f.Name, tt.expected[i], v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,88 @@
+package abi
+
+import (
+	"fmt"
+	"strings"
+)
+
+// TypeExpression is a parsed ABI type, such as "BigUint", "List<Address>" or "variadic<multi<TokenIdentifier,u64>>".
+type TypeExpression struct {
+	Name      string
+	Arguments []*TypeExpression
+}
+
+func (t *TypeExpression) String() string {
+	if len(t.Arguments) == 0 {
+		return t.Name
+	}
+
+	arguments := make([]string, len(t.Arguments))
+	for i, a := range t.Arguments {
+		arguments[i] = a.String()
+	}
+
+	return fmt.Sprintf("%s<%s>", t.Name, strings.Join(arguments, ","))
+}
+
+// isMultiValue reports whether values of the type span several arguments, or none, at the top level.
+func (t *TypeExpression) isMultiValue() bool {
+	switch t.Name {
+	case "variadic", "optional", "multi", "counted-variadic":
+		return true
+	}
+
+	return false
+}
+
+// ParseType parses an ABI type expression.
+func ParseType(s string) (*TypeExpression, error) {
+	t, rest, err := parseType(strings.TrimSpace(s))
+	if err != nil {
+		return nil, err
+	}
+
+	if rest != "" {
+		return nil, fmt.Errorf("%w: unexpected %q in %q", ErrInvalidType, rest, s)
+	}
+
+	return t, nil
+}
+
+func parseType(s string) (*TypeExpression, string, error) {
+	end := strings.IndexAny(s, "<>,")
+	if end == -1 {
+		end = len(s)
+	}
+
+	name := strings.TrimSpace(s[:end])
+	if name == "" {
+		return nil, "", fmt.Errorf("%w: missing type name in %q", ErrInvalidType, s)
+	}
+
+	t := &TypeExpression{Name: name}
+	rest := s[end:]
+
+	if !strings.HasPrefix(rest, "<") {
+		return t, rest, nil
+	}
+
+	rest = rest[1:]
+	for {
+		argument, r, err := parseType(strings.TrimSpace(rest))
+		if err != nil {
+			return nil, "", err
+		}
+
+		t.Arguments = append(t.Arguments, argument)
+		rest = strings.TrimSpace(r)
+
+		switch {
+		case strings.HasPrefix(rest, ","):
+			rest = rest[1:]
+		case strings.HasPrefix(rest, ">"):
+			return t, rest[1:], nil
+		default:
+			return nil, "", fmt.Errorf("%w: unterminated type arguments in %q", ErrInvalidType, s)
+		}
+	}
+}
@@ -0,0 +1,105 @@
+package abi
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strconv"
+)
+
+// A decoded value is one of:
+//   - *big.Int for BigUint and BigInt,
+//   - uint64 and int64 for the other integers,
+//   - bool,
+//   - string for addresses, in bech32, token identifiers and strings,
+//   - Bytes for bytes and buffers,
+//   - []interface{} for lists, arrays, tuples and multi-values,
+//   - nil or the value for options,
+//   - Struct for structs and Enum for enums.
+
+// Bytes is a decoded buffer, encoded as hex in JSON.
+type Bytes []byte
+
+func (b Bytes) MarshalJSON() ([]byte, error) {
+	return json.Marshal(hex.EncodeToString(b))
+}
+
+// Field is a named decoded value.
+type Field struct {
+	Name  string      `json:"name"`
+	Type  string      `json:"type"`
+	Value interface{} `json:"value"`
+}
+
+type Struct []*Field
+
+// Get returns the value of the field of the given name.
+func (s Struct) Get(name string) (interface{}, bool) {
+	for _, f := range s {
+		if f.Name == name {
+			return f.Value, true
+		}
+	}
+
+	return nil, false
+}
+
+// GetString returns the value of the field of the given name formatted by FormatValue.
+func (s Struct) GetString(name string) (string, bool) {
+	v, found := s.Get(name)
+	if !found {
+		return "", false
+	}
+
+	return FormatValue(v), true
+}
+
+type Enum struct {
+	Name         string `json:"name"`
+	Discriminant int    `json:"discriminant"`
+	Fields       Struct `json:"fields,omitempty"`
+}
+
+// Call is the decoded call of an endpoint.
+type Call struct {
+	Endpoint  string `json:"endpoint"`
+	Arguments Struct `json:"arguments"`
+}
+
+// Event is a decoded event.
+type Event struct {
+	Identifier string `json:"identifier"`
+	Fields     Struct `json:"fields"`
+}
+
+// FormatValue returns the text form of a decoded value: integers in decimal, buffers in hex and composite values in
+// JSON.
+func FormatValue(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case *big.Int:
+		return value.String()
+	case uint64:
+		return strconv.FormatUint(value, 10)
+	case int64:
+		return strconv.FormatInt(value, 10)
+	case bool:
+		return strconv.FormatBool(value)
+	case string:
+		return value
+	case Bytes:
+		return hex.EncodeToString(value)
+	case Enum:
+		if len(value.Fields) == 0 {
+			return value.Name
+		}
+	}
+
+	b, err := json.Marshal(v)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
@@ -0,0 +1,158 @@
+// Package bech32 encodes and decodes the bech32 strings used for addresses, as specified by BIP 173.
+package bech32
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+var (
+	ErrInvalidLength    = errors.New("invalid bech32 string length")
+	ErrMixedCase        = errors.New("bech32 string mixes upper and lower case")
+	ErrMissingSeparator = errors.New("bech32 string has no separator")
+	ErrInvalidCharacter = errors.New("invalid bech32 character")
+	ErrInvalidChecksum  = errors.New("invalid bech32 checksum")
+	ErrInvalidPadding   = errors.New("invalid padding")
+)
+
+const charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+var generator = [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+
+// Encode encodes data, a slice of bytes, under the human readable part hrp.
+func Encode(hrp string, data []byte) (string, error) {
+	values, err := ConvertBits(data, 8, 5, true)
+	if err != nil {
+		return "", err
+	}
+
+	hrp = strings.ToLower(hrp)
+	checksum := createChecksum(hrp, values)
+
+	var sb strings.Builder
+	sb.Grow(len(hrp) + 1 + len(values) + len(checksum))
+	sb.WriteString(hrp)
+	sb.WriteByte('1')
+
+	for _, v := range append(values, checksum...) {
+		sb.WriteByte(charset[v])
+	}
+
+	return sb.String(), nil
+}
+
+// Decode returns the human readable part and the bytes encoded in s.
+func Decode(s string) (string, []byte, error) {
+	if len(s) < 8 || len(s) > 90 {
+		return "", nil, ErrInvalidLength
+	}
+
+	lower := strings.ToLower(s)
+	if lower != s && strings.ToUpper(s) != s {
+		return "", nil, ErrMixedCase
+	}
+
+	separator := strings.LastIndexByte(lower, '1')
+	if separator < 1 || separator+7 > len(lower) {
+		return "", nil, ErrMissingSeparator
+	}
+
+	hrp := lower[:separator]
+
+	values := make([]byte, 0, len(lower)-separator-1)
+	for _, c := range lower[separator+1:] {
+		i := strings.IndexRune(charset, c)
+		if i < 0 {
+			return "", nil, fmt.Errorf("%w %q", ErrInvalidCharacter, c)
+		}
+
+		values = append(values, byte(i))
+	}
+
+	if polymod(append(expandHRP(hrp), values...)) != 1 {
+		return "", nil, ErrInvalidChecksum
+	}
+
+	data, err := ConvertBits(values[:len(values)-6], 5, 8, false)
+	if err != nil {
+		return "", nil, err
+	}
+
+	return hrp, data, nil
+}
+
+// ConvertBits regroups data made of fromBits-bit values into toBits-bit values. When pad is false, the input must not
+// leave any incomplete group.
+func ConvertBits(data []byte, fromBits, toBits uint, pad bool) ([]byte, error) {
+	var acc uint32
+	var bits uint
+
+	maxValue := uint32(1)<<toBits - 1
+	result := make([]byte, 0, len(data)*int(fromBits)/int(toBits)+1)
+
+	for _, b := range data {
+		if uint32(b)>>fromBits != 0 {
+			return nil, ErrInvalidCharacter
+		}
+
+		acc = acc<<fromBits | uint32(b)
+		bits += fromBits
+
+		for bits >= toBits {
+			bits -= toBits
+			result = append(result, byte(acc>>bits&maxValue))
+		}
+	}
+
+	if pad {
+		if bits > 0 {
+			result = append(result, byte(acc<<(toBits-bits)&maxValue))
+		}
+	} else if bits >= fromBits || acc<<(toBits-bits)&maxValue != 0 {
+		return nil, ErrInvalidPadding
+	}
+
+	return result, nil
+}
+
+func polymod(values []byte) uint32 {
+	checksum := uint32(1)
+	for _, v := range values {
+		top := checksum >> 25
+		checksum = (checksum&0x1ffffff)<<5 ^ uint32(v)
+
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				checksum ^= generator[i]
+			}
+		}
+	}
+
+	return checksum
+}
+
+func expandHRP(hrp string) []byte {
+	expanded := make([]byte, 0, len(hrp)*2+1)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]>>5)
+	}
+
+	expanded = append(expanded, 0)
+	for i := 0; i < len(hrp); i++ {
+		expanded = append(expanded, hrp[i]&31)
+	}
+
+	return expanded
+}
+
+func createChecksum(hrp string, values []byte) []byte {
+	polymodValue := polymod(append(append(expandHRP(hrp), values...), 0, 0, 0, 0, 0, 0)) ^ 1
+
+	checksum := make([]byte, 6)
+	for i := range checksum {
+		checksum[i] = byte(polymodValue >> uint(5*(5-i)) & 31)
+	}
+
+	return checksum
+}
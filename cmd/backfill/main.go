@@ -0,0 +1,160 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	"github.com/thefabric-io/elrond-transaction-processor/redisstate"
+)
+
+/*
+	Processes a bounded range of blocks, given either as per-shard nonces or as a time window.
+
+	e.g. go run ./cmd/backfill -from 0=100,1=100,metachain=100 -to 0=200,1=200,metachain=200
+	     go run ./cmd/backfill -since 2022-03-01T00:00:00Z -until 2022-03-02T00:00:00Z -chunks 4 -chunk 0
+
+	When -redis is set, progress is persisted under the -state-key prefix so that an interrupted job resumes where it
+	stopped without touching the cursor of a live processor.
+*/
+
+func main() {
+	var (
+		gatewayURL = flag.String("gateway", elrondgateway.TestNetGatewayURL, "URL of the Elrond gateway")
+		redisURI   = flag.String("redis", "", "URI of the redis server used to persist progress (in-memory when empty)")
+		stateKey   = flag.String("state-key", "backfill:", "key prefix of the backfill state in redis")
+		from       = flag.String("from", "", "first nonce to process per shard (e.g. 0=100,1=100,metachain=100)")
+		to         = flag.String("to", "", "last nonce to process per shard (e.g. 0=200,1=200,metachain=200)")
+		since      = flag.String("since", "", "start of the time window (RFC3339), used when -from is empty")
+		until      = flag.String("until", "", "end of the time window (RFC3339, exclusive), used when -to is empty")
+		chunks     = flag.Int("chunks", 1, "number of chunks the range is split into")
+		chunk      = flag.Int("chunk", 0, "index of the chunk processed by this worker")
+	)
+	flag.Parse()
+
+	gateway := elrondgateway.NewClient(*gatewayURL)
+
+	nonceRange, err := resolveRange(gateway, *from, *to, *since, *until)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	nonceRange, err = nonceRange.Chunk(*chunk, *chunks)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	var stateStorage processor.StateStorage = processor.NewInMemoryStateStorage()
+	if *redisURI != "" {
+		redisOptions, err := redis.ParseURL(*redisURI)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		stateStorage = redisstate.NewStateStorage(redis.NewClient(redisOptions), fmt.Sprintf("%s%d:%d:", *stateKey, *chunk, *chunks))
+	}
+
+	opts := processor.Options{}
+	proc, err := processor.NewProcessor(
+		opts.DataSource(gateway),
+		opts.StateStorage(stateStorage),
+		opts.Range(nonceRange),
+		opts.PastTransactionBufferPerShard(0),
+		opts.NotifyEmptyBlocks(false),
+		opts.OnTransactionsReceived(func(shard processor.Shard, nonce processor.Nonce, transactions []*processor.Transaction, blockHash string) {
+			log.Printf("%d transaction(s) received from %s with nonce %d\n", len(transactions), shard.Name(), nonce)
+		}),
+		opts.OnRangeCompleted(func(report processor.RangeReport) {
+			log.Printf("chunk %d/%d completed: %d block(s), %d transaction(s)\n", *chunk+1, *chunks, report.ProcessedBlocks, report.ProcessedTransactions)
+		}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	if err = proc.Start(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func resolveRange(dataSource processor.DataSource, from, to, since, until string) (*processor.NonceRange, error) {
+	if from != "" && to != "" {
+		fromNonces, err := parseNonces(from)
+		if err != nil {
+			return nil, err
+		}
+
+		toNonces, err := parseNonces(to)
+		if err != nil {
+			return nil, err
+		}
+
+		return processor.NewNonceRange(fromNonces, toNonces)
+	}
+
+	if since == "" || until == "" {
+		return nil, errors.New("either -from and -to or -since and -until must be provided")
+	}
+
+	sinceTime, err := time.Parse(time.RFC3339, since)
+	if err != nil {
+		return nil, err
+	}
+
+	untilTime, err := time.Parse(time.RFC3339, until)
+	if err != nil {
+		return nil, err
+	}
+
+	shards, err := dataSource.GetShards()
+	if err != nil {
+		return nil, err
+	}
+
+	return processor.NewNonceResolver(dataSource).RangeForWindow(shards, sinceTime, untilTime)
+}
+
+func parseNonces(s string) (processor.NonceByShard, error) {
+	result := processor.NonceByShard{}
+
+	for _, pair := range strings.Split(s, ",") {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid shard nonce %q, expected <shard>=<nonce>", pair)
+		}
+
+		shard, err := parseShard(parts[0])
+		if err != nil {
+			return nil, err
+		}
+
+		nonce, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return nil, fmt.Errorf("invalid nonce %q: %w", parts[1], err)
+		}
+
+		result.PutNonce(shard, processor.Nonce(nonce))
+	}
+
+	return result, nil
+}
+
+func parseShard(s string) (processor.Shard, error) {
+	if strings.EqualFold(s, "metachain") {
+		return processor.ShardMetachain, nil
+	}
+
+	shard, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid shard %q: %w", s, err)
+	}
+
+	return processor.Shard(shard), nil
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+/*
+	Runs a cluster of in-process workers sharing the shards of the testnet. Workers join one after the other and the
+	first one leaves after a while, so that shards get reassigned while blocks keep being emitted once.
+
+	A real deployment runs one worker per process and shares the membership, coordinator, state storage and cross-shard
+	store through redis (see the rediscoordinator and redisstate packages).
+*/
+
+const (
+	numberOfWorkers = 3
+	runInterval     = 5 * time.Second
+)
+
+func main() {
+	gateway := elrondgateway.NewClient(elrondgateway.TestNetGatewayURL)
+
+	shards, err := gateway.GetShards()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	currentNonces, err := gateway.GetCurrentNoncesForShards(shards)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	stateStorage := processor.NewInMemoryStateStorage()
+	if err := stateStorage.PersistLastState(shards, processor.NewState(processor.NewCrossShardDictionary(), currentNonces, nil)); err != nil {
+		log.Fatal(err)
+	}
+
+	var (
+		membership      = processor.NewInMemoryMembership()
+		coordinator     = processor.NewInMemoryCoordinator()
+		crossShardStore = processor.NewInMemoryCrossShardStore()
+		emitted         = map[string]string{}
+		emittedMu       sync.Mutex
+	)
+
+	newWorker := func(workerID string) *processor.ClusterWorker {
+		opts := processor.Options{}
+
+		worker, err := processor.NewClusterWorker(membership,
+			opts.DataSource(gateway),
+			opts.StateStorage(stateStorage),
+			opts.CrossShardStore(crossShardStore),
+			opts.Coordinator(coordinator, "cluster-example", workerID),
+			opts.LeaseTTL(3*runInterval),
+			opts.OnTransactionsReceived(func(shard processor.Shard, nonce processor.Nonce, transactions []*processor.Transaction, blockHash string) {
+				emittedMu.Lock()
+				defer emittedMu.Unlock()
+
+				key := fmt.Sprintf("%d/%d", shard, nonce)
+				if previous, found := emitted[key]; found {
+					log.Printf("block %s emitted twice, by %s and %s\n", key, previous, workerID)
+				}
+
+				emitted[key] = workerID
+				log.Printf("%s: %d transaction(s) in %s at nonce %d\n", workerID, len(transactions), shard.Name(), nonce)
+			}),
+		)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		return worker
+	}
+
+	var (
+		wg    sync.WaitGroup
+		stops []chan struct{}
+	)
+
+	for i := 0; i < numberOfWorkers; i++ {
+		stop := make(chan struct{})
+		stops = append(stops, stop)
+
+		worker := newWorker(fmt.Sprintf("worker-%d", i))
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if err := worker.Run(runInterval, stop); err != nil {
+				log.Println(err)
+			}
+		}()
+
+		time.Sleep(2 * runInterval)
+	}
+
+	time.Sleep(4 * runInterval)
+	close(stops[0])
+
+	time.Sleep(4 * runInterval)
+	for _, stop := range stops[1:] {
+		close(stop)
+	}
+
+	wg.Wait()
+}
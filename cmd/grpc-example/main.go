@@ -0,0 +1,64 @@
+package main
+
+import (
+	"log"
+	"net"
+	"os"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
+	"github.com/thefabric-io/elrond-transaction-processor/grpcstream"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	"google.golang.org/grpc"
+)
+
+/*
+	Serves the blocks of the testnet on a BlockStream gRPC service, see grpcstream/pb/stream.proto for the schema.
+	Clients subscribe with the last nonce they received for each shard and get the buffered blocks after it before the
+	live ones.
+
+	The state is kept in memory, so the processor starts with the blocks of the last ten minutes on each run.
+*/
+
+func main() {
+	address := os.Getenv("GRPC_ADDRESS")
+	if address == "" {
+		address = ":9090"
+	}
+
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	so := grpcstream.Options{}
+	stream := grpcstream.NewServer(so.HistorySize(2000))
+
+	grpcServer := grpc.NewServer()
+	stream.Register(grpcServer)
+
+	go func() {
+		if err := grpcServer.Serve(listener); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	gateway := elrondgateway.NewClient(elrondgateway.TestNetGatewayURL)
+
+	opts := processor.Options{}
+	proc, err := processor.NewProcessor(
+		opts.DataSource(gateway),
+		opts.StateStorage(processor.NewInMemoryStateStorage()),
+		opts.StartFrom(processor.StartFromTimestamp(time.Now().Add(-10*time.Minute))),
+		opts.Sinks(stream),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer proc.Close()
+
+	if err = proc.Start(); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+/*
+	Prints the transactions of the testnet notarized by the metachain over the last ten minutes, in the order of the
+	hyperblocks, along with the shards they went through.
+*/
+
+func main() {
+	gateway := elrondgateway.NewHyperblockClient(elrondgateway.TestNetGatewayURL)
+
+	opts := processor.Options{}
+	proc, err := processor.NewProcessor(
+		opts.DataSource(gateway),
+		opts.StateStorage(processor.NewInMemoryStateStorage()),
+		opts.StartFrom(processor.StartFromTimestamp(time.Now().Add(-10*time.Minute))),
+		opts.Hyperblocks(),
+		opts.NotifyEmptyBlocks(false),
+		opts.OnTransactionsReceived(func(shard processor.Shard, nonce processor.Nonce, transactions []*processor.Transaction, blockHash string) {
+			for _, tx := range transactions {
+				log.Printf("hyperblock %d: %s %s -> %s (%s)\n", nonce, tx.Hash(), tx.SourceShard().Name(), tx.DestinationShard().Name(), tx.Status())
+			}
+		}),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer proc.Close()
+
+	if err = proc.Start(); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
+	"github.com/thefabric-io/elrond-transaction-processor/kafkasink"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+/*
+	Publishes the transactions of the testnet to a local broker, keyed by sender so that the transactions of an account
+	stay ordered. Smart contract results and rewards are routed to their own topics.
+
+	The state is kept in memory, so the processor starts with the blocks of the last ten minutes on each run.
+*/
+
+func main() {
+	brokers := os.Getenv("KAFKA_BROKERS")
+	if brokers == "" {
+		brokers = "localhost:9092"
+	}
+
+	ko := kafkasink.Options{}
+	kafkaSink, err := kafkasink.New(strings.Split(brokers, ","),
+		ko.Topic("elrond.transactions"),
+		ko.TopicForKind(processor.KindSmartContractResult, "elrond.smart-contract-results"),
+		ko.TopicForKind(processor.KindReward, "elrond.rewards"),
+		ko.KeyBy(kafkasink.KeyBySender),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	gateway := elrondgateway.NewClient(elrondgateway.TestNetGatewayURL)
+
+	opts := processor.Options{}
+	proc, err := processor.NewProcessor(
+		opts.DataSource(gateway),
+		opts.StateStorage(processor.NewInMemoryStateStorage()),
+		opts.StartFrom(processor.StartFromTimestamp(time.Now().Add(-10*time.Minute))),
+		opts.Sinks(kafkaSink),
+		opts.NotifyEmptyBlocks(false),
+		opts.DisplayProgressBar(),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer proc.Close()
+
+	if err = proc.Start(); err != nil {
+		log.Println(err)
+	}
+}
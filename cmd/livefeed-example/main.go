@@ -0,0 +1,65 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
+	"github.com/thefabric-io/elrond-transaction-processor/livefeed"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+/*
+	Streams the transactions of the testnet on /feed, for instance:
+
+		curl -N "localhost:8080/feed?address=erd1...&minValue=1000000000000000000"
+
+	or with a WebSocket client on ws://localhost:8080/feed, which may send a filter such as {"function": "ESDTTransfer"}
+	at any time to replace the one given in the query.
+*/
+
+func main() {
+	address := os.Getenv("HTTP_ADDRESS")
+	if address == "" {
+		address = ":8080"
+	}
+
+	lo := livefeed.Options{}
+	feed := livefeed.NewServer(
+		lo.HeartbeatInterval(15*time.Second),
+		lo.OnSlowClient(livefeed.DropEvents),
+		lo.CheckOrigin(func(r *http.Request) bool { return true }),
+	)
+	defer feed.Close()
+
+	mux := http.NewServeMux()
+	mux.Handle("/feed", feed)
+
+	go func() {
+		if err := http.ListenAndServe(address, mux); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	gateway := elrondgateway.NewClient(elrondgateway.TestNetGatewayURL)
+
+	opts := processor.Options{}
+	proc, err := processor.NewProcessor(
+		opts.DataSource(gateway),
+		opts.StateStorage(processor.NewInMemoryStateStorage()),
+		opts.StartFrom(processor.StartFromTimestamp(time.Now().Add(-time.Minute))),
+		opts.OnTransactionsReceived(feed.OnTransactionsReceived),
+		opts.NotifyEmptyBlocks(false),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer proc.Close()
+
+	if err = proc.Start(); err != nil {
+		log.Println(err)
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
+	"github.com/thefabric-io/elrond-transaction-processor/nftindex"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+/*
+	Follows the non-fungible and semi-fungible tokens of the testnet created during the last hour and serves the query
+	API, for instance:
+
+		curl "localhost:8080/collections/COLL-a1b2c3/owners"
+
+	COLLECTIONS restricts the index to a comma separated list of collections. The position of the processor is kept in
+	memory, so the last hour is applied again on each run: the snapshot of the index is removed first.
+*/
+
+func main() {
+	path := os.Getenv("INDEX_PATH")
+	if path == "" {
+		path = "tokens.json"
+	}
+
+	address := os.Getenv("HTTP_ADDRESS")
+	if address == "" {
+		address = ":8080"
+	}
+
+	_ = os.Remove(path)
+
+	ixo := nftindex.Options{}
+	indexOptions := []nftindex.Option{
+		ixo.OnOperation(func(o *nftindex.Operation) {
+			log.Printf("%s %s x%s from %q to %q (reverted: %t)\n", o.Kind, o.Identifier(), o.Quantity, o.From, o.To, o.Reverted)
+		}),
+	}
+
+	if collections := os.Getenv("COLLECTIONS"); collections != "" {
+		indexOptions = append(indexOptions, ixo.Collections(strings.Split(collections, ",")...))
+	}
+
+	index, err := nftindex.Open(path, indexOptions...)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		if err := http.ListenAndServe(address, nftindex.NewHandler(index)); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	gateway := elrondgateway.NewClient(elrondgateway.TestNetGatewayURL)
+
+	opts := processor.Options{}
+	proc, err := processor.NewProcessor(
+		opts.DataSource(gateway),
+		opts.StateStorage(processor.NewInMemoryStateStorage()),
+		opts.StartFrom(processor.StartFromTimestamp(time.Now().Add(-time.Hour))),
+		opts.Sinks(index),
+		opts.NotifyEmptyBlocks(false),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer proc.Close()
+
+	if err = proc.Start(); err != nil {
+		log.Println(err)
+	}
+}
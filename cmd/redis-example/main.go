@@ -2,12 +2,20 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"log"
 	"os"
+	"strconv"
+	"time"
 
+	"github.com/go-redis/redis/v8"
 	"github.com/joho/godotenv"
 	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
 	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	"github.com/thefabric-io/elrond-transaction-processor/rediscoordinator"
+	"github.com/thefabric-io/elrond-transaction-processor/redisstate"
+	"github.com/thefabric-io/elrond-transaction-processor/redisstream"
+	"github.com/thefabric-io/elrond-transaction-processor/sink"
 )
 
 func onTransactionReceivedFunc(shard processor.Shard, nonce processor.Nonce, transactions []*processor.Transaction, blockHash string) {
@@ -19,11 +27,59 @@ func onTransactionReceivedFunc(shard processor.Shard, nonce processor.Nonce, tra
 	/*
 		TODO:
 			Filter transactions to be processed (e.g. by contract(s))
-			Send shard, nonce and transactions to a message broker (e.g. Kafka)
 			Consume message and persist for later querying (e.g. relational/document database, elasticsearch)
+
+		Delivery of blocks to a file, a webhook or a redis stream is handled by sinks, see sinksFromEnv.
 	*/
 }
 
+func startPositionFromEnv() (*processor.StartPosition, error) {
+	if v := os.Getenv("START_FROM_TIMESTAMP"); v != "" {
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, err
+		}
+
+		return processor.StartFromTimestamp(t), nil
+	}
+
+	if v := os.Getenv("START_FROM_EPOCH"); v != "" {
+		epoch, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+
+		return processor.StartFromEpoch(epoch), nil
+	}
+
+	return nil, nil
+}
+
+func sinksFromEnv(redisClient *redis.Client) ([]processor.Sink, error) {
+	sinks := make([]processor.Sink, 0)
+	so := sink.Options{}
+
+	if dir := os.Getenv("SINK_JSONL_DIR"); dir != "" {
+		s, err := sink.NewJSONLFileSink(dir, "blocks", 64<<20)
+		if err != nil {
+			return nil, err
+		}
+
+		sinks = append(sinks, s)
+	}
+
+	if url := os.Getenv("SINK_WEBHOOK_URL"); url != "" {
+		sinks = append(sinks, sink.NewWebhookSink(url, os.Getenv("SINK_WEBHOOK_SECRET"), so.FlushInterval(5*time.Second)))
+	}
+
+	if stream := os.Getenv("SINK_REDIS_STREAM"); stream != "" {
+		rso := redisstream.Options{}
+		sinks = append(sinks, redisstream.NewSink(redisClient, stream, rso.MaxLen(100000)))
+	}
+
+	return sinks, nil
+}
+
 func main() {
 	_ = godotenv.Load(".env")
 
@@ -46,10 +102,33 @@ func main() {
 		Please make sure your processor state repository persists the cross shard transactions dictionary if
 		IncludeCrossShardStartedTransactions is true.
 	*/
-	stateStorage := NewProcessorStateStorage(redisServerURI)
+	redisOptions, err := redis.ParseURL(redisServerURI)
+	if err != nil {
+		panic(err)
+	}
+
+	redisClient := redis.NewClient(redisOptions)
+	stateStorage := redisstate.NewStateStorage(redisClient, "")
 
 	opts := processor.Options{}
-	proc, err := processor.NewProcessor(
+
+	/*
+		When the state storage is empty, the processor can resolve its first nonces from a date (RFC3339) or an epoch
+		instead of requiring them to be written by hand.
+	*/
+	startPosition, err := startPositionFromEnv()
+	if err != nil {
+		panic(err)
+	}
+
+	sinks, err := sinksFromEnv(redisClient)
+	if err != nil {
+		panic(err)
+	}
+
+	options := []processor.Option{
+		opts.Sinks(sinks...),
+		opts.StartFrom(startPosition),
 		opts.DataSource(elrondGateway),
 		opts.StateStorage(stateStorage),
 		opts.OnTransactionsReceived(onTransactionReceivedFunc),
@@ -58,11 +137,27 @@ func main() {
 		opts.WaitForFinalizedCrossShardSmartContractResults(false),
 		opts.Verbose(),
 		opts.DisplayProgressBar(),
-	)
+	}
+
+	/*
+		When several replicas share the same PROCESSOR_ID, only the one holding the lease processes blocks, the others
+		return processor.ErrLeaseNotAcquired until the lease expires or is released.
+	*/
+	if processorID := os.Getenv("PROCESSOR_ID"); processorID != "" {
+		hostname, _ := os.Hostname()
+		instanceID := fmt.Sprintf("%s-%d", hostname, os.Getpid())
+
+		options = append(options, opts.Coordinator(rediscoordinator.NewCoordinator(redisClient, ""), processorID, instanceID))
+	}
+
+	proc, err := processor.NewProcessor(options...)
 	if err != nil {
 		panic(err)
 	}
 
+	defer proc.ReleaseLeases()
+	defer proc.Close()
+
 	if err = proc.Start(); err != nil {
 		log.Println(err)
 	}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"log"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	"github.com/thefabric-io/elrond-transaction-processor/staking"
+)
+
+/*
+	Prints the delegation and staking operations of the testnet over the last hour, once their result came back from
+	the metachain.
+*/
+
+func main() {
+	decoder := staking.NewDecoder(func(e *staking.Event) {
+		if !e.Confirmed {
+			log.Printf("%s by %s with %s: %s (unconfirmed)\n", e.Kind, e.Caller, e.Provider, e.Amount)
+
+			return
+		}
+
+		log.Printf("%s by %s with %s: %s (%s)\n", e.Kind, e.Caller, e.Provider, e.Amount, e.Outcome)
+	})
+
+	gateway := elrondgateway.NewClient(elrondgateway.TestNetGatewayURL)
+
+	opts := processor.Options{}
+	proc, err := processor.NewProcessor(
+		opts.DataSource(gateway),
+		opts.StateStorage(processor.NewInMemoryStateStorage()),
+		opts.StartFrom(processor.StartFromTimestamp(time.Now().Add(-time.Hour))),
+		opts.Sinks(decoder),
+		opts.NotifyEmptyBlocks(false),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer proc.Close()
+
+	if err = proc.Start(); err != nil {
+		log.Println(err)
+	}
+}
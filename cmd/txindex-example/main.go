@@ -0,0 +1,64 @@
+package main
+
+import (
+	"log"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	"github.com/thefabric-io/elrond-transaction-processor/txindex"
+)
+
+/*
+	Indexes the transactions of the testnet of the last week in a local file and serves the query API, for instance:
+
+		curl "localhost:8080/addresses/erd1.../transactions?since=2022-01-01T00:00:00Z&limit=20"
+
+	The position of the processor is kept in memory, so the last hour is indexed again on each run.
+*/
+
+func main() {
+	path := os.Getenv("INDEX_PATH")
+	if path == "" {
+		path = "transactions.db"
+	}
+
+	address := os.Getenv("HTTP_ADDRESS")
+	if address == "" {
+		address = ":8080"
+	}
+
+	ixo := txindex.Options{}
+	index, err := txindex.Open(path, ixo.Retention(7*24*time.Hour))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	go func() {
+		if err := http.ListenAndServe(address, txindex.NewHandler(index)); err != nil {
+			log.Fatal(err)
+		}
+	}()
+
+	gateway := elrondgateway.NewClient(elrondgateway.TestNetGatewayURL)
+
+	opts := processor.Options{}
+	proc, err := processor.NewProcessor(
+		opts.DataSource(gateway),
+		opts.StateStorage(processor.NewInMemoryStateStorage()),
+		opts.StartFrom(processor.StartFromTimestamp(time.Now().Add(-time.Hour))),
+		opts.Sinks(index),
+		opts.NotifyEmptyBlocks(false),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer proc.Close()
+
+	if err = proc.Start(); err != nil {
+		log.Println(err)
+	}
+}
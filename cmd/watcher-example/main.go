@@ -0,0 +1,68 @@
+package main
+
+import (
+	"log"
+	"os"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/elrondgateway"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	"github.com/thefabric-io/elrond-transaction-processor/watcher"
+)
+
+/*
+	Watches the addresses listed in the JSON file given by WATCH_LIST, for instance:
+
+		[
+			{
+				"address": "erd1...",
+				"label": "treasury",
+				"rules": [
+					{"name": "large-outgoing", "direction": "outgoing", "minValue": "100000000000000000000"},
+					{"name": "failed", "failed": true}
+				]
+			}
+		]
+
+	Alerts are logged, and also posted to ALERT_WEBHOOK_URL when it is set.
+*/
+
+func main() {
+	addresses, err := watcher.LoadWatchList(os.Getenv("WATCH_LIST"))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	notifiers := []watcher.Notifier{watcher.NewLogNotifier(nil)}
+	if url := os.Getenv("ALERT_WEBHOOK_URL"); url != "" {
+		notifiers = append(notifiers, watcher.NewWebhookNotifier(url, os.Getenv("ALERT_WEBHOOK_SECRET")))
+	}
+
+	wo := watcher.Options{}
+	w, err := watcher.New(addresses, notifiers, wo.RateLimit(10, time.Minute))
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer w.Close()
+
+	gateway := elrondgateway.NewClient(elrondgateway.MainNetGatewayURL)
+
+	opts := processor.Options{}
+	proc, err := processor.NewProcessor(
+		opts.DataSource(gateway),
+		opts.StateStorage(processor.NewInMemoryStateStorage()),
+		opts.StartFrom(processor.StartFromTimestamp(time.Now().Add(-time.Minute))),
+		opts.OnTransactionsReceived(w.OnTransactionsReceived),
+		opts.NotifyEmptyBlocks(false),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	defer proc.Close()
+
+	if err = proc.Start(); err != nil {
+		log.Println(err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"io/ioutil"
 	"net/http"
+	"time"
 
 	"github.com/thefabric-io/elrond-transaction-processor/processor"
 )
@@ -25,6 +26,15 @@ type Client struct {
 }
 
 func (e *Client) GetShards() ([]processor.Shard, error) {
+	config, err := e.GetNetworkConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	return config.Shards(), nil
+}
+
+func (e *Client) GetNetworkConfig() (*processor.NetworkConfig, error) {
 	b, err := e.get("network/config")
 	if err != nil {
 		return nil, err
@@ -39,16 +49,20 @@ func (e *Client) GetShards() ([]processor.Shard, error) {
 		return nil, errors.New(fmt.Sprintf("%s: %s", response.Code, response.Error))
 	}
 
-	shardCount := response.Data.Config.ErdNumShardsWithoutMeta
-
-	result := make([]processor.Shard, shardCount)
-	for i := 0; i < shardCount; i++ {
-		result[i] = processor.Shard(i)
-	}
-
-	result = append(result, processor.ShardMetachain)
-
-	return result, nil
+	config := response.Data.Config
+
+	return processor.NewNetworkConfigBuilder().
+		ChainID(config.ErdChainId).
+		Denomination(config.ErdDenomination).
+		NumShards(config.ErdNumShardsWithoutMeta).
+		RoundDuration(time.Duration(config.ErdRoundDuration) * time.Millisecond).
+		RoundsPerEpoch(config.ErdRoundsPerEpoch).
+		StartTime(time.Unix(int64(config.ErdStartTime), 0)).
+		MinGasLimit(config.ErdMinGasLimit).
+		MinGasPrice(config.ErdMinGasPrice).
+		GasPerDataByte(config.ErdGasPerDataByte).
+		GasPriceModifier(config.ErdGasPriceModifier).
+		Build(), nil
 }
 
 func (e *Client) GetCurrentNoncesForShards(shards []processor.Shard) (processor.NonceByShard, error) {
@@ -66,44 +80,55 @@ func (e *Client) GetCurrentNoncesForShards(shards []processor.Shard) (processor.
 }
 
 func (e *Client) GetCurrentNonceForShard(shard processor.Shard) (processor.Nonce, error) {
-	b, err := e.get(fmt.Sprintf("network/status/%d", shard))
+	status, err := e.GetNetworkStatus(shard)
 	if err != nil {
 		return 0, err
 	}
 
-	response := GetCurrentNonceForShardResponse{}
-	if err := json.Unmarshal(b, &response); err != nil {
-		return 0, err
-	}
-
-	if response.Code != CodeSuccessful {
-		return 0, errors.New(fmt.Sprintf("%s: %s", response.Code, response.Error))
-	}
-
-	return processor.Nonce(response.Data.Status.ErdNonce), nil
+	return status.Nonce(), nil
 }
 
-func (e *Client) GetShardTransactions(shard processor.Shard, nonce processor.Nonce) (string, []*processor.Transaction, error) {
-	b, err := e.get(fmt.Sprintf("block/%d/by-nonce/%d?withTxs=true", shard, nonce))
+func (e *Client) GetNetworkStatus(shard processor.Shard) (*processor.NetworkStatus, error) {
+	b, err := e.get(fmt.Sprintf("network/status/%d", shard))
 	if err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
-	response := GetShardTransactionsResponse{}
+	response := GetCurrentNonceForShardResponse{}
 	if err := json.Unmarshal(b, &response); err != nil {
-		return "", nil, err
+		return nil, err
 	}
 
 	if response.Code != CodeSuccessful {
-		return "", nil, errors.New(fmt.Sprintf("%s: %s", response.Code, response.Error))
+		return nil, errors.New(fmt.Sprintf("%s: %s", response.Code, response.Error))
 	}
 
-	if len(response.Data.Block.Hash) == 0 {
-		return "", nil, errors.New(fmt.Sprintf("Block for shard %d and nonce %d is undefined or block not available\n", shard, nonce))
+	status := response.Data.Status
+
+	return processor.NewNetworkStatusBuilder().
+		Shard(shard).
+		Nonce(processor.Nonce(status.ErdNonce)).
+		HighestFinalNonce(processor.Nonce(status.ErdHighestFinalNonce)).
+		CurrentRound(status.ErdCurrentRound).
+		Epoch(status.ErdEpochNumber).
+		RoundsPerEpoch(status.ErdRoundsPerEpoch).
+		NonceAtEpochStart(processor.Nonce(status.ErdNonceAtEpochStart)).
+		RoundAtEpochStart(status.ErdRoundAtEpochStart).
+		NoncesPassedInCurrentEpoch(status.ErdNoncesPassedInCurrentEpoch).
+		RoundsPassedInCurrentEpoch(status.ErdRoundsPassedInCurrentEpoch).
+		Build(), nil
+}
+
+func (e *Client) GetShardTransactions(shard processor.Shard, nonce processor.Nonce) (*processor.BlockHeader, []*processor.Transaction, error) {
+	response, err := e.getBlock(shard, nonce, true)
+	if err != nil {
+		return nil, nil, err
 	}
 
+	header := newBlockHeader(response)
+
 	if len(response.Data.Block.MiniBlocks) == 0 {
-		return response.Data.Block.Hash, []*processor.Transaction{}, nil
+		return header, []*processor.Transaction{}, nil
 	}
 
 	results := make([]*processor.Transaction, 0)
@@ -113,6 +138,7 @@ func (e *Client) GetShardTransactions(shard processor.Shard, nonce processor.Non
 	for _, mb := range response.Data.Block.MiniBlocks {
 		for _, mbTx := range mb.Transactions {
 			tx := txB.NewTransaction().
+				Kind(processor.TransactionKind(mbTx.Type)).
 				Value(mbTx.Value).
 				Data(mbTx.Data).
 				Hash(mbTx.Hash).
@@ -131,8 +157,104 @@ func (e *Client) GetShardTransactions(shard processor.Shard, nonce processor.Non
 		}
 	}
 
-	return response.Data.Block.Hash, results, nil
+	return header, results, nil
+
+}
+
+func (e *Client) GetBlockHeader(shard processor.Shard, nonce processor.Nonce) (*processor.BlockHeader, error) {
+	response, err := e.getBlock(shard, nonce, false)
+	if err != nil {
+		return nil, err
+	}
+
+	return newBlockHeader(response), nil
+}
+
+// GetTransactionLogs returns the log of the transaction followed by the events of the logs of its smart contract
+// results, or nil when none of them wrote a log.
+func (e *Client) GetTransactionLogs(hash string) (*processor.TransactionLog, error) {
+	b, err := e.get(fmt.Sprintf("transaction/%s?withResults=true", hash))
+	if err != nil {
+		return nil, err
+	}
+
+	response := GetTransactionResponse{}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Code != CodeSuccessful {
+		return nil, errors.New(fmt.Sprintf("%s: %s", response.Code, response.Error))
+	}
+
+	transaction := response.Data.Transaction
+
+	logs := make([]*TransactionLogResponse, 0, len(transaction.SmartContractResults)+1)
+	if transaction.Logs != nil {
+		logs = append(logs, transaction.Logs)
+	}
+
+	for _, scr := range transaction.SmartContractResults {
+		if scr.Logs != nil {
+			logs = append(logs, scr.Logs)
+		}
+	}
+
+	if len(logs) == 0 {
+		return nil, nil
+	}
+
+	events := make([]*processor.Event, 0)
+	for _, l := range logs {
+		for _, event := range l.Events {
+			events = append(events, processor.NewEvent(event.Address, event.Identifier, event.Topics, event.Data))
+		}
+	}
+
+	return processor.NewTransactionLog(logs[0].Address, events), nil
+}
+
+func (e *Client) getBlock(shard processor.Shard, nonce processor.Nonce, withTxs bool) (*GetShardTransactionsResponse, error) {
+	path := fmt.Sprintf("block/%d/by-nonce/%d", shard, nonce)
+	if withTxs {
+		path += "?withTxs=true"
+	}
+
+	b, err := e.get(path)
+	if err != nil {
+		return nil, err
+	}
+
+	response := GetShardTransactionsResponse{}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Code != CodeSuccessful {
+		return nil, errors.New(fmt.Sprintf("%s: %s", response.Code, response.Error))
+	}
+
+	if len(response.Data.Block.Hash) == 0 {
+		return nil, errors.New(fmt.Sprintf("Block for shard %d and nonce %d is undefined or block not available\n", shard, nonce))
+	}
+
+	return &response, nil
+}
 
+func newBlockHeader(response *GetShardTransactionsResponse) *processor.BlockHeader {
+	block := response.Data.Block
+
+	return processor.NewBlockHeaderBuilder().
+		Hash(block.Hash).
+		PreviousHash(block.PrevBlockHash).
+		Shard(processor.Shard(block.Shard)).
+		Nonce(processor.Nonce(block.Nonce)).
+		Round(block.Round).
+		Epoch(block.Epoch).
+		Timestamp(time.Unix(int64(block.Timestamp), 0)).
+		AccumulatedFees(block.AccumulatedFees).
+		DeveloperFees(block.DeveloperFees).
+		Build()
 }
 
 func (e *Client) get(path string) ([]byte, error) {
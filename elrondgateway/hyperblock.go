@@ -0,0 +1,139 @@
+package elrondgateway
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+var (
+	ErrShardNotServedByHyperblocks = errors.New("hyperblocks are only available for the metachain")
+)
+
+// NewHyperblockClient returns a processor.DataSource reading the hyperblocks of the metachain, which hold every
+// transaction notarized by a metachain block, whatever its shards. It serves the metachain only and is meant for a
+// processor started with the Hyperblocks option.
+func NewHyperblockClient(url string) *HyperblockClient {
+	return &HyperblockClient{Client: NewClient(url)}
+}
+
+type HyperblockClient struct {
+	*Client
+}
+
+func (e *HyperblockClient) GetShards() ([]processor.Shard, error) {
+	return []processor.Shard{processor.ShardMetachain}, nil
+}
+
+func (e *HyperblockClient) GetCurrentNonceForShard(shard processor.Shard) (processor.Nonce, error) {
+	if !shard.Equals(processor.ShardMetachain) {
+		return 0, fmt.Errorf("%w: %s", ErrShardNotServedByHyperblocks, shard.Name())
+	}
+
+	return e.Client.GetCurrentNonceForShard(shard)
+}
+
+func (e *HyperblockClient) GetCurrentNoncesForShards(shards []processor.Shard) (processor.NonceByShard, error) {
+	var err error
+
+	result := make(processor.NonceByShard, len(shards))
+	for _, shard := range shards {
+		result[shard], err = e.GetCurrentNonceForShard(shard)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return result, nil
+}
+
+// GetShardTransactions returns the header of the metachain block of the given nonce and the transactions of its
+// hyperblock, in the order of the hyperblock.
+func (e *HyperblockClient) GetShardTransactions(shard processor.Shard, nonce processor.Nonce) (*processor.BlockHeader, []*processor.Transaction, error) {
+	response, err := e.getHyperblock(shard, nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	header := newHyperblockHeader(response)
+
+	results := make([]*processor.Transaction, 0, len(response.Data.Hyperblock.Transactions))
+
+	txB := processor.NewTransactionBuilder()
+
+	for _, hbTx := range response.Data.Hyperblock.Transactions {
+		tx := txB.NewTransaction().
+			Kind(processor.TransactionKind(hbTx.Type)).
+			Value(hbTx.Value).
+			Data(hbTx.Data).
+			Hash(hbTx.Hash).
+			Sender(hbTx.Sender).
+			Receiver(hbTx.Receiver).
+			Status(hbTx.Status).
+			SourceShard(processor.Shard(hbTx.SourceShard)).
+			DestinationShard(processor.Shard(hbTx.DestinationShard)).
+			Nonce(processor.Nonce(hbTx.Nonce)).
+			PreviousTransactionHash(hbTx.PreviousTransactionHash).
+			OriginalTransactionHash(hbTx.OriginalTransactionHash).
+			GasPrice(hbTx.GasPrice).
+			GasLimit(hbTx.GasLimit).
+			Build()
+		results = append(results, tx)
+	}
+
+	return header, results, nil
+}
+
+func (e *HyperblockClient) GetBlockHeader(shard processor.Shard, nonce processor.Nonce) (*processor.BlockHeader, error) {
+	response, err := e.getHyperblock(shard, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	return newHyperblockHeader(response), nil
+}
+
+func (e *HyperblockClient) getHyperblock(shard processor.Shard, nonce processor.Nonce) (*GetHyperblockResponse, error) {
+	if !shard.Equals(processor.ShardMetachain) {
+		return nil, fmt.Errorf("%w: %s", ErrShardNotServedByHyperblocks, shard.Name())
+	}
+
+	b, err := e.get(fmt.Sprintf("hyperblock/by-nonce/%d", nonce))
+	if err != nil {
+		return nil, err
+	}
+
+	response := GetHyperblockResponse{}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, err
+	}
+
+	if response.Code != CodeSuccessful {
+		return nil, errors.New(fmt.Sprintf("%s: %s", response.Code, response.Error))
+	}
+
+	if len(response.Data.Hyperblock.Hash) == 0 {
+		return nil, errors.New(fmt.Sprintf("Hyperblock for nonce %d is undefined or not available\n", nonce))
+	}
+
+	return &response, nil
+}
+
+func newHyperblockHeader(response *GetHyperblockResponse) *processor.BlockHeader {
+	hyperblock := response.Data.Hyperblock
+
+	return processor.NewBlockHeaderBuilder().
+		Hash(hyperblock.Hash).
+		PreviousHash(hyperblock.PrevBlockHash).
+		Shard(processor.ShardMetachain).
+		Nonce(processor.Nonce(hyperblock.Nonce)).
+		Round(hyperblock.Round).
+		Epoch(hyperblock.Epoch).
+		Timestamp(time.Unix(int64(hyperblock.Timestamp), 0)).
+		AccumulatedFees(hyperblock.AccumulatedFees).
+		DeveloperFees(hyperblock.DeveloperFees).
+		Build()
+}
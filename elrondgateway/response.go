@@ -89,3 +89,75 @@ type GetShardTransactionsResponse struct {
 	Code  string `json:"code"`
 	Error string `json:"error"`
 }
+
+type GetHyperblockResponse struct {
+	Data struct {
+		Hyperblock struct {
+			Nonce           int    `json:"nonce"`
+			Round           int    `json:"round"`
+			Hash            string `json:"hash"`
+			PrevBlockHash   string `json:"prevBlockHash"`
+			Epoch           int    `json:"epoch"`
+			NumTxs          int    `json:"numTxs"`
+			AccumulatedFees string `json:"accumulatedFees"`
+			DeveloperFees   string `json:"developerFees"`
+			Timestamp       int    `json:"timestamp"`
+			ShardBlocks     []struct {
+				Hash  string `json:"hash"`
+				Nonce int    `json:"nonce"`
+				Shard int    `json:"shard"`
+			} `json:"shardBlocks"`
+			Transactions []struct {
+				Type                              string `json:"type"`
+				Hash                              string `json:"hash"`
+				Nonce                             int    `json:"nonce"`
+				Value                             string `json:"value"`
+				Receiver                          string `json:"receiver"`
+				Sender                            string `json:"sender"`
+				GasPrice                          int    `json:"gasPrice"`
+				GasLimit                          int    `json:"gasLimit,omitempty"`
+				Data                              string `json:"data"`
+				PreviousTransactionHash           string `json:"previousTransactionHash,omitempty"`
+				OriginalTransactionHash           string `json:"originalTransactionHash,omitempty"`
+				SourceShard                       int    `json:"sourceShard"`
+				DestinationShard                  int    `json:"destinationShard"`
+				BlockNonce                        int    `json:"blockNonce"`
+				BlockHash                         string `json:"blockHash"`
+				NotarizedAtSourceInMetaNonce      int    `json:"notarizedAtSourceInMetaNonce"`
+				NotarizedAtDestinationInMetaNonce int    `json:"notarizedAtDestinationInMetaNonce"`
+				MiniblockType                     string `json:"miniblockType"`
+				MiniblockHash                     string `json:"miniblockHash"`
+				Status                            string `json:"status"`
+				Signature                         string `json:"signature,omitempty"`
+			} `json:"transactions"`
+		} `json:"hyperblock"`
+	} `json:"data"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
+
+type TransactionLogResponse struct {
+	Address string `json:"address"`
+	Events  []struct {
+		Address    string   `json:"address"`
+		Identifier string   `json:"identifier"`
+		Topics     []string `json:"topics"`
+		Data       string   `json:"data"`
+	} `json:"events"`
+}
+
+type GetTransactionResponse struct {
+	Data struct {
+		Transaction struct {
+			Hash                 string                  `json:"hash"`
+			Status               string                  `json:"status"`
+			Logs                 *TransactionLogResponse `json:"logs,omitempty"`
+			SmartContractResults []struct {
+				Hash string                  `json:"hash"`
+				Logs *TransactionLogResponse `json:"logs,omitempty"`
+			} `json:"smartContractResults,omitempty"`
+		} `json:"transaction"`
+	} `json:"data"`
+	Code  string `json:"code"`
+	Error string `json:"error"`
+}
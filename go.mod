@@ -3,7 +3,13 @@ module github.com/thefabric-io/elrond-transaction-processor
 go 1.16
 
 require (
+	github.com/Shopify/sarama v1.30.1
+	github.com/alicebob/miniredis/v2 v2.30.4
 	github.com/go-redis/redis/v8 v8.11.4
+	github.com/gorilla/websocket v1.5.0
 	github.com/joho/godotenv v1.4.0
 	github.com/schollz/progressbar/v3 v3.8.3
+	go.etcd.io/bbolt v1.3.6
+	google.golang.org/grpc v1.43.0
+	google.golang.org/protobuf v1.27.1
 )
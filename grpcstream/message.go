@@ -0,0 +1,46 @@
+package grpcstream
+
+import (
+	"github.com/thefabric-io/elrond-transaction-processor/grpcstream/pb"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+func NewBlockMessage(block *processor.Block) *pb.Block {
+	header := block.Header()
+
+	message := &pb.Block{
+		Shard:        uint32(header.Shard()),
+		Nonce:        uint64(header.Nonce()),
+		Hash:         header.Hash(),
+		PreviousHash: header.PreviousHash(),
+		Round:        uint64(header.Round()),
+		Epoch:        uint32(header.Epoch()),
+		Timestamp:    header.Timestamp().Unix(),
+		Transactions: make([]*pb.Transaction, 0, len(block.Transactions())),
+	}
+
+	for _, tx := range block.Transactions() {
+		message.Transactions = append(message.Transactions, NewTransactionMessage(tx))
+	}
+
+	return message
+}
+
+func NewTransactionMessage(tx *processor.Transaction) *pb.Transaction {
+	return &pb.Transaction{
+		Hash:                    tx.Hash(),
+		Kind:                    string(tx.Kind()),
+		Nonce:                   uint64(tx.Nonce()),
+		Value:                   tx.Value(),
+		Sender:                  tx.Sender(),
+		Receiver:                tx.Receiver(),
+		Data:                    tx.Data(),
+		Status:                  tx.Status(),
+		SourceShard:             uint32(tx.SourceShard()),
+		DestinationShard:        uint32(tx.DestinationShard()),
+		PreviousTransactionHash: tx.PreviousTransactionHash(),
+		OriginalTransactionHash: tx.OriginalTransactionHash(),
+		GasPrice:                uint64(tx.GasPrice()),
+		GasLimit:                uint64(tx.GasLimit()),
+	}
+}
@@ -0,0 +1,23 @@
+package grpcstream
+
+type Option func(*Server)
+
+type Options struct{}
+
+// HistorySize sets the number of blocks kept per shard for reconnecting clients.
+func (oo *Options) HistorySize(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.historySize = n
+		}
+	}
+}
+
+// SubscriberBuffer sets the number of blocks queued for a client before it is disconnected for being too slow.
+func (oo *Options) SubscriberBuffer(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.subscriberBuffer = n
+		}
+	}
+}
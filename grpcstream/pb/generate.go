@@ -0,0 +1,4 @@
+// Package pb holds the protobuf schema of the block stream and the code generated from it.
+package pb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative stream.proto
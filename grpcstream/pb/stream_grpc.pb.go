@@ -0,0 +1,132 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+
+package pb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+// BlockStreamClient is the client API for BlockStream service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type BlockStreamClient interface {
+	// Subscribe sends the buffered blocks following the cursors of the request, then every new block as it is
+	// processed. The stream fails with OUT_OF_RANGE when a cursor is older than the buffered history of its shard.
+	Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (BlockStream_SubscribeClient, error)
+}
+
+type blockStreamClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBlockStreamClient(cc grpc.ClientConnInterface) BlockStreamClient {
+	return &blockStreamClient{cc}
+}
+
+func (c *blockStreamClient) Subscribe(ctx context.Context, in *SubscribeRequest, opts ...grpc.CallOption) (BlockStream_SubscribeClient, error) {
+	stream, err := c.cc.NewStream(ctx, &BlockStream_ServiceDesc.Streams[0], "/elrond.processor.v1.BlockStream/Subscribe", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &blockStreamSubscribeClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type BlockStream_SubscribeClient interface {
+	Recv() (*Block, error)
+	grpc.ClientStream
+}
+
+type blockStreamSubscribeClient struct {
+	grpc.ClientStream
+}
+
+func (x *blockStreamSubscribeClient) Recv() (*Block, error) {
+	m := new(Block)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// BlockStreamServer is the server API for BlockStream service.
+// All implementations must embed UnimplementedBlockStreamServer
+// for forward compatibility
+type BlockStreamServer interface {
+	// Subscribe sends the buffered blocks following the cursors of the request, then every new block as it is
+	// processed. The stream fails with OUT_OF_RANGE when a cursor is older than the buffered history of its shard.
+	Subscribe(*SubscribeRequest, BlockStream_SubscribeServer) error
+	mustEmbedUnimplementedBlockStreamServer()
+}
+
+// UnimplementedBlockStreamServer must be embedded to have forward compatible implementations.
+type UnimplementedBlockStreamServer struct {
+}
+
+func (UnimplementedBlockStreamServer) Subscribe(*SubscribeRequest, BlockStream_SubscribeServer) error {
+	return status.Errorf(codes.Unimplemented, "method Subscribe not implemented")
+}
+func (UnimplementedBlockStreamServer) mustEmbedUnimplementedBlockStreamServer() {}
+
+// UnsafeBlockStreamServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BlockStreamServer will
+// result in compilation errors.
+type UnsafeBlockStreamServer interface {
+	mustEmbedUnimplementedBlockStreamServer()
+}
+
+func RegisterBlockStreamServer(s grpc.ServiceRegistrar, srv BlockStreamServer) {
+	s.RegisterService(&BlockStream_ServiceDesc, srv)
+}
+
+func _BlockStream_Subscribe_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BlockStreamServer).Subscribe(m, &blockStreamSubscribeServer{stream})
+}
+
+type BlockStream_SubscribeServer interface {
+	Send(*Block) error
+	grpc.ServerStream
+}
+
+type blockStreamSubscribeServer struct {
+	grpc.ServerStream
+}
+
+func (x *blockStreamSubscribeServer) Send(m *Block) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// BlockStream_ServiceDesc is the grpc.ServiceDesc for BlockStream service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BlockStream_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "elrond.processor.v1.BlockStream",
+	HandlerType: (*BlockStreamServer)(nil),
+	Methods:     []grpc.MethodDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Subscribe",
+			Handler:       _BlockStream_Subscribe_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "stream.proto",
+}
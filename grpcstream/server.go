@@ -0,0 +1,232 @@
+package grpcstream
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/thefabric-io/elrond-transaction-processor/grpcstream/pb"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	defaultHistorySize      = 1000
+	defaultSubscriberBuffer = 256
+)
+
+// NewServer returns a BlockStream service fed by the processor as a processor.Sink. The last blocks of each shard are
+// kept in memory so that clients reconnecting with their cursors resume without missing any block.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		historySize:      defaultHistorySize,
+		subscriberBuffer: defaultSubscriberBuffer,
+		history:          map[uint32][]*pb.Block{},
+		subscribers:      map[*subscriber]struct{}{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+type Server struct {
+	pb.UnimplementedBlockStreamServer
+
+	mu               sync.Mutex
+	historySize      int
+	subscriberBuffer int
+	history          map[uint32][]*pb.Block
+	subscribers      map[*subscriber]struct{}
+}
+
+// Register registers the service on a gRPC server.
+func (s *Server) Register(g *grpc.Server) {
+	pb.RegisterBlockStreamServer(g, s)
+}
+
+func (s *Server) Send(block *processor.Block) error {
+	message := NewBlockMessage(block)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.record(message) {
+		return nil
+	}
+
+	for sub := range s.subscribers {
+		if !sub.wants(message.Shard) {
+			continue
+		}
+
+		select {
+		case sub.blocks <- message:
+		default:
+			s.unsubscribe(sub, status.Error(codes.ResourceExhausted, "subscriber is too slow, resubscribe with its cursors"))
+		}
+	}
+
+	return nil
+}
+
+func (s *Server) Flush() error {
+	return nil
+}
+
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for sub := range s.subscribers {
+		s.unsubscribe(sub, status.Error(codes.Unavailable, "server is shutting down"))
+	}
+
+	return nil
+}
+
+func (s *Server) Subscribe(request *pb.SubscribeRequest, stream pb.BlockStream_SubscribeServer) error {
+	sub := newSubscriber(request, s.subscriberBuffer)
+
+	s.mu.Lock()
+	backlog, err := s.backlog(request.Cursors, sub)
+	if err == nil {
+		s.subscribers[sub] = struct{}{}
+	}
+	s.mu.Unlock()
+
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		s.mu.Lock()
+		s.unsubscribe(sub, nil)
+		s.mu.Unlock()
+	}()
+
+	for _, block := range backlog {
+		if err := stream.Send(block); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return stream.Context().Err()
+		case <-sub.done:
+			return sub.err
+		case block := <-sub.blocks:
+			if err := stream.Send(block); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// record adds the block to the history of its shard and reports whether it is new. Blocks replayed by the processor
+// are ignored, as are blocks older than the history, while a different block at an already recorded nonce replaces
+// the history from that nonce.
+func (s *Server) record(block *pb.Block) bool {
+	history := s.history[block.Shard]
+	if len(history) != 0 && block.Nonce < history[0].Nonce {
+		return false
+	}
+
+	i := sort.Search(len(history), func(i int) bool {
+		return history[i].Nonce >= block.Nonce
+	})
+
+	switch {
+	case i == len(history):
+		history = append(history, block)
+	case history[i].Nonce != block.Nonce:
+		/* A block missing from the history is inserted at its nonce */
+		history = append(history[:i], append([]*pb.Block{block}, history[i:]...)...)
+	case history[i].Hash == block.Hash:
+		return false
+	default:
+		history = append(history[:i], block)
+	}
+
+	if len(history) > s.historySize {
+		history = history[len(history)-s.historySize:]
+	}
+
+	s.history[block.Shard] = history
+
+	return true
+}
+
+func (s *Server) backlog(cursors []*pb.Cursor, sub *subscriber) ([]*pb.Block, error) {
+	backlog := make([]*pb.Block, 0)
+
+	for _, cursor := range cursors {
+		if !sub.wants(cursor.Shard) {
+			continue
+		}
+
+		history := s.history[cursor.Shard]
+		if len(history) == 0 {
+			continue
+		}
+
+		if cursor.Nonce+1 < history[0].Nonce {
+			return nil, status.Errorf(codes.OutOfRange, "cursor %d of shard %d is older than the buffered history starting at %d", cursor.Nonce, cursor.Shard, history[0].Nonce)
+		}
+
+		for _, block := range history {
+			if block.Nonce > cursor.Nonce {
+				backlog = append(backlog, block)
+			}
+		}
+	}
+
+	return backlog, nil
+}
+
+func (s *Server) unsubscribe(sub *subscriber, err error) {
+	if _, found := s.subscribers[sub]; !found {
+		return
+	}
+
+	delete(s.subscribers, sub)
+
+	sub.err = err
+	close(sub.done)
+}
+
+type subscriber struct {
+	shards map[uint32]bool
+	blocks chan *pb.Block
+	done   chan struct{}
+	err    error
+}
+
+func newSubscriber(request *pb.SubscribeRequest, buffer int) *subscriber {
+	sub := &subscriber{
+		blocks: make(chan *pb.Block, buffer),
+		done:   make(chan struct{}),
+	}
+
+	if len(request.Shards) != 0 {
+		sub.shards = map[uint32]bool{}
+		for _, shard := range request.Shards {
+			sub.shards[shard] = true
+		}
+
+		for _, cursor := range request.Cursors {
+			sub.shards[cursor.Shard] = true
+		}
+	}
+
+	return sub
+}
+
+func (s *subscriber) wants(shard uint32) bool {
+	return s.shards == nil || s.shards[shard]
+}
@@ -0,0 +1,192 @@
+package grpcstream
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/grpcstream/pb"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestBlock(shard processor.Shard, nonce processor.Nonce, hash string) *processor.Block {
+	return processor.NewBlock(processor.NewBlockHeaderBuilder().Shard(shard).Nonce(nonce).Hash(hash).Build(), nil)
+}
+
+// historyOf returns the nonce and hash of each block of the history of the shard.
+func historyOf(s *Server, shard uint32) []string {
+	history := make([]string, 0)
+	for _, block := range s.history[shard] {
+		history = append(history, fmt.Sprintf("%d:%s", block.Nonce, block.Hash))
+	}
+
+	return history
+}
+
+func TestServerRecord(t *testing.T) {
+	type sent struct {
+		nonce    processor.Nonce
+		hash     string
+		recorded bool
+	}
+
+	tests := []struct {
+		name     string
+		sent     []sent
+		expected []string
+	}{
+		{
+			name:     "new blocks",
+			sent:     []sent{{1, "a", true}, {2, "a", true}, {3, "a", true}},
+			expected: []string{"1:a", "2:a", "3:a"},
+		},
+		{
+			name:     "replayed block",
+			sent:     []sent{{1, "a", true}, {2, "a", true}, {1, "a", false}, {2, "a", false}},
+			expected: []string{"1:a", "2:a"},
+		},
+		{
+			name:     "replayed block older than the history",
+			sent:     []sent{{1, "a", true}, {2, "a", true}, {3, "a", true}, {4, "a", true}, {5, "a", true}, {1, "a", false}, {2, "b", false}},
+			expected: []string{"3:a", "4:a", "5:a"},
+		},
+		{
+			name:     "reorg replaces the history from the nonce",
+			sent:     []sent{{1, "a", true}, {2, "a", true}, {3, "a", true}, {2, "b", true}},
+			expected: []string{"1:a", "2:b"},
+		},
+		{
+			name:     "missing block is inserted",
+			sent:     []sent{{1, "a", true}, {3, "a", true}, {2, "a", true}},
+			expected: []string{"1:a", "2:a", "3:a"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oo := Options{}
+			s := NewServer(oo.HistorySize(3))
+
+			for i, b := range tt.sent {
+				if recorded := s.record(NewBlockMessage(newTestBlock(0, b.nonce, b.hash))); recorded != b.recorded {
+					t.Fatalf("block %d: expected recorded %t, got %t", i, b.recorded, recorded)
+				}
+			}
+
+			if history := historyOf(s, 0); fmt.Sprint(history) != fmt.Sprint(tt.expected) {
+				t.Fatalf("expected history %v, got %v", tt.expected, history)
+			}
+		})
+	}
+}
+
+// testStream collects the blocks sent to a subscriber.
+type testStream struct {
+	grpc.ServerStream
+	ctx    context.Context
+	blocks chan *pb.Block
+}
+
+func (s *testStream) Send(block *pb.Block) error {
+	s.blocks <- block
+
+	return nil
+}
+
+func (s *testStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestServerSubscribeResumesFromCursor(t *testing.T) {
+	tests := []struct {
+		name    string
+		request *pb.SubscribeRequest
+		backlog []string
+		live    []string
+		code    codes.Code
+	}{
+		{
+			name:    "cursor within the history",
+			request: &pb.SubscribeRequest{Shards: []uint32{0}, Cursors: []*pb.Cursor{{Shard: 0, Nonce: 4}}},
+			backlog: []string{"0/5", "0/6"},
+			live:    []string{"0/7"},
+		},
+		{
+			name:    "cursor right before the history",
+			request: &pb.SubscribeRequest{Cursors: []*pb.Cursor{{Shard: 1, Nonce: 2}}},
+			backlog: []string{"1/3", "1/4", "1/5", "1/6"},
+			live:    []string{"0/7", "1/7"},
+		},
+		{
+			name:    "cursor older than the history",
+			request: &pb.SubscribeRequest{Cursors: []*pb.Cursor{{Shard: 0, Nonce: 1}}},
+			code:    codes.OutOfRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oo := Options{}
+			s := NewServer(oo.HistorySize(4))
+
+			send := func(nonce processor.Nonce) {
+				for _, shard := range []processor.Shard{0, 1} {
+					if err := s.Send(newTestBlock(shard, nonce, "a")); err != nil {
+						t.Fatal(err)
+					}
+				}
+			}
+
+			for nonce := processor.Nonce(1); nonce <= 6; nonce++ {
+				send(nonce)
+			}
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			stream := &testStream{ctx: ctx, blocks: make(chan *pb.Block, 16)}
+
+			done := make(chan error, 1)
+			go func() {
+				done <- s.Subscribe(tt.request, stream)
+			}()
+
+			if tt.code != codes.OK {
+				if err := <-done; status.Code(err) != tt.code {
+					t.Fatalf("expected %s, got %v", tt.code, err)
+				}
+
+				return
+			}
+
+			receive := func(expected []string) {
+				received := make([]string, 0, len(expected))
+				for len(received) < len(expected) {
+					select {
+					case block := <-stream.blocks:
+						received = append(received, fmt.Sprintf("%d/%d", block.Shard, block.Nonce))
+					case <-time.After(time.Second):
+						t.Fatalf("expected %v, received %v", expected, received)
+					}
+				}
+
+				if fmt.Sprint(received) != fmt.Sprint(expected) {
+					t.Fatalf("expected %v, got %v", expected, received)
+				}
+			}
+
+			receive(tt.backlog)
+
+			/* Once the backlog is sent, the new blocks are streamed live */
+			send(7)
+			receive(tt.live)
+
+			cancel()
+			<-done
+		})
+	}
+}
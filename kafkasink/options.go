@@ -0,0 +1,45 @@
+package kafkasink
+
+import (
+	"github.com/Shopify/sarama"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+type Option func(*Sink)
+
+type Options struct{}
+
+// Topic sets the topic of transactions whose kind has no dedicated topic.
+func (oo *Options) Topic(topic string) Option {
+	return func(s *Sink) {
+		s.defaultTopic = topic
+	}
+}
+
+// TopicForKind routes the transactions of the given kind (e.g. smart contract results) to a dedicated topic.
+func (oo *Options) TopicForKind(kind processor.TransactionKind, topic string) Option {
+	return func(s *Sink) {
+		s.topics[kind] = topic
+	}
+}
+
+// TopicRouter replaces routing by kind with a custom router.
+func (oo *Options) TopicRouter(r TopicRouter) Option {
+	return func(s *Sink) {
+		s.router = r
+	}
+}
+
+func (oo *Options) KeyBy(k KeyStrategy) Option {
+	return func(s *Sink) {
+		s.keyStrategy = k
+	}
+}
+
+// Config replaces the producer configuration. Idempotence requires Producer.Idempotent, RequiredAcks set to
+// WaitForAll and Net.MaxOpenRequests set to 1, see NewProducerConfig.
+func (oo *Options) Config(c *sarama.Config) Option {
+	return func(s *Sink) {
+		s.config = c
+	}
+}
@@ -0,0 +1,180 @@
+package kafkasink
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+var (
+	ErrNoBrokers = errors.New("at least one kafka broker must be defined")
+	ErrNoTopic   = errors.New("a default topic or a topic router must be defined")
+)
+
+const (
+	KeyBySender KeyStrategy = iota
+	KeyByReceiver
+	KeyByShard
+)
+
+const (
+	HeaderShard     = "shard"
+	HeaderNonce     = "nonce"
+	HeaderBlockHash = "blockHash"
+	HeaderKind      = "kind"
+)
+
+// KeyStrategy defines the key of the message of a transaction. Messages sharing a key land in the same partition, so
+// their order is preserved.
+type KeyStrategy int
+
+// TopicRouter returns the topic of the message of a transaction.
+type TopicRouter func(block *processor.Block, tx *processor.Transaction) string
+
+// New returns a processor.Sink publishing one message per transaction. Blocks are published synchronously, so that a
+// block is acknowledged by every in-sync replica before the processor advances to the next nonce of its shard.
+//
+// The producer is idempotent: retries caused by transient errors never duplicate a message nor reorder the messages
+// of a partition.
+func New(brokers []string, opts ...Option) (*Sink, error) {
+	if len(brokers) == 0 {
+		return nil, ErrNoBrokers
+	}
+
+	s := &Sink{
+		config:      NewProducerConfig(),
+		keyStrategy: KeyBySender,
+		topics:      map[processor.TransactionKind]string{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.router == nil {
+		/* The transactions of the kinds without a dedicated topic would have nowhere to go */
+		if s.defaultTopic == "" {
+			return nil, ErrNoTopic
+		}
+
+		s.router = s.routeByKind
+	}
+
+	producer, err := sarama.NewSyncProducer(brokers, s.config)
+	if err != nil {
+		return nil, err
+	}
+
+	s.producer = producer
+
+	return s, nil
+}
+
+// NewProducerConfig returns the configuration of an idempotent producer hashing message keys to partitions.
+func NewProducerConfig() *sarama.Config {
+	config := sarama.NewConfig()
+	config.ClientID = "elrond-transaction-processor"
+	config.Version = sarama.V2_1_0_0
+	config.Producer.Idempotent = true
+	config.Producer.RequiredAcks = sarama.WaitForAll
+	config.Producer.Return.Successes = true
+	config.Producer.Retry.Max = 10
+	config.Producer.Retry.Backoff = 250 * time.Millisecond
+	config.Producer.Partitioner = sarama.NewHashPartitioner
+	config.Net.MaxOpenRequests = 1
+
+	return config
+}
+
+type Sink struct {
+	producer     sarama.SyncProducer
+	config       *sarama.Config
+	keyStrategy  KeyStrategy
+	router       TopicRouter
+	defaultTopic string
+	topics       map[processor.TransactionKind]string
+}
+
+// Message is the value of a published message.
+type Message struct {
+	Shard       processor.Shard        `json:"shard"`
+	Nonce       processor.Nonce        `json:"nonce"`
+	BlockHash   string                 `json:"blockHash"`
+	Timestamp   int64                  `json:"timestamp"`
+	Transaction *processor.Transaction `json:"transaction"`
+}
+
+func (s *Sink) Send(block *processor.Block) error {
+	messages := make([]*sarama.ProducerMessage, 0, len(block.Transactions()))
+
+	for _, tx := range block.Transactions() {
+		message, err := s.message(block, tx)
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, message)
+	}
+
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return s.producer.SendMessages(messages)
+}
+
+func (s *Sink) Flush() error {
+	return nil
+}
+
+func (s *Sink) Close() error {
+	return s.producer.Close()
+}
+
+func (s *Sink) message(block *processor.Block, tx *processor.Transaction) (*sarama.ProducerMessage, error) {
+	value, err := json.Marshal(Message{
+		Shard:       block.Shard(),
+		Nonce:       block.Nonce(),
+		BlockHash:   block.Hash(),
+		Timestamp:   block.Header().Timestamp().Unix(),
+		Transaction: tx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &sarama.ProducerMessage{
+		Topic: s.router(block, tx),
+		Key:   sarama.StringEncoder(s.key(block, tx)),
+		Value: sarama.ByteEncoder(value),
+		Headers: []sarama.RecordHeader{
+			{Key: []byte(HeaderShard), Value: []byte(strconv.Itoa(int(block.Shard())))},
+			{Key: []byte(HeaderNonce), Value: []byte(strconv.Itoa(int(block.Nonce())))},
+			{Key: []byte(HeaderBlockHash), Value: []byte(block.Hash())},
+			{Key: []byte(HeaderKind), Value: []byte(tx.Kind())},
+		},
+	}, nil
+}
+
+func (s *Sink) key(block *processor.Block, tx *processor.Transaction) string {
+	switch s.keyStrategy {
+	case KeyByReceiver:
+		return tx.Receiver()
+	case KeyByShard:
+		return strconv.Itoa(int(block.Shard()))
+	default:
+		return tx.Sender()
+	}
+}
+
+func (s *Sink) routeByKind(_ *processor.Block, tx *processor.Transaction) string {
+	if topic, found := s.topics[tx.Kind()]; found {
+		return topic
+	}
+
+	return s.defaultTopic
+}
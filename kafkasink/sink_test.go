@@ -0,0 +1,251 @@
+package kafkasink
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Shopify/sarama"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+func newTestBlock(nonce processor.Nonce) *processor.Block {
+	header := processor.NewBlockHeaderBuilder().Shard(1).Nonce(nonce).Hash(fmt.Sprint("block-", nonce)).Timestamp(time.Unix(1600000000, 0)).Build()
+
+	return processor.NewBlock(header, processor.Transactions{
+		processor.NewTransactionBuilder().Hash(fmt.Sprint("tx-", nonce)).Sender("erd1alice").Receiver("erd1contract").Kind(processor.KindNormal).Build(),
+		processor.NewTransactionBuilder().Hash(fmt.Sprint("scr-", nonce)).Sender("erd1contract").Receiver("erd1alice").Kind(processor.KindSmartContractResult).Build(),
+	})
+}
+
+// newLocalBroker starts a broker in the process, leading the single partition of each topic and answering produce
+// requests with the given response.
+func newLocalBroker(t *testing.T, produce *sarama.MockProduceResponse, topics ...string) *sarama.MockBroker {
+	t.Helper()
+
+	broker := sarama.NewMockBroker(t, 1)
+	t.Cleanup(broker.Close)
+
+	metadata := sarama.NewMockMetadataResponse(t).SetBroker(broker.Addr(), broker.BrokerID()).SetController(broker.BrokerID())
+	for _, topic := range topics {
+		metadata.SetLeader(topic, 0, broker.BrokerID())
+	}
+
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest":       metadata,
+		"InitProducerIDRequest": sarama.NewMockWrapper(&sarama.InitProducerIDResponse{ProducerID: 1000}),
+		"ProduceRequest":        produce,
+	})
+
+	return broker
+}
+
+// producedTopics returns the topics the broker acknowledged messages for.
+func producedTopics(broker *sarama.MockBroker) []string {
+	seen := map[string]bool{}
+	topics := make([]string, 0)
+	for _, rr := range broker.History() {
+		if response, ok := rr.Response.(*sarama.ProduceResponse); ok {
+			for topic := range response.Blocks {
+				if !seen[topic] {
+					seen[topic] = true
+					topics = append(topics, topic)
+				}
+			}
+		}
+	}
+
+	sort.Strings(topics)
+
+	return topics
+}
+
+func TestSinkPublishesToALocalBroker(t *testing.T) {
+	oo := Options{}
+
+	tests := []struct {
+		name     string
+		topics   []string
+		opts     []Option
+		failing  string
+		expected []string
+		err      error
+	}{
+		{
+			name:     "default topic",
+			topics:   []string{"transactions"},
+			opts:     []Option{oo.Topic("transactions")},
+			expected: []string{"transactions"},
+		},
+		{
+			name:     "topic for kind",
+			topics:   []string{"transactions", "results"},
+			opts:     []Option{oo.Topic("transactions"), oo.TopicForKind(processor.KindSmartContractResult, "results")},
+			expected: []string{"results", "transactions"},
+		},
+		{
+			name:    "rejected message",
+			topics:  []string{"transactions"},
+			opts:    []Option{oo.Topic("transactions")},
+			failing: "transactions",
+			err:     sarama.ErrMessageSizeTooLarge,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			produce := sarama.NewMockProduceResponse(t).SetVersion(3)
+			if tt.failing != "" {
+				produce.SetError(tt.failing, 0, sarama.ErrMessageSizeTooLarge)
+			}
+
+			broker := newLocalBroker(t, produce, tt.topics...)
+
+			s, err := New([]string{broker.Addr()}, tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			defer s.Close()
+
+			err = s.Send(newTestBlock(1))
+			if tt.err != nil {
+				producerErrors := sarama.ProducerErrors{}
+				if !errors.As(err, &producerErrors) || len(producerErrors) == 0 || !errors.Is(producerErrors[0].Err, tt.err) {
+					t.Fatalf("expected %v, got %v", tt.err, err)
+				}
+
+				return
+			}
+
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if topics := producedTopics(broker); strings.Join(topics, ",") != strings.Join(tt.expected, ",") {
+				t.Fatalf("expected messages in %v, got %v", tt.expected, topics)
+			}
+		})
+	}
+}
+
+func TestNewRequiresATopic(t *testing.T) {
+	oo := Options{}
+	router := func(*processor.Block, *processor.Transaction) string {
+		return "transactions"
+	}
+
+	tests := []struct {
+		name string
+		opts []Option
+		err  error
+	}{
+		{name: "no topic", err: ErrNoTopic},
+		{name: "topics for some kinds only", opts: []Option{oo.TopicForKind(processor.KindSmartContractResult, "results")}, err: ErrNoTopic},
+		{name: "default topic", opts: []Option{oo.Topic("transactions")}},
+		{name: "router", opts: []Option{oo.TopicRouter(router)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := newLocalBroker(t, sarama.NewMockProduceResponse(t), "transactions")
+
+			s, err := New([]string{broker.Addr()}, tt.opts...)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			if s != nil {
+				_ = s.Close()
+			}
+		})
+	}
+}
+
+// TestSinkPublishesToKafka runs against the brokers of KAFKA_BROKERS, e.g. "localhost:9092", and checks the messages
+// read back from the topic.
+func TestSinkPublishesToKafka(t *testing.T) {
+	brokers := os.Getenv("KAFKA_BROKERS")
+	if brokers == "" {
+		t.Skip("KAFKA_BROKERS is not set")
+	}
+
+	topic := fmt.Sprintf("elrond-transaction-processor-test-%d", time.Now().UnixNano())
+
+	oo := Options{}
+	s, err := New(strings.Split(brokers, ","), oo.Topic(topic), oo.KeyBy(KeyByReceiver))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block := newTestBlock(7)
+	if err := s.Send(block); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	consumer, err := sarama.NewConsumer(strings.Split(brokers, ","), sarama.NewConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer consumer.Close()
+
+	partitions, err := consumer.Partitions(topic)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	received := map[string]*sarama.ConsumerMessage{}
+	for _, partition := range partitions {
+		pc, err := consumer.ConsumePartition(topic, partition, sarama.OffsetOldest)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		timeout := time.After(10 * time.Second)
+		for len(received) < len(block.Transactions()) {
+			select {
+			case m := <-pc.Messages():
+				message := Message{}
+				if err := json.Unmarshal(m.Value, &message); err != nil {
+					t.Fatal(err)
+				}
+
+				received[message.Transaction.Hash()] = m
+			case <-timeout:
+				t.Fatalf("received %d message(s) out of %d", len(received), len(block.Transactions()))
+			}
+		}
+
+		_ = pc.Close()
+	}
+
+	for _, tx := range block.Transactions() {
+		m, found := received[tx.Hash()]
+		if !found {
+			t.Fatalf("no message for %s", tx.Hash())
+		}
+
+		if string(m.Key) != tx.Receiver() {
+			t.Errorf("expected key %s for %s, got %s", tx.Receiver(), tx.Hash(), m.Key)
+		}
+
+		headers := map[string]string{}
+		for _, h := range m.Headers {
+			headers[string(h.Key)] = string(h.Value)
+		}
+
+		if headers[HeaderShard] != "1" || headers[HeaderNonce] != "7" || headers[HeaderBlockHash] != "block-7" || headers[HeaderKind] != string(tx.Kind()) {
+			t.Errorf("unexpected headers for %s: %v", tx.Hash(), headers)
+		}
+	}
+}
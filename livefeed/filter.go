@@ -0,0 +1,125 @@
+package livefeed
+
+import (
+	"errors"
+	"math/big"
+	"net/url"
+	"strings"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+var (
+	ErrInvalidMinimumValue = errors.New("minimum value is not a base 10 integer")
+	ErrInvalidArgument     = errors.New("argument is not formatted as name:value")
+)
+
+// Filter selects the transactions sent to a client. Empty criteria match every transaction.
+type Filter struct {
+	// Addresses matches the transactions sent or received by one of the addresses.
+	Addresses []string `json:"addresses,omitempty"`
+	// Function matches the transactions calling the function, see processor.Transaction.CallsFunction.
+	Function string `json:"function,omitempty"`
+	// MinValue matches the transactions transferring at least this value, in the smallest denomination.
+	MinValue string `json:"minValue,omitempty"`
+	// Arguments matches the transactions whose decoded arguments have these values, see
+	// processor.Transaction.DecodedArgument.
+	Arguments map[string]string `json:"arguments,omitempty"`
+
+	addresses map[string]bool
+	minValue  *big.Int
+}
+
+// FilterFromQuery reads a filter from the "address" (repeatable or comma separated), "function", "minValue" and
+// "argument" (repeatable, as name:value) query parameters.
+func FilterFromQuery(q url.Values) (*Filter, error) {
+	f := &Filter{
+		Function: q.Get("function"),
+		MinValue: q.Get("minValue"),
+	}
+
+	for _, a := range q["address"] {
+		for _, address := range strings.Split(a, ",") {
+			if address = strings.TrimSpace(address); address != "" {
+				f.Addresses = append(f.Addresses, address)
+			}
+		}
+	}
+
+	for _, a := range q["argument"] {
+		parts := strings.SplitN(a, ":", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, ErrInvalidArgument
+		}
+
+		if f.Arguments == nil {
+			f.Arguments = map[string]string{}
+		}
+
+		f.Arguments[parts[0]] = parts[1]
+	}
+
+	if err := f.compile(); err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+func (f *Filter) compile() error {
+	f.addresses = nil
+	if len(f.Addresses) != 0 {
+		f.addresses = map[string]bool{}
+		for _, a := range f.Addresses {
+			f.addresses[a] = true
+		}
+	}
+
+	f.minValue = nil
+	if f.MinValue != "" {
+		v, ok := new(big.Int).SetString(f.MinValue, 10)
+		if !ok {
+			return ErrInvalidMinimumValue
+		}
+
+		f.minValue = v
+	}
+
+	return nil
+}
+
+// Matches reports whether the transaction, or the inner transaction of a relayed transaction, matches the filter.
+func (f *Filter) Matches(tx *processor.Transaction) bool {
+	if f.matches(tx) {
+		return true
+	}
+
+	inner := tx.InnerTransaction()
+
+	return inner != nil && f.matches(inner)
+}
+
+func (f *Filter) matches(tx *processor.Transaction) bool {
+	if f.addresses != nil && !f.addresses[tx.Sender()] && !f.addresses[tx.Receiver()] {
+		return false
+	}
+
+	if f.Function != "" && !tx.CallsFunction(f.Function) {
+		return false
+	}
+
+	for name, value := range f.Arguments {
+		if v, found := tx.DecodedArgument(name); !found || v != value {
+			return false
+		}
+	}
+
+	if f.minValue != nil {
+		v, ok := new(big.Int).SetString(tx.Value(), 10)
+		if !ok || v.Cmp(f.minValue) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
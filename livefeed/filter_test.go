@@ -0,0 +1,114 @@
+package livefeed
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/url"
+	"testing"
+
+	"github.com/thefabric-io/elrond-transaction-processor/bech32"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const (
+	testCaller       = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	testPairContract = "erd1qqqqqqqqqqqqqpgqeel2kumf0r8ffyhth7pqdujjat9nx0862jpsg2pqaq"
+)
+
+func newTestTransaction(hash, sender, receiver, value, data string) *processor.Transaction {
+	return processor.NewTransactionBuilder().Hash(hash).Sender(sender).Receiver(receiver).Value(value).Data(base64.StdEncoding.EncodeToString([]byte(data))).Status("success").Build()
+}
+
+func TestFilterFromQuery(t *testing.T) {
+	tests := []struct {
+		name      string
+		query     string
+		addresses []string
+		function  string
+		arguments map[string]string
+		err       error
+	}{
+		{name: "no criteria"},
+		{name: "repeated and comma separated addresses", query: "address=a,%20b&address=c&address=", addresses: []string{"a", "b", "c"}},
+		{name: "function", query: "function=swapTokensFixedInput", function: "swapTokensFixedInput"},
+		{name: "arguments", query: "argument=token_out:USDC-c76f1f&argument=memo:a:b", arguments: map[string]string{"token_out": "USDC-c76f1f", "memo": "a:b"}},
+		{name: "argument without value", query: "argument=token_out", err: ErrInvalidArgument},
+		{name: "argument without name", query: "argument=:USDC-c76f1f", err: ErrInvalidArgument},
+		{name: "minimum value", query: "minValue=1000000000000000000"},
+		{name: "minimum value not an integer", query: "minValue=1.5", err: ErrInvalidMinimumValue},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q, err := url.ParseQuery(tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			f, err := FilterFromQuery(q)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected the error %v, got %v", tt.err, err)
+			}
+
+			if err != nil {
+				return
+			}
+
+			if fmt.Sprint(f.Addresses) != fmt.Sprint(tt.addresses) || f.Function != tt.function || fmt.Sprint(f.Arguments) != fmt.Sprint(tt.arguments) {
+				t.Fatalf("unexpected filter %+v", f)
+			}
+		})
+	}
+}
+
+func TestFilterMatches(t *testing.T) {
+	relayer, err := bech32.Encode("erd", bytes.Repeat([]byte{0x42}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, pairKey, err := bech32.Decode(testPairContract)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	swap := newTestTransaction("swap", testCaller, testPairContract, "0", "ESDTTransfer@5745474c442d626434643739@0de0b6b3a7640000@73776170546f6b656e734669786564496e707574@555344432d633736663166@01")
+	transfer := newTestTransaction("transfer", testCaller, relayer, "1000000000000000000", "")
+	relayed := newTestTransaction("relayed", relayer, testCaller, "0", "relayedTxV2@"+hex.EncodeToString(pairKey)+"@01@"+hex.EncodeToString([]byte("claimRewards"))+"@"+hex.EncodeToString(bytes.Repeat([]byte{0x07}, 64)))
+
+	tests := []struct {
+		name     string
+		filter   Filter
+		tx       *processor.Transaction
+		expected bool
+	}{
+		{name: "no criteria", tx: swap, expected: true},
+		{name: "sender", filter: Filter{Addresses: []string{testCaller}}, tx: swap, expected: true},
+		{name: "receiver", filter: Filter{Addresses: []string{relayer, testPairContract}}, tx: swap, expected: true},
+		{name: "other address", filter: Filter{Addresses: []string{relayer}}, tx: swap},
+		{name: "function", filter: Filter{Function: "ESDTTransfer"}, tx: swap, expected: true},
+		{name: "function called along with a transfer not decoded", filter: Filter{Function: "swapTokensFixedInput"}, tx: swap},
+		{name: "value above the minimum", filter: Filter{MinValue: "1000000000000000000"}, tx: transfer, expected: true},
+		{name: "value below the minimum", filter: Filter{MinValue: "1000000000000000001"}, tx: transfer},
+		{name: "address and function of different transactions", filter: Filter{Addresses: []string{relayer}, Function: "swapTokensFixedInput"}, tx: transfer},
+		{name: "argument not decoded", filter: Filter{Arguments: map[string]string{"token_out": "USDC-c76f1f"}}, tx: swap},
+		{name: "inner transaction of a relayed transaction", filter: Filter{Addresses: []string{testPairContract}, Function: "claimRewards"}, tx: relayed, expected: true},
+		{name: "relayed transaction", filter: Filter{Addresses: []string{relayer}}, tx: relayed, expected: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := tt.filter
+			if err := f.compile(); err != nil {
+				t.Fatal(err)
+			}
+
+			if f.Matches(tt.tx) != tt.expected {
+				t.Fatalf("expected the filter to match %t", tt.expected)
+			}
+		})
+	}
+}
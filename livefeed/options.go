@@ -0,0 +1,44 @@
+package livefeed
+
+import (
+	"net/http"
+	"time"
+)
+
+type Option func(*Server)
+
+type Options struct{}
+
+// HeartbeatInterval sets how often idle connections receive a heartbeat: a ping frame over WebSocket and a comment
+// line over Server-Sent Events.
+func (oo *Options) HeartbeatInterval(d time.Duration) Option {
+	return func(s *Server) {
+		if d > 0 {
+			s.heartbeatInterval = d
+		}
+	}
+}
+
+// ClientBuffer sets the number of events queued for a client before the SlowClientPolicy applies.
+func (oo *Options) ClientBuffer(n int) Option {
+	return func(s *Server) {
+		if n > 0 {
+			s.clientBuffer = n
+		}
+	}
+}
+
+// OnSlowClient sets what happens to a client whose queue is full.
+func (oo *Options) OnSlowClient(p SlowClientPolicy) Option {
+	return func(s *Server) {
+		s.slowClientPolicy = p
+	}
+}
+
+// CheckOrigin sets the function accepting or rejecting the origin of WebSocket handshakes. By default only same origin
+// requests are accepted.
+func (oo *Options) CheckOrigin(f func(r *http.Request) bool) Option {
+	return func(s *Server) {
+		s.upgrader.CheckOrigin = f
+	}
+}
@@ -0,0 +1,351 @@
+package livefeed
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const (
+	defaultHeartbeatInterval = 15 * time.Second
+	defaultClientBuffer      = 256
+	writeTimeout             = 10 * time.Second
+	maxFilterMessageSize     = 64 * 1024
+)
+
+// SlowClientPolicy decides what happens to a client that does not read its events as fast as they are produced.
+type SlowClientPolicy int
+
+const (
+	// DropEvents discards the events that do not fit in the queue of the client. The client is told how many events it
+	// missed with a "dropped" event.
+	DropEvents SlowClientPolicy = iota
+	// Disconnect closes the connection of the client.
+	Disconnect
+)
+
+const (
+	EventTransaction = "transaction"
+	EventDropped     = "dropped"
+)
+
+// Event is the JSON payload sent to the clients.
+type Event struct {
+	Type        string                 `json:"type"`
+	Shard       processor.Shard        `json:"shard"`
+	Nonce       processor.Nonce        `json:"nonce"`
+	BlockHash   string                 `json:"blockHash"`
+	Transaction *processor.Transaction `json:"transaction,omitempty"`
+	Dropped     int64                  `json:"dropped,omitempty"`
+}
+
+// NewServer returns an http.Handler streaming the transactions handed to OnTransactionsReceived to its clients, over
+// WebSocket when the request is a WebSocket handshake and over Server-Sent Events otherwise. Each client sets its filter
+// with the query parameters of the request, see FilterFromQuery, and WebSocket clients may replace it at any time by
+// sending a Filter as a JSON message.
+func NewServer(opts ...Option) *Server {
+	s := &Server{
+		heartbeatInterval: defaultHeartbeatInterval,
+		clientBuffer:      defaultClientBuffer,
+		slowClientPolicy:  DropEvents,
+		clients:           map[*client]struct{}{},
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	return s
+}
+
+type Server struct {
+	mu                sync.Mutex
+	upgrader          websocket.Upgrader
+	heartbeatInterval time.Duration
+	clientBuffer      int
+	slowClientPolicy  SlowClientPolicy
+	clients           map[*client]struct{}
+}
+
+// OnTransactionsReceived feeds the server, it is meant to be given to the processor through the
+// OnTransactionsReceived option.
+func (s *Server) OnTransactionsReceived(shard processor.Shard, nonce processor.Nonce, transactions []*processor.Transaction, blockHash string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, tx := range transactions {
+		var data []byte
+
+		for c := range s.clients {
+			if !c.matches(tx) {
+				continue
+			}
+
+			if data == nil {
+				var err error
+
+				data, err = json.Marshal(Event{
+					Type:        EventTransaction,
+					Shard:       shard,
+					Nonce:       nonce,
+					BlockHash:   blockHash,
+					Transaction: tx,
+				})
+				if err != nil {
+					log.Printf("could not encode transaction %s for the live feed: %v\n", tx.Hash(), err)
+
+					break
+				}
+			}
+
+			if c.push(data) {
+				continue
+			}
+
+			if s.slowClientPolicy == Disconnect {
+				s.remove(c)
+			} else {
+				atomic.AddInt64(&c.dropped, 1)
+			}
+		}
+	}
+}
+
+// Clients returns the number of connected clients.
+func (s *Server) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return len(s.clients)
+}
+
+// Close disconnects every client.
+func (s *Server) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for c := range s.clients {
+		s.remove(c)
+	}
+
+	return nil
+}
+
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	filter, err := FilterFromQuery(r.URL.Query())
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+
+		return
+	}
+
+	if websocket.IsWebSocketUpgrade(r) {
+		s.serveWebSocket(w, r, filter)
+
+		return
+	}
+
+	s.serveEvents(w, r, filter)
+}
+
+func (s *Server) serveWebSocket(w http.ResponseWriter, r *http.Request, filter *Filter) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+
+	defer conn.Close()
+
+	c := s.register(filter)
+	defer s.unregister(c)
+
+	go s.readFilters(conn, c)
+
+	heartbeat := time.NewTicker(s.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-c.done:
+			_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""), time.Now().Add(writeTimeout))
+
+			return
+		case data := <-c.events:
+			_ = conn.SetWriteDeadline(time.Now().Add(writeTimeout))
+
+			if notice := c.droppedNotice(); notice != nil {
+				if err := conn.WriteMessage(websocket.TextMessage, notice); err != nil {
+					return
+				}
+			}
+
+			if err := conn.WriteMessage(websocket.TextMessage, data); err != nil {
+				return
+			}
+		case <-heartbeat.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(writeTimeout)); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// readFilters reads the filters sent by a WebSocket client until the connection breaks or the client stops answering
+// the heartbeats.
+func (s *Server) readFilters(conn *websocket.Conn, c *client) {
+	defer c.close()
+
+	conn.SetReadLimit(maxFilterMessageSize)
+	_ = conn.SetReadDeadline(time.Now().Add(2 * s.heartbeatInterval))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(2 * s.heartbeatInterval))
+	})
+
+	for {
+		_, message, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+
+		filter := &Filter{}
+		if err := json.Unmarshal(message, filter); err != nil {
+			continue
+		}
+
+		if err := filter.compile(); err != nil {
+			continue
+		}
+
+		c.setFilter(filter)
+	}
+}
+
+func (s *Server) serveEvents(w http.ResponseWriter, r *http.Request, filter *Filter) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	c := s.register(filter)
+	defer s.unregister(c)
+
+	heartbeat := time.NewTicker(s.heartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-c.done:
+			return
+		case data := <-c.events:
+			if notice := c.droppedNotice(); notice != nil {
+				if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", EventDropped, notice); err != nil {
+					return
+				}
+			}
+
+			if _, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", EventTransaction, data); err != nil {
+				return
+			}
+
+			flusher.Flush()
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(w, ": heartbeat\n\n"); err != nil {
+				return
+			}
+
+			flusher.Flush()
+		}
+	}
+}
+
+func (s *Server) register(filter *Filter) *client {
+	c := &client{
+		filter: filter,
+		events: make(chan []byte, s.clientBuffer),
+		done:   make(chan struct{}),
+	}
+
+	s.mu.Lock()
+	s.clients[c] = struct{}{}
+	s.mu.Unlock()
+
+	return c
+}
+
+func (s *Server) unregister(c *client) {
+	s.mu.Lock()
+	s.remove(c)
+	s.mu.Unlock()
+}
+
+func (s *Server) remove(c *client) {
+	delete(s.clients, c)
+	c.close()
+}
+
+type client struct {
+	mu        sync.Mutex
+	filter    *Filter
+	events    chan []byte
+	dropped   int64
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+func (c *client) matches(tx *processor.Transaction) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.filter.Matches(tx)
+}
+
+func (c *client) setFilter(f *Filter) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.filter = f
+}
+
+func (c *client) push(data []byte) bool {
+	select {
+	case c.events <- data:
+		return true
+	default:
+		return false
+	}
+}
+
+func (c *client) droppedNotice() []byte {
+	dropped := atomic.SwapInt64(&c.dropped, 0)
+	if dropped == 0 {
+		return nil
+	}
+
+	data, _ := json.Marshal(Event{Type: EventDropped, Dropped: dropped})
+
+	return data
+}
+
+func (c *client) close() {
+	c.closeOnce.Do(func() {
+		close(c.done)
+	})
+}
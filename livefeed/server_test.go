@@ -0,0 +1,256 @@
+package livefeed
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+func newTestFilter(t *testing.T, f Filter) *Filter {
+	t.Helper()
+
+	if err := f.compile(); err != nil {
+		t.Fatal(err)
+	}
+
+	return &f
+}
+
+// received returns the hashes of the transactions queued for the client and the dropped event it is sent next.
+func received(t *testing.T, c *client) ([]string, int64) {
+	t.Helper()
+
+	dropped := int64(0)
+	if notice := c.droppedNotice(); notice != nil {
+		e := Event{}
+		if err := json.Unmarshal(notice, &e); err != nil || e.Type != EventDropped {
+			t.Fatalf("unexpected dropped event %s: %v", notice, err)
+		}
+
+		dropped = e.Dropped
+	}
+
+	hashes := make([]string, 0)
+	for {
+		select {
+		case data := <-c.events:
+			e := struct {
+				Type        string          `json:"type"`
+				BlockHash   string          `json:"blockHash"`
+				Transaction json.RawMessage `json:"transaction"`
+			}{}
+			tx := &processor.Transaction{}
+			if err := json.Unmarshal(data, &e); err != nil || e.Type != EventTransaction || e.BlockHash != "block" {
+				t.Fatalf("unexpected event %s: %v", data, err)
+			}
+
+			if err := json.Unmarshal(e.Transaction, tx); err != nil {
+				t.Fatal(err)
+			}
+
+			hashes = append(hashes, tx.Hash())
+		default:
+			return hashes, dropped
+		}
+	}
+}
+
+func TestServerSendsMatchingTransactions(t *testing.T) {
+	transactions := []*processor.Transaction{
+		newTestTransaction("swap", testCaller, testPairContract, "0", "ESDTTransfer@555344432d633736663166@0f4240"),
+		newTestTransaction("transfer", testPairContract, testCaller, "1000000000000000000", ""),
+		newTestTransaction("claim", testCaller, testPairContract, "0", "claimRewards"),
+	}
+
+	tests := []struct {
+		name     string
+		policy   SlowClientPolicy
+		buffer   int
+		filter   Filter
+		expected []string
+		dropped  int64
+		closed   bool
+	}{
+		{name: "every transaction", buffer: 10, expected: []string{"swap", "transfer", "claim"}},
+		{name: "transactions of a function", buffer: 10, filter: Filter{Function: "claimRewards"}, expected: []string{"claim"}},
+		{name: "transactions with a value", buffer: 10, filter: Filter{MinValue: "1"}, expected: []string{"transfer"}},
+		{name: "no matching transaction", buffer: 10, filter: Filter{Addresses: []string{"erd1other"}}, expected: []string{}},
+		{name: "dropped events", policy: DropEvents, buffer: 1, expected: []string{"swap"}, dropped: 2},
+		{name: "events fitting the queue", policy: Disconnect, buffer: 3, expected: []string{"swap", "transfer", "claim"}},
+		{name: "disconnected", policy: Disconnect, buffer: 2, expected: []string{"swap", "transfer"}, closed: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := &Options{}
+			s := NewServer(opts.ClientBuffer(tt.buffer), opts.OnSlowClient(tt.policy))
+
+			c := s.register(newTestFilter(t, tt.filter))
+			other := s.register(newTestFilter(t, Filter{Function: "claimRewards"}))
+
+			s.OnTransactionsReceived(1, 10, transactions, "block")
+
+			hashes, dropped := received(t, c)
+			if strings.Join(hashes, " ") != strings.Join(tt.expected, " ") || dropped != tt.dropped {
+				t.Fatalf("expected %v with %d dropped, got %v with %d dropped", tt.expected, tt.dropped, hashes, dropped)
+			}
+
+			if _, dropped := received(t, c); dropped != 0 {
+				t.Fatalf("expected the dropped events to be reported once, got %d again", dropped)
+			}
+
+			select {
+			case <-c.done:
+				if !tt.closed {
+					t.Fatal("expected the client to stay connected")
+				}
+			default:
+				if tt.closed {
+					t.Fatal("expected the client to be disconnected")
+				}
+			}
+
+			/* The other client is unaffected by the filter and the queue of the first one */
+			if hashes, dropped := received(t, other); strings.Join(hashes, " ") != "claim" || dropped != 0 {
+				t.Fatalf("expected the other client to receive its transaction, got %v with %d dropped", hashes, dropped)
+			}
+
+			expectedClients := 2
+			if tt.closed {
+				expectedClients = 1
+			}
+
+			if s.Clients() != expectedClients {
+				t.Fatalf("expected %d clients, got %d", expectedClients, s.Clients())
+			}
+		})
+	}
+}
+
+func waitForClients(t *testing.T, s *Server, n int) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for s.Clients() != n {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %d clients, got %d", n, s.Clients())
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestServerStreamsServerSentEvents(t *testing.T) {
+	s := NewServer()
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	res, err := http.Get(server.URL + "?function=claimRewards")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer res.Body.Close()
+
+	if res.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected content type %s", res.Header.Get("Content-Type"))
+	}
+
+	waitForClients(t, s, 1)
+
+	s.OnTransactionsReceived(1, 10, []*processor.Transaction{
+		newTestTransaction("swap", testCaller, testPairContract, "0", "ESDTTransfer@555344432d633736663166@0f4240"),
+		newTestTransaction("claim", testCaller, testPairContract, "0", "claimRewards"),
+	}, "block")
+
+	r := bufio.NewReader(res.Body)
+	for _, expected := range []string{"event: transaction\n", "data: "} {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !strings.HasPrefix(line, expected) {
+			t.Fatalf("expected a line starting with %q, got %q", expected, line)
+		}
+
+		if expected == "data: " && !strings.Contains(line, `"hash":"claim"`) {
+			t.Fatalf("expected the claim transaction, got %s", line)
+		}
+	}
+
+	if err := s.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	waitForClients(t, s, 0)
+}
+
+func TestServerReplacesWebSocketFilters(t *testing.T) {
+	s := NewServer()
+	server := httptest.NewServer(s)
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"?minValue=1", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer conn.Close()
+
+	waitForClients(t, s, 1)
+
+	if err := conn.WriteJSON(Filter{Function: "claimRewards"}); err != nil {
+		t.Fatal(err)
+	}
+
+	/* The filter is replaced asynchronously, the transactions are sent until the new filter applies */
+	_ = conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+	received, stop := make(chan string), make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		defer close(received)
+
+		for {
+			e := struct {
+				Transaction struct {
+					Hash string `json:"hash"`
+				} `json:"transaction"`
+			}{}
+			if err := conn.ReadJSON(&e); err != nil {
+				return
+			}
+
+			select {
+			case received <- e.Transaction.Hash:
+			case <-stop:
+				return
+			}
+		}
+	}()
+
+	transfer := newTestTransaction("transfer", testPairContract, testCaller, "1000000000000000000", "")
+	claim := newTestTransaction("claim", testCaller, testPairContract, "0", "claimRewards")
+
+	for {
+		s.OnTransactionsReceived(1, 10, []*processor.Transaction{claim, transfer}, "block")
+
+		hash, ok := <-received
+		if !ok {
+			t.Fatal("the connection closed before the filter was replaced")
+		}
+
+		if hash == "claim" {
+			break
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package nftindex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+)
+
+// NewHandler returns the HTTP query API of the index:
+//
+//	GET /tokens/{identifier}
+//	GET /collections/{collection}/tokens
+//	GET /collections/{collection}/owners
+//	GET /addresses/{address}/tokens
+//	GET /snapshot
+//
+// The owners of a collection are the holdings of each owner, keyed by address. The snapshot is the whole index, as
+// stored in the file of an index opened with Open.
+func NewHandler(i *Index) http.Handler {
+	return &handler{index: i}
+}
+
+type handler struct {
+	index *Index
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 2 && parts[0] == "tokens":
+		token, err := h.index.Token(parts[1])
+		writeResult(w, token, err)
+	case len(parts) == 3 && parts[0] == "collections" && parts[2] == "tokens":
+		tokens, err := h.index.Collection(parts[1])
+		writeResult(w, tokens, err)
+	case len(parts) == 3 && parts[0] == "collections" && parts[2] == "owners":
+		owners, err := h.index.Owners(parts[1])
+		writeResult(w, owners, err)
+	case len(parts) == 3 && parts[0] == "addresses" && parts[2] == "tokens":
+		writeResult(w, h.index.Holdings(parts[1]), nil)
+	case len(parts) == 1 && parts[0] == "snapshot":
+		writeResult(w, h.index.Snapshot(), nil)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	switch {
+	case errors.Is(err, ErrInvalidIdentifier):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrTokenNotFound), errors.Is(err, ErrCollectionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeResult(w http.ResponseWriter, v interface{}, err error) {
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,420 @@
+package nftindex
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"sort"
+	"sync"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const defaultJournalSize = 100
+
+// New returns an index kept in memory. The index is a processor.Sink applying the non-fungible and semi-fungible token
+// operations of the blocks to the supply and the balances of each token nonce.
+//
+// The operations are decoded from the data of the transactions by default, which misses the tokens created, burnt or
+// added by the contracts, and infers the nonce of a created token from the last nonce created in its collection: the
+// index must then see the collections from their first creation. FromLogs decodes the events of the logs instead.
+//
+// A sender gives at most the balance known to the index: the quantity of a token it held before the index started is
+// marked as untracked in the operation, and only added to the supply when it is transferred.
+//
+// The operations of the last blocks of each shard are kept in a journal: a block delivered again is ignored, and a
+// block replacing another one of the same nonce rolls back the operations of the replaced block and of the blocks
+// after it before being applied.
+func New(opts ...Option) *Index {
+	i := &Index{
+		journalSize: defaultJournalSize,
+		collections: map[string]*collection{},
+		owners:      map[string]map[string]bool{},
+		journal:     map[processor.Shard][]*BlockOperations{},
+		heads:       processor.NonceByShard{},
+	}
+
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i
+}
+
+// Open returns an index restored from the snapshot stored in the file at path, if any. A snapshot of the index is
+// written to the file whenever the processor flushes its sinks, so that it matches the position persisted by the
+// processor.
+func Open(path string, opts ...Option) (*Index, error) {
+	i := New(opts...)
+	i.path = path
+
+	content, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return i, nil
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not read token index %s: %w", path, err)
+	}
+
+	s := &Snapshot{}
+	if err := json.Unmarshal(content, s); err != nil {
+		return nil, fmt.Errorf("could not decode token index %s: %w", path, err)
+	}
+
+	i.Restore(s)
+
+	return i, nil
+}
+
+type Index struct {
+	mu          sync.RWMutex
+	path        string
+	journalSize int
+	fromLogs    bool
+	filter      map[string]bool
+	onOperation OnOperationFunc
+	collections map[string]*collection
+	owners      map[string]map[string]bool
+	journal     map[processor.Shard][]*BlockOperations
+	heads       processor.NonceByShard
+	dirty       bool
+}
+
+func (i *Index) Send(block *processor.Block) error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	header := block.Header()
+	shard, nonce := header.Shard(), header.Nonce()
+
+	if previous := i.journalBlock(shard, nonce); previous != nil {
+		if previous.Hash == header.Hash() {
+			return nil
+		}
+
+		i.rollback(shard, nonce)
+	} else if head, found := i.heads[shard]; found && nonce <= head {
+		return nil
+	}
+
+	b := &BlockOperations{Shard: shard, Nonce: nonce, Hash: header.Hash()}
+
+	for _, tx := range block.Transactions() {
+		for _, o := range i.decode(tx) {
+			if o.Collection == "" || len(i.filter) != 0 && !i.filter[o.Collection] {
+				continue
+			}
+
+			o.Hash = tx.Hash()
+			o.Shard, o.BlockNonce, o.BlockHash, o.Timestamp = shard, nonce, header.Hash(), header.Timestamp()
+
+			i.apply(o, false)
+			b.Operations = append(b.Operations, o)
+		}
+	}
+
+	i.journal[shard] = append(i.journal[shard], b)
+	if len(i.journal[shard]) > i.journalSize {
+		i.journal[shard] = i.journal[shard][len(i.journal[shard])-i.journalSize:]
+	}
+
+	i.heads[shard] = nonce
+	i.dirty = true
+
+	if i.onOperation != nil {
+		for _, o := range b.Operations {
+			i.onOperation(o)
+		}
+	}
+
+	return nil
+}
+
+// Flush writes the snapshot of the index to its file when it changed since the last flush.
+func (i *Index) Flush() error {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	if i.path == "" || !i.dirty {
+		return nil
+	}
+
+	content, err := json.Marshal(i.snapshot())
+	if err != nil {
+		return err
+	}
+
+	/* The snapshot replaces the previous one at once, a crash while writing it leaves the previous one intact */
+	temporary := i.path + ".tmp"
+	if err := os.WriteFile(temporary, content, 0600); err != nil {
+		return fmt.Errorf("could not write token index %s: %w", temporary, err)
+	}
+
+	if err := os.Rename(temporary, i.path); err != nil {
+		return fmt.Errorf("could not write token index %s: %w", i.path, err)
+	}
+
+	i.dirty = false
+
+	return nil
+}
+
+func (i *Index) Close() error {
+	return i.Flush()
+}
+
+// Snapshot returns a copy of the index.
+func (i *Index) Snapshot() *Snapshot {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	return i.snapshot()
+}
+
+// Restore replaces the content of the index with the snapshot.
+func (i *Index) Restore(s *Snapshot) {
+	i.mu.Lock()
+	defer i.mu.Unlock()
+
+	i.collections = map[string]*collection{}
+	i.owners = map[string]map[string]bool{}
+	i.journal = map[processor.Shard][]*BlockOperations{}
+	i.heads = processor.NonceByShard{}
+
+	for name, lastNonce := range s.LastNonces {
+		i.collection(name).lastNonce = lastNonce
+	}
+
+	for _, e := range s.Tokens {
+		t := importToken(e)
+		i.collection(e.Collection).tokens[e.Nonce] = t
+
+		for address := range t.balances {
+			i.own(address, e.Identifier, true)
+		}
+	}
+
+	for _, b := range s.Journal {
+		i.journal[b.Shard] = append(i.journal[b.Shard], b)
+	}
+
+	for shard, nonce := range s.Heads {
+		i.heads[shard] = nonce
+	}
+
+	i.dirty = true
+}
+
+func (i *Index) snapshot() *Snapshot {
+	s := &Snapshot{
+		Tokens:     make([]*Token, 0),
+		LastNonces: map[string]uint64{},
+		Heads:      processor.NonceByShard{},
+		Journal:    make([]*BlockOperations, 0),
+	}
+
+	names := make([]string, 0, len(i.collections))
+	for name := range i.collections {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	for _, name := range names {
+		c := i.collections[name]
+		s.LastNonces[name] = c.lastNonce
+
+		for _, nonce := range c.sortedNonces() {
+			s.Tokens = append(s.Tokens, c.tokens[nonce].export(name, nonce))
+		}
+	}
+
+	for shard, nonce := range i.heads {
+		s.Heads[shard] = nonce
+	}
+
+	shards := make(processor.Shards, 0, len(i.journal))
+	for shard := range i.journal {
+		shards = append(shards, shard)
+	}
+
+	sort.Slice(shards, func(a, b int) bool {
+		return shards[a] < shards[b]
+	})
+
+	for _, shard := range shards {
+		s.Journal = append(s.Journal, i.journal[shard]...)
+	}
+
+	return s
+}
+
+func (i *Index) decode(tx *processor.Transaction) []*Operation {
+	if i.fromLogs {
+		return operationsFromLogs(tx)
+	}
+
+	return operationsFromData(tx)
+}
+
+func (i *Index) journalBlock(shard processor.Shard, nonce processor.Nonce) *BlockOperations {
+	for _, b := range i.journal[shard] {
+		if b.Nonce == nonce {
+			return b
+		}
+	}
+
+	return nil
+}
+
+// rollback reverts the operations of the blocks of the shard from the nonce, the last operations first.
+func (i *Index) rollback(shard processor.Shard, nonce processor.Nonce) {
+	blocks := i.journal[shard]
+
+	kept := 0
+	for kept < len(blocks) && blocks[kept].Nonce < nonce {
+		kept++
+	}
+
+	for j := len(blocks) - 1; j >= kept; j-- {
+		for k := len(blocks[j].Operations) - 1; k >= 0; k-- {
+			o := blocks[j].Operations[k]
+			i.apply(o, true)
+
+			if i.onOperation != nil {
+				reverted := *o
+				reverted.Reverted = true
+				i.onOperation(&reverted)
+			}
+		}
+	}
+
+	i.journal[shard] = blocks[:kept]
+	i.heads[shard] = nonce - 1
+	i.dirty = true
+}
+
+// apply applies the operation to the token, or reverts it. The nonce of a creation decoded from the data of its
+// transaction is set from the last nonce created in the collection.
+func (i *Index) apply(o *Operation, revert bool) {
+	c := i.collection(o.Collection)
+	if o.Kind == KindCreate && o.Nonce == 0 {
+		o.Nonce = c.lastNonce + 1
+	}
+
+	t, found := c.tokens[o.Nonce]
+	if !found {
+		t = newToken()
+		c.tokens[o.Nonce] = t
+	}
+
+	quantity := o.quantity()
+
+	/* From gives at most its balance, the rest was held before the index started and is only known from now on */
+	if o.From != "" && !revert {
+		o.Untracked = ""
+		if held := t.balance(o.From); held.Cmp(quantity) < 0 {
+			o.Untracked = new(big.Int).Sub(quantity, held).String()
+		}
+	}
+
+	taken := new(big.Int).Sub(quantity, o.untracked())
+
+	supply := new(big.Int)
+	if o.To != "" {
+		supply.Add(supply, quantity)
+	}
+
+	if o.From != "" {
+		supply.Sub(supply, taken)
+	}
+
+	if revert {
+		quantity.Neg(quantity)
+		taken.Neg(taken)
+		supply.Neg(supply)
+	}
+
+	t.supply.Add(t.supply, supply)
+
+	identifier := o.Identifier()
+	if o.From != "" {
+		i.addBalance(t, identifier, o.From, taken.Neg(taken))
+	}
+
+	if o.To != "" {
+		i.addBalance(t, identifier, o.To, quantity)
+	}
+
+	if o.Kind == KindCreate {
+		if !revert {
+			t.creator, t.creationHash = o.To, o.Hash
+			if o.Nonce > c.lastNonce {
+				c.lastNonce = o.Nonce
+			}
+		} else {
+			t.creator, t.creationHash = "", ""
+			if c.lastNonce == o.Nonce {
+				c.lastNonce--
+			}
+		}
+	}
+
+	if t.creator == "" && t.supply.Sign() == 0 && len(t.balances) == 0 {
+		delete(c.tokens, o.Nonce)
+	}
+
+	if len(c.tokens) == 0 && c.lastNonce == 0 {
+		delete(i.collections, o.Collection)
+	}
+}
+
+func (i *Index) collection(name string) *collection {
+	c, found := i.collections[name]
+	if !found {
+		c = &collection{tokens: map[uint64]*token{}}
+		i.collections[name] = c
+	}
+
+	return c
+}
+
+func (i *Index) addBalance(t *token, identifier, address string, delta *big.Int) {
+	balance, found := t.balances[address]
+	if !found {
+		balance = new(big.Int)
+		t.balances[address] = balance
+	}
+
+	balance.Add(balance, delta)
+	if balance.Sign() <= 0 {
+		delete(t.balances, address)
+		i.own(address, identifier, false)
+
+		return
+	}
+
+	i.own(address, identifier, true)
+}
+
+func (i *Index) own(address, identifier string, owns bool) {
+	tokens, found := i.owners[address]
+	if !found && owns {
+		tokens = map[string]bool{}
+		i.owners[address] = tokens
+	}
+
+	if owns {
+		tokens[identifier] = true
+
+		return
+	}
+
+	delete(tokens, identifier)
+	if len(tokens) == 0 {
+		delete(i.owners, address)
+	}
+}
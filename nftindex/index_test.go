@@ -0,0 +1,189 @@
+package nftindex
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const testCollection = "COLL-a1b2c3"
+
+// call returns a transaction of the sender calling the built-in function with the arguments, hex encoded.
+func call(hash, sender, receiver, function string, arguments ...string) *processor.Transaction {
+	data := function
+	for _, a := range arguments {
+		data += "@" + a
+	}
+
+	return processor.NewTransactionBuilder().Hash(hash).Sender(sender).Receiver(receiver).Status("success").Data(base64.StdEncoding.EncodeToString([]byte(data))).Build()
+}
+
+func create(hash, creator string, quantity int) *processor.Transaction {
+	return call(hash, creator, creator, createFunction, hex.EncodeToString([]byte(testCollection)), fmt.Sprintf("%02x", quantity))
+}
+
+func transfer(hash, from, to string, nonce, quantity int) *processor.Transaction {
+	return call(hash, from, to, transferFunction, hex.EncodeToString([]byte(testCollection)), fmt.Sprintf("%02x", nonce), fmt.Sprintf("%02x", quantity))
+}
+
+func burn(hash, owner string, nonce, quantity int) *processor.Transaction {
+	return call(hash, owner, owner, burnFunction, hex.EncodeToString([]byte(testCollection)), fmt.Sprintf("%02x", nonce), fmt.Sprintf("%02x", quantity))
+}
+
+func newTestBlock(nonce processor.Nonce, hash string, txs ...*processor.Transaction) *processor.Block {
+	header := processor.NewBlockHeaderBuilder().Shard(1).Nonce(nonce).Hash(hash).Timestamp(time.Unix(1600000000, 0)).Build()
+
+	return processor.NewBlock(header, txs)
+}
+
+// stateOf returns the supply and the balances of each token of the index, and the tokens of each owner.
+func stateOf(i *Index) string {
+	state := make([]string, 0)
+	for _, t := range i.Snapshot().Tokens {
+		owners := make([]string, 0, len(t.Owners))
+		for _, b := range t.Owners {
+			owners = append(owners, b.Address+"="+b.Quantity)
+		}
+
+		state = append(state, fmt.Sprintf("%s supply=%s owners=[%s]", t.Identifier, t.Supply, strings.Join(owners, " ")))
+	}
+
+	for _, address := range []string{"erd1alice", "erd1bob", "erd1carol"} {
+		for _, h := range i.Holdings(address) {
+			state = append(state, fmt.Sprintf("%s holds %s", address, h.Identifier))
+		}
+	}
+
+	return strings.Join(state, "; ")
+}
+
+func TestIndexSend(t *testing.T) {
+	tests := []struct {
+		name     string
+		blocks   []*processor.Block
+		expected string
+	}{
+		{
+			name: "create, transfer and burn",
+			blocks: []*processor.Block{
+				newTestBlock(1, "a", create("tx-1", "erd1alice", 3)),
+				newTestBlock(2, "a", transfer("tx-2", "erd1alice", "erd1bob", 1, 2)),
+				newTestBlock(3, "a", burn("tx-3", "erd1alice", 1, 1)),
+			},
+			expected: "COLL-a1b2c3-01 supply=2 owners=[erd1bob=2]; erd1bob holds COLL-a1b2c3-01",
+		},
+		{
+			name: "transfer of a token created before the index started",
+			blocks: []*processor.Block{
+				newTestBlock(1, "a", transfer("tx-1", "erd1alice", "erd1bob", 7, 2)),
+			},
+			expected: "COLL-a1b2c3-07 supply=2 owners=[erd1bob=2]; erd1bob holds COLL-a1b2c3-07",
+		},
+		{
+			name: "transfer of more than the known balance",
+			blocks: []*processor.Block{
+				newTestBlock(1, "a", create("tx-1", "erd1alice", 1)),
+				newTestBlock(2, "a", transfer("tx-2", "erd1alice", "erd1bob", 1, 3)),
+			},
+			expected: "COLL-a1b2c3-01 supply=3 owners=[erd1bob=3]; erd1bob holds COLL-a1b2c3-01",
+		},
+		{
+			name: "burn of a token created before the index started",
+			blocks: []*processor.Block{
+				newTestBlock(1, "a", burn("tx-1", "erd1alice", 7, 2)),
+			},
+			expected: "",
+		},
+		{
+			name: "duplicate block",
+			blocks: []*processor.Block{
+				newTestBlock(1, "a", create("tx-1", "erd1alice", 3)),
+				newTestBlock(2, "a", transfer("tx-2", "erd1alice", "erd1bob", 1, 1)),
+				newTestBlock(2, "a", transfer("tx-2", "erd1alice", "erd1bob", 1, 1)),
+				newTestBlock(1, "a", create("tx-1", "erd1alice", 3)),
+			},
+			expected: "COLL-a1b2c3-01 supply=3 owners=[erd1alice=2 erd1bob=1]; erd1alice holds COLL-a1b2c3-01; erd1bob holds COLL-a1b2c3-01",
+		},
+		{
+			name: "reorg rolls back the replaced blocks",
+			blocks: []*processor.Block{
+				newTestBlock(1, "a", create("tx-1", "erd1alice", 3)),
+				newTestBlock(2, "a", transfer("tx-2", "erd1alice", "erd1bob", 1, 1), transfer("tx-3", "erd1carol", "erd1bob", 7, 2)),
+				newTestBlock(3, "a", burn("tx-4", "erd1bob", 1, 1), create("tx-5", "erd1alice", 1)),
+				newTestBlock(2, "b", transfer("tx-6", "erd1alice", "erd1carol", 1, 2)),
+			},
+			expected: "COLL-a1b2c3-01 supply=3 owners=[erd1alice=1 erd1carol=2]; erd1alice holds COLL-a1b2c3-01; erd1carol holds COLL-a1b2c3-01",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := New()
+			for _, block := range tt.blocks {
+				if err := i.Send(block); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if state := stateOf(i); state != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, state)
+			}
+		})
+	}
+}
+
+func TestIndexRestore(t *testing.T) {
+	i := New()
+	blocks := []*processor.Block{
+		newTestBlock(1, "a", create("tx-1", "erd1alice", 3)),
+		newTestBlock(2, "a", transfer("tx-2", "erd1alice", "erd1bob", 1, 1), transfer("tx-3", "erd1carol", "erd1bob", 7, 2)),
+	}
+
+	for _, block := range blocks {
+		if err := i.Send(block); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	content, err := json.Marshal(i.Snapshot())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &Snapshot{}
+	if err := json.Unmarshal(content, s); err != nil {
+		t.Fatal(err)
+	}
+
+	restored := New()
+	restored.Restore(s)
+
+	if expected, state := stateOf(i), stateOf(restored); state != expected {
+		t.Fatalf("expected %q, got %q", expected, state)
+	}
+
+	/* The journal restored with the snapshot rolls back a block replaced after the restore */
+	if err := restored.Send(newTestBlock(2, "b")); err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "COLL-a1b2c3-01 supply=3 owners=[erd1alice=3]; erd1alice holds COLL-a1b2c3-01"
+	if state := stateOf(restored); state != expected {
+		t.Fatalf("expected %q, got %q", expected, state)
+	}
+
+	/* A block already applied before the snapshot is ignored */
+	if err := restored.Send(blocks[0]); err != nil {
+		t.Fatal(err)
+	}
+
+	if state := stateOf(restored); state != expected {
+		t.Fatalf("expected %q, got %q", expected, state)
+	}
+}
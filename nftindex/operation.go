@@ -0,0 +1,317 @@
+// Package nftindex follows the lifecycle of the non-fungible and semi-fungible tokens, from their creation to their
+// burn, and keeps the supply and the balances of the owners of each token nonce.
+package nftindex
+
+import (
+	"bytes"
+	"encoding/hex"
+	"math/big"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/bech32"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const (
+	addressHRP = "erd"
+	// smartContractPrefixLength is the number of zero bytes starting the public keys of the smart contracts.
+	smartContractPrefixLength = 8
+)
+
+// Kind is the operation applied to a token.
+type Kind string
+
+const (
+	// KindCreate creates a token nonce with its initial quantity, owned by its creator.
+	KindCreate Kind = "create"
+	// KindAddQuantity adds a quantity of a semi-fungible token to the balance of its owner.
+	KindAddQuantity Kind = "add-quantity"
+	// KindBurn burns a quantity of the balance of the owner.
+	KindBurn Kind = "burn"
+	// KindTransfer transfers a quantity from an owner to another, single or multiple transfers alike.
+	KindTransfer Kind = "transfer"
+)
+
+// The built-in functions and the identifiers of the events they log.
+const (
+	createFunction        = "ESDTNFTCreate"
+	addQuantityFunction   = "ESDTNFTAddQuantity"
+	burnFunction          = "ESDTNFTBurn"
+	transferFunction      = "ESDTNFTTransfer"
+	multiTransferFunction = "MultiESDTNFTTransfer"
+)
+
+// Operation is a change of the supply or of the balances of a token nonce. The quantity is subtracted from the balance
+// of From and added to the balance of To, the supply grows when only To is set and shrinks when only From is set.
+type Operation struct {
+	Kind       Kind   `json:"kind"`
+	Hash       string `json:"hash"`
+	Collection string `json:"collection"`
+	// Nonce is the nonce of the token in its collection. It is inferred from the last nonce created in the collection
+	// for the creations decoded from the data of the transactions.
+	Nonce    uint64 `json:"nonce"`
+	Quantity string `json:"quantity"`
+	From     string `json:"from,omitempty"`
+	To       string `json:"to,omitempty"`
+	// Untracked is the part of the quantity that From held before the index started, e.g. a token created before the
+	// first block applied to the index. It is not taken from the balance of From, and a transfer adds it to the supply.
+	Untracked string `json:"untracked,omitempty"`
+	// Reverted is set when the operation is rolled back because its block was replaced.
+	Reverted   bool            `json:"reverted,omitempty"`
+	Shard      processor.Shard `json:"shard"`
+	BlockNonce processor.Nonce `json:"blockNonce"`
+	BlockHash  string          `json:"blockHash"`
+	Timestamp  time.Time       `json:"timestamp"`
+}
+
+// Identifier returns the identifier of the token nonce, e.g. "COLL-a1b2c3-0f".
+func (o *Operation) Identifier() string {
+	return Identifier(o.Collection, o.Nonce)
+}
+
+func (o *Operation) quantity() *big.Int {
+	q, ok := new(big.Int).SetString(o.Quantity, 10)
+	if !ok {
+		return new(big.Int)
+	}
+
+	return q
+}
+
+func (o *Operation) untracked() *big.Int {
+	q, ok := new(big.Int).SetString(o.Untracked, 10)
+	if !ok {
+		return new(big.Int)
+	}
+
+	return q
+}
+
+type OnOperationFunc func(o *Operation)
+
+// operationsFromData decodes the built-in function called by a transaction, or by a smart contract result a contract
+// sent to transfer tokens. The results sent by the other accounts relay a transfer already seen in its transaction.
+func operationsFromData(tx *processor.Transaction) []*Operation {
+	if tx.Status() == "fail" || tx.Status() == "invalid" {
+		return nil
+	}
+
+	call := tx
+	if inner := tx.InnerTransaction(); inner != nil {
+		call = inner
+	}
+
+	result := tx.HasOriginalTransactionHash()
+	if result && !isSmartContractAddress(tx.Sender()) {
+		return nil
+	}
+
+	sender, arguments := call.Sender(), call.Arguments()
+
+	switch function := call.Function(); {
+	case function == createFunction && !result:
+		// ESDTNFTCreate@token@quantity@name@royalties@hash@attributes@uris...
+		if len(arguments) < 2 {
+			return nil
+		}
+
+		return []*Operation{{Kind: KindCreate, Collection: decodeString(arguments[0]), Quantity: decodeQuantity(arguments[1]), To: sender}}
+	case function == addQuantityFunction && !result:
+		// ESDTNFTAddQuantity@token@nonce@quantity
+		if len(arguments) < 3 {
+			return nil
+		}
+
+		return []*Operation{{Kind: KindAddQuantity, Collection: decodeString(arguments[0]), Nonce: decodeNonce(arguments[1]), Quantity: decodeQuantity(arguments[2]), To: sender}}
+	case function == burnFunction && !result:
+		// ESDTNFTBurn@token@nonce@quantity
+		if len(arguments) < 3 {
+			return nil
+		}
+
+		return []*Operation{{Kind: KindBurn, Collection: decodeString(arguments[0]), Nonce: decodeNonce(arguments[1]), Quantity: decodeQuantity(arguments[2]), From: sender}}
+	case function == transferFunction:
+		// ESDTNFTTransfer@token@nonce@quantity@destination@..., sent to the sender itself. The results carrying a
+		// transfer to another shard are sent to the destination directly.
+		if len(arguments) < 3 {
+			return nil
+		}
+
+		destination := call.Receiver()
+		if destination == sender {
+			if len(arguments) < 4 {
+				return nil
+			}
+
+			destination = decodeAddress(arguments[3])
+		}
+
+		if destination == "" {
+			return nil
+		}
+
+		return []*Operation{{Kind: KindTransfer, Collection: decodeString(arguments[0]), Nonce: decodeNonce(arguments[1]), Quantity: decodeQuantity(arguments[2]), From: sender, To: destination}}
+	case function == multiTransferFunction:
+		// MultiESDTNFTTransfer@destination@count@(token@nonce@quantity)*count@..., sent to the sender itself. The
+		// destination is omitted from the results carrying the transfers to another shard.
+		destination := call.Receiver()
+		if destination == sender {
+			if len(arguments) == 0 {
+				return nil
+			}
+
+			destination, arguments = decodeAddress(arguments[0]), arguments[1:]
+		}
+
+		if destination == "" || len(arguments) == 0 {
+			return nil
+		}
+
+		count := decodeNonce(arguments[0])
+		operations := make([]*Operation, 0)
+		for i := 0; uint64(i) < count && 4+3*i <= len(arguments); i++ {
+			transfer := arguments[1+3*i : 4+3*i]
+			nonce := decodeNonce(transfer[1])
+			if nonce == 0 {
+				// fungible tokens are transferred along with the non-fungible ones
+				continue
+			}
+
+			operations = append(operations, &Operation{Kind: KindTransfer, Collection: decodeString(transfer[0]), Nonce: nonce, Quantity: decodeQuantity(transfer[2]), From: sender, To: destination})
+		}
+
+		return operations
+	}
+
+	return nil
+}
+
+// operationsFromLogs decodes the events logged by the built-in functions during the execution of a transaction and of
+// its smart contract results, the operations made by the contracts included. An event reported twice, by the shards of
+// both the sender and the receiver of a transfer, is counted once.
+func operationsFromLogs(tx *processor.Transaction) []*Operation {
+	if tx.Logs() == nil || tx.HasOriginalTransactionHash() {
+		return nil
+	}
+
+	operations := make([]*Operation, 0)
+	seen := map[string]bool{}
+
+	for _, e := range tx.Logs().Events() {
+		topics, err := e.TopicsDecoded()
+		if err != nil {
+			continue
+		}
+
+		key := e.Identifier() + "/" + e.Address() + "/" + string(bytes.Join(topics, []byte{0}))
+		if seen[key] {
+			continue
+		}
+
+		seen[key] = true
+
+		switch e.Identifier() {
+		case createFunction, addQuantityFunction, burnFunction:
+			// token, nonce, quantity[, attributes]
+			if len(topics) < 3 {
+				continue
+			}
+
+			o := &Operation{Collection: string(topics[0]), Nonce: new(big.Int).SetBytes(topics[1]).Uint64(), Quantity: new(big.Int).SetBytes(topics[2]).String()}
+			switch e.Identifier() {
+			case createFunction:
+				o.Kind, o.To = KindCreate, e.Address()
+			case addQuantityFunction:
+				o.Kind, o.To = KindAddQuantity, e.Address()
+			default:
+				o.Kind, o.From = KindBurn, e.Address()
+			}
+
+			operations = append(operations, o)
+		case transferFunction, multiTransferFunction:
+			// (token, nonce, quantity)*, receiver
+			if len(topics) < 4 {
+				continue
+			}
+
+			receiver := encodeAddress(topics[len(topics)-1])
+			if receiver == "" {
+				continue
+			}
+
+			for i := 0; i+3 < len(topics); i += 3 {
+				nonce := new(big.Int).SetBytes(topics[i+1]).Uint64()
+				if nonce == 0 {
+					continue
+				}
+
+				operations = append(operations, &Operation{Kind: KindTransfer, Collection: string(topics[i]), Nonce: nonce, Quantity: new(big.Int).SetBytes(topics[i+2]).String(), From: e.Address(), To: receiver})
+			}
+		}
+	}
+
+	return operations
+}
+
+// Identifier returns the identifier of a token nonce: its collection followed by the hex encoded nonce.
+func Identifier(collection string, nonce uint64) string {
+	return collection + "-" + hex.EncodeToString(new(big.Int).SetUint64(nonce).Bytes())
+}
+
+func isSmartContractAddress(address string) bool {
+	_, key, err := bech32.Decode(address)
+	if err != nil || len(key) != 32 {
+		return false
+	}
+
+	return bytes.Equal(key[:smartContractPrefixLength], make([]byte, smartContractPrefixLength))
+}
+
+func decodeString(s string) string {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+func decodeNonce(s string) uint64 {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok || !v.IsUint64() {
+		return 0
+	}
+
+	return v.Uint64()
+}
+
+func decodeQuantity(s string) string {
+	v, ok := new(big.Int).SetString(s, 16)
+	if !ok {
+		return "0"
+	}
+
+	return v.String()
+}
+
+func decodeAddress(s string) string {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return ""
+	}
+
+	return encodeAddress(b)
+}
+
+func encodeAddress(key []byte) string {
+	if len(key) != 32 {
+		return ""
+	}
+
+	address, err := bech32.Encode(addressHRP, key)
+	if err != nil {
+		return ""
+	}
+
+	return address
+}
@@ -0,0 +1,40 @@
+package nftindex
+
+type Option func(*Index)
+
+type Options struct{}
+
+// FromLogs decodes the operations from the events logged by the transactions and their smart contract results, the
+// operations made by the contracts and the nonces of the created tokens included. The logs must be fetched by the
+// processor for the transactions involving the tokens, see processor.Options.EnrichLogs.
+func (oo *Options) FromLogs() Option {
+	return func(i *Index) {
+		i.fromLogs = true
+	}
+}
+
+// Collections restricts the index to the given collections, e.g. "COLL-a1b2c3".
+func (oo *Options) Collections(collections ...string) Option {
+	return func(i *Index) {
+		i.filter = map[string]bool{}
+		for _, c := range collections {
+			i.filter[c] = true
+		}
+	}
+}
+
+// JournalSize sets the number of blocks per shard whose operations can be rolled back, 100 by default.
+func (oo *Options) JournalSize(blocks int) Option {
+	return func(i *Index) {
+		if blocks > 0 {
+			i.journalSize = blocks
+		}
+	}
+}
+
+// OnOperation calls fn with each operation applied to the index, and with the operations reverted by a rollback.
+func (oo *Options) OnOperation(fn OnOperationFunc) Option {
+	return func(i *Index) {
+		i.onOperation = fn
+	}
+}
@@ -0,0 +1,87 @@
+package nftindex
+
+import "sort"
+
+// Token returns the token nonce of the identifier, e.g. "COLL-a1b2c3-0f".
+func (i *Index) Token(identifier string) (*Token, error) {
+	name, nonce, err := ParseIdentifier(identifier)
+	if err != nil {
+		return nil, err
+	}
+
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	c, found := i.collections[name]
+	if !found {
+		return nil, ErrTokenNotFound
+	}
+
+	t, found := c.tokens[nonce]
+	if !found {
+		return nil, ErrTokenNotFound
+	}
+
+	return t.export(name, nonce), nil
+}
+
+// Collection returns the token nonces of the collection, ordered by nonce.
+func (i *Index) Collection(name string) ([]*Token, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	c, found := i.collections[name]
+	if !found {
+		return nil, ErrCollectionNotFound
+	}
+
+	tokens := make([]*Token, 0, len(c.tokens))
+	for _, nonce := range c.sortedNonces() {
+		tokens = append(tokens, c.tokens[nonce].export(name, nonce))
+	}
+
+	return tokens, nil
+}
+
+// Holdings returns the token nonces owned by the address, ordered by identifier.
+func (i *Index) Holdings(address string) []*Holding {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	holdings := make([]*Holding, 0, len(i.owners[address]))
+	for identifier := range i.owners[address] {
+		name, nonce, err := ParseIdentifier(identifier)
+		if err != nil {
+			continue
+		}
+
+		t := i.collections[name].tokens[nonce]
+		holdings = append(holdings, &Holding{Identifier: identifier, Collection: name, Nonce: nonce, Quantity: t.balances[address].String()})
+	}
+
+	sort.Slice(holdings, func(a, b int) bool {
+		return holdings[a].Identifier < holdings[b].Identifier
+	})
+
+	return holdings
+}
+
+// Owners returns the holdings of each owner of the token nonces of the collection, keyed by address.
+func (i *Index) Owners(name string) (map[string][]Holding, error) {
+	i.mu.RLock()
+	defer i.mu.RUnlock()
+
+	c, found := i.collections[name]
+	if !found {
+		return nil, ErrCollectionNotFound
+	}
+
+	owners := map[string][]Holding{}
+	for _, nonce := range c.sortedNonces() {
+		for address, quantity := range c.tokens[nonce].balances {
+			owners[address] = append(owners[address], Holding{Identifier: Identifier(name, nonce), Collection: name, Nonce: nonce, Quantity: quantity.String()})
+		}
+	}
+
+	return owners, nil
+}
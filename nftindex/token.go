@@ -0,0 +1,180 @@
+package nftindex
+
+import (
+	"errors"
+	"math/big"
+	"sort"
+	"strings"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+var (
+	ErrTokenNotFound      = errors.New("token not found")
+	ErrCollectionNotFound = errors.New("collection not found")
+	ErrInvalidIdentifier  = errors.New("invalid token identifier")
+)
+
+// Token is a token nonce of a collection with its supply and the balances of its owners. A token whose supply was
+// entirely burnt is kept with a zero supply and no owners.
+type Token struct {
+	Identifier string `json:"identifier"`
+	Collection string `json:"collection"`
+	Nonce      uint64 `json:"nonce"`
+	// Creator and CreationHash are empty when the creation of the token was not seen by the index.
+	Creator      string    `json:"creator,omitempty"`
+	CreationHash string    `json:"creationHash,omitempty"`
+	Supply       string    `json:"supply"`
+	Owners       []Balance `json:"owners"`
+}
+
+// Burnt reports whether the whole supply of the token was burnt.
+func (t *Token) Burnt() bool {
+	return t.Creator != "" && t.Supply == "0"
+}
+
+type Balance struct {
+	Address  string `json:"address"`
+	Quantity string `json:"quantity"`
+}
+
+// Holding is the balance of an owner for a token nonce.
+type Holding struct {
+	Identifier string `json:"identifier"`
+	Collection string `json:"collection"`
+	Nonce      uint64 `json:"nonce"`
+	Quantity   string `json:"quantity"`
+}
+
+// Snapshot is a copy of the index, consistent with the blocks it was built from. It is the content of the file of an
+// index opened with Open.
+type Snapshot struct {
+	Tokens []*Token `json:"tokens"`
+	// LastNonces is the last nonce created in each collection.
+	LastNonces map[string]uint64 `json:"lastNonces"`
+	// Heads is the nonce of the last block of each shard applied to the index.
+	Heads processor.NonceByShard `json:"heads"`
+	// Journal is the operations of the last blocks of each shard, rolled back when the blocks are replaced.
+	Journal []*BlockOperations `json:"journal,omitempty"`
+}
+
+// Owners returns the holdings of each owner of the tokens of the snapshot in the collection, e.g. for an airdrop to
+// the owners at a given block.
+func (s *Snapshot) Owners(collection string) map[string][]Holding {
+	owners := map[string][]Holding{}
+	for _, t := range s.Tokens {
+		if t.Collection != collection {
+			continue
+		}
+
+		for _, b := range t.Owners {
+			owners[b.Address] = append(owners[b.Address], Holding{Identifier: t.Identifier, Collection: t.Collection, Nonce: t.Nonce, Quantity: b.Quantity})
+		}
+	}
+
+	return owners
+}
+
+// BlockOperations is the operations applied by a block, in order.
+type BlockOperations struct {
+	Shard      processor.Shard `json:"shard"`
+	Nonce      processor.Nonce `json:"nonce"`
+	Hash       string          `json:"hash"`
+	Operations []*Operation    `json:"operations,omitempty"`
+}
+
+// ParseIdentifier returns the collection and the nonce of a token identifier, e.g. "COLL-a1b2c3" and 15 for
+// "COLL-a1b2c3-0f".
+func ParseIdentifier(identifier string) (string, uint64, error) {
+	separator := strings.LastIndex(identifier, "-")
+	if separator <= 0 || strings.Count(identifier, "-") != 2 {
+		return "", 0, ErrInvalidIdentifier
+	}
+
+	nonce, ok := new(big.Int).SetString(identifier[separator+1:], 16)
+	if !ok || !nonce.IsUint64() || nonce.Sign() == 0 {
+		return "", 0, ErrInvalidIdentifier
+	}
+
+	return identifier[:separator], nonce.Uint64(), nil
+}
+
+// token is the state of a token nonce in the index.
+type token struct {
+	creator      string
+	creationHash string
+	supply       *big.Int
+	balances     map[string]*big.Int
+}
+
+func newToken() *token {
+	return &token{supply: new(big.Int), balances: map[string]*big.Int{}}
+}
+
+func (t *token) export(collection string, nonce uint64) *Token {
+	e := &Token{
+		Identifier:   Identifier(collection, nonce),
+		Collection:   collection,
+		Nonce:        nonce,
+		Creator:      t.creator,
+		CreationHash: t.creationHash,
+		Supply:       t.supply.String(),
+		Owners:       make([]Balance, 0, len(t.balances)),
+	}
+
+	for address, quantity := range t.balances {
+		e.Owners = append(e.Owners, Balance{Address: address, Quantity: quantity.String()})
+	}
+
+	sort.Slice(e.Owners, func(i, j int) bool {
+		return e.Owners[i].Address < e.Owners[j].Address
+	})
+
+	return e
+}
+
+// balance returns the quantity of the token held by the address.
+func (t *token) balance(address string) *big.Int {
+	if balance, found := t.balances[address]; found {
+		return balance
+	}
+
+	return new(big.Int)
+}
+
+func importToken(e *Token) *token {
+	t := newToken()
+	t.creator = e.Creator
+	t.creationHash = e.CreationHash
+
+	if supply, ok := new(big.Int).SetString(e.Supply, 10); ok {
+		t.supply = supply
+	}
+
+	for _, b := range e.Owners {
+		if quantity, ok := new(big.Int).SetString(b.Quantity, 10); ok && quantity.Sign() > 0 {
+			t.balances[b.Address] = quantity
+		}
+	}
+
+	return t
+}
+
+// collection is the state of a collection in the index.
+type collection struct {
+	lastNonce uint64
+	tokens    map[uint64]*token
+}
+
+func (c *collection) sortedNonces() []uint64 {
+	nonces := make([]uint64, 0, len(c.tokens))
+	for n := range c.tokens {
+		nonces = append(nonces, n)
+	}
+
+	sort.Slice(nonces, func(i, j int) bool {
+		return nonces[i] < nonces[j]
+	})
+
+	return nonces
+}
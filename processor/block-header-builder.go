@@ -0,0 +1,75 @@
+package processor
+
+import "time"
+
+func NewBlockHeaderBuilder() *BlockHeaderBuilder {
+	return &BlockHeaderBuilder{header: &BlockHeader{}}
+}
+
+type BlockHeaderBuilder struct {
+	header *BlockHeader
+}
+
+func (b *BlockHeaderBuilder) NewBlockHeader() *BlockHeaderBuilder {
+	b.header = &BlockHeader{}
+
+	return b
+}
+
+func (b *BlockHeaderBuilder) Hash(h string) *BlockHeaderBuilder {
+	b.header.hash = h
+
+	return b
+}
+
+func (b *BlockHeaderBuilder) PreviousHash(h string) *BlockHeaderBuilder {
+	b.header.previousHash = h
+
+	return b
+}
+
+func (b *BlockHeaderBuilder) Shard(s Shard) *BlockHeaderBuilder {
+	b.header.shard = s
+
+	return b
+}
+
+func (b *BlockHeaderBuilder) Nonce(n Nonce) *BlockHeaderBuilder {
+	b.header.nonce = n
+
+	return b
+}
+
+func (b *BlockHeaderBuilder) Round(r int) *BlockHeaderBuilder {
+	b.header.round = r
+
+	return b
+}
+
+func (b *BlockHeaderBuilder) Epoch(e int) *BlockHeaderBuilder {
+	b.header.epoch = e
+
+	return b
+}
+
+func (b *BlockHeaderBuilder) Timestamp(t time.Time) *BlockHeaderBuilder {
+	b.header.timestamp = t
+
+	return b
+}
+
+func (b *BlockHeaderBuilder) AccumulatedFees(f string) *BlockHeaderBuilder {
+	b.header.accumulatedFees = f
+
+	return b
+}
+
+func (b *BlockHeaderBuilder) DeveloperFees(f string) *BlockHeaderBuilder {
+	b.header.developerFees = f
+
+	return b
+}
+
+func (b *BlockHeaderBuilder) Build() *BlockHeader {
+	return b.header
+}
@@ -0,0 +1,55 @@
+package processor
+
+import "time"
+
+type BlockHeader struct {
+	hash            string
+	previousHash    string
+	shard           Shard
+	nonce           Nonce
+	round           int
+	epoch           int
+	timestamp       time.Time
+	accumulatedFees string
+	developerFees   string
+}
+
+func (h *BlockHeader) Hash() string {
+	return h.hash
+}
+
+func (h *BlockHeader) PreviousHash() string {
+	return h.previousHash
+}
+
+func (h *BlockHeader) Shard() Shard {
+	return h.shard
+}
+
+func (h *BlockHeader) Nonce() Nonce {
+	return h.nonce
+}
+
+func (h *BlockHeader) Round() int {
+	return h.round
+}
+
+func (h *BlockHeader) Epoch() int {
+	return h.epoch
+}
+
+func (h *BlockHeader) Timestamp() time.Time {
+	return h.timestamp
+}
+
+func (h *BlockHeader) AccumulatedFees() string {
+	return h.accumulatedFees
+}
+
+func (h *BlockHeader) DeveloperFees() string {
+	return h.developerFees
+}
+
+func (h *BlockHeader) IsAtOrAfter(t time.Time) bool {
+	return !h.timestamp.Before(t)
+}
@@ -0,0 +1,80 @@
+package processor
+
+import (
+	"encoding/json"
+	"time"
+)
+
+func NewBlock(header *BlockHeader, transactions Transactions) *Block {
+	return &Block{header: header, transactions: transactions}
+}
+
+// Block is a processed block of a shard along with its valid transactions, as delivered to sinks.
+type Block struct {
+	header       *BlockHeader
+	transactions Transactions
+}
+
+func (b *Block) Header() *BlockHeader {
+	return b.header
+}
+
+func (b *Block) Shard() Shard {
+	return b.header.shard
+}
+
+func (b *Block) Nonce() Nonce {
+	return b.header.nonce
+}
+
+func (b *Block) Hash() string {
+	return b.header.hash
+}
+
+func (b *Block) Transactions() Transactions {
+	return b.transactions
+}
+
+type blockJSON struct {
+	Shard        Shard          `json:"shard"`
+	Nonce        Nonce          `json:"nonce"`
+	Hash         string         `json:"hash"`
+	PreviousHash string         `json:"previousHash,omitempty"`
+	Round        int            `json:"round"`
+	Epoch        int            `json:"epoch"`
+	Timestamp    int64          `json:"timestamp"`
+	Transactions []*Transaction `json:"transactions"`
+}
+
+func (b *Block) MarshalJSON() ([]byte, error) {
+	return json.Marshal(blockJSON{
+		Shard:        b.header.shard,
+		Nonce:        b.header.nonce,
+		Hash:         b.header.hash,
+		PreviousHash: b.header.previousHash,
+		Round:        b.header.round,
+		Epoch:        b.header.epoch,
+		Timestamp:    b.header.timestamp.Unix(),
+		Transactions: b.transactions,
+	})
+}
+
+func (b *Block) UnmarshalJSON(data []byte) error {
+	v := blockJSON{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	b.header = NewBlockHeaderBuilder().
+		Shard(v.Shard).
+		Nonce(v.Nonce).
+		Hash(v.Hash).
+		PreviousHash(v.PreviousHash).
+		Round(v.Round).
+		Epoch(v.Epoch).
+		Timestamp(time.Unix(v.Timestamp, 0)).
+		Build()
+	b.transactions = v.Transactions
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package processor
+
+import (
+	"errors"
+	"log"
+	"time"
+)
+
+var (
+	ErrMembershipIsUndefined  = errors.New("membership of cluster worker is undefined")
+	ErrCoordinatorIsUndefined = errors.New("coordinator of cluster worker is undefined")
+)
+
+// NewClusterWorker returns a worker processing its share of the shards of a cluster.
+//
+// Every worker of the cluster must be configured with the same membership, coordinator (see Options.Coordinator,
+// whose processor ID is used as the cluster ID), fenced state storage and cross-shard store. Shards are assigned with
+// AssignShards and each shard is guarded by its own lease, so that a shard moving to another worker is only processed
+// once the previous owner persisted its progress and released it.
+func NewClusterWorker(membership Membership, opts ...Option) (*ClusterWorker, error) {
+	if membership == nil {
+		return nil, ErrMembershipIsUndefined
+	}
+
+	oo := Options{}
+	opts = append(opts, oo.LeasePerShard(), oo.PersistEveryBlock(), withoutPastBlocksReplay())
+
+	p, err := NewProcessor(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	if p.coordinator == nil {
+		return nil, ErrCoordinatorIsUndefined
+	}
+
+	w := &ClusterWorker{membership: membership, processor: p}
+
+	/* Processing may last longer than the TTL of the membership, which is renewed along with the leases meanwhile */
+	p.leases.heartbeat = func() {
+		if err := membership.Join(w.ClusterID(), w.ID(), p.leaseTTL); err != nil {
+			log.Printf("%s: could not renew membership: %s\n", w.ID(), err)
+		}
+	}
+
+	return w, nil
+}
+
+type ClusterWorker struct {
+	membership Membership
+	processor  *Processor
+	assigned   Shards
+}
+
+func (w *ClusterWorker) ID() string {
+	return w.processor.instanceID
+}
+
+func (w *ClusterWorker) ClusterID() string {
+	return w.processor.processorID
+}
+
+func (w *ClusterWorker) AssignedShards() Shards {
+	return w.assigned
+}
+
+// RunOnce renews the membership of the worker, computes its shards from the current members and processes them up
+// to their tip. It must be called more often than the lease TTL of the processor, which is also the TTL of the
+// membership. While the shards are processed, the membership is renewed along with the leases.
+func (w *ClusterWorker) RunOnce() error {
+	p := w.processor
+
+	if err := w.membership.Join(w.ClusterID(), w.ID(), p.leaseTTL); err != nil {
+		return err
+	}
+
+	members, err := w.membership.Members(w.ClusterID())
+	if err != nil {
+		return err
+	}
+
+	shards, err := p.dataSource.GetShards()
+	if err != nil {
+		return err
+	}
+
+	clusterShards := append(Shards{}, p.selectShards(shards)...)
+	clusterShards.Sort()
+
+	assigned := AssignShards(clusterShards, members)[w.ID()]
+	if !assigned.Equals(w.assigned) {
+		log.Printf("%s now handles %s\n", w.ID(), assigned)
+	}
+
+	w.assigned = assigned
+
+	if err := p.leases.releaseExcept(assigned); err != nil {
+		return err
+	}
+
+	if len(assigned) == 0 {
+		return nil
+	}
+
+	p.assignedShards = assigned
+	p.clusterShards = clusterShards
+
+	err = p.Start()
+	if errors.Is(err, ErrLeaseNotAcquired) {
+		// shards moving to this worker are still held by their previous owner
+		return nil
+	}
+
+	return err
+}
+
+// Run calls RunOnce at each interval until stop is closed, then leaves the cluster.
+func (w *ClusterWorker) Run(interval time.Duration, stop <-chan struct{}) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := w.RunOnce(); err != nil {
+			log.Printf("%s: %s\n", w.ID(), err)
+		}
+
+		select {
+		case <-stop:
+			return w.Leave()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Leave releases the leases of the worker and removes it from the members, so that its shards are reassigned
+// immediately.
+func (w *ClusterWorker) Leave() error {
+	w.assigned = nil
+
+	if err := w.processor.ReleaseLeases(); err != nil {
+		return err
+	}
+
+	return w.membership.Leave(w.ClusterID(), w.ID())
+}
+
+func withoutPastBlocksReplay() Option {
+	return func(p *Processor) {
+		p.replayPastBlocks = false
+	}
+}
@@ -0,0 +1,393 @@
+package processor
+
+import (
+	"encoding/base64"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testDataSource serves blocks up to a tip moved by the tests, each block holding an intra-shard transaction along
+// with the transactions added for it. The epoch changes every five nonces.
+type testDataSource struct {
+	mu           sync.Mutex
+	shards       Shards
+	tip          Nonce
+	start        time.Time
+	transactions map[string]Transactions
+}
+
+func newTestDataSource(shards Shards) *testDataSource {
+	return &testDataSource{shards: shards, start: time.Unix(1600000000, 0), transactions: map[string]Transactions{}}
+}
+
+func (d *testDataSource) setTip(n Nonce) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.tip = n
+}
+
+func (d *testDataSource) add(shard Shard, nonce Nonce, txs ...*Transaction) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	key := fmt.Sprintf("%d/%d", shard, nonce)
+	d.transactions[key] = append(d.transactions[key], txs...)
+}
+
+func (d *testDataSource) currentTip() Nonce {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.tip
+}
+
+func (d *testDataSource) GetShards() ([]Shard, error) {
+	return d.shards, nil
+}
+
+func (d *testDataSource) GetNetworkConfig() (*NetworkConfig, error) {
+	return NewNetworkConfigBuilder().NumShards(len(d.shards) - 1).Build(), nil
+}
+
+func (d *testDataSource) GetNetworkStatus(shard Shard) (*NetworkStatus, error) {
+	tip := d.currentTip()
+
+	return NewNetworkStatusBuilder().Shard(shard).Nonce(tip).HighestFinalNonce(tip).Build(), nil
+}
+
+func (d *testDataSource) GetCurrentNonceForShard(Shard) (Nonce, error) {
+	return d.currentTip(), nil
+}
+
+func (d *testDataSource) GetCurrentNoncesForShards(shards []Shard) (NonceByShard, error) {
+	nonces := NonceByShard{}
+	for _, s := range shards {
+		nonces[s] = d.currentTip()
+	}
+
+	return nonces, nil
+}
+
+func (d *testDataSource) GetShardTransactions(shard Shard, nonce Nonce) (*BlockHeader, []*Transaction, error) {
+	header, err := d.GetBlockHeader(shard, nonce)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	/* Each call returns its own copies, as the gateway does */
+	transactions := Transactions{NewTransactionBuilder().Hash(fmt.Sprintf("tx-%d-%d", shard, nonce)).SourceShard(shard).DestinationShard(shard).Status("success").Build()}
+	for _, tx := range d.transactions[fmt.Sprintf("%d/%d", shard, nonce)] {
+		c := *tx
+		transactions = append(transactions, &c)
+	}
+
+	return header, transactions, nil
+}
+
+func (d *testDataSource) GetBlockHeader(shard Shard, nonce Nonce) (*BlockHeader, error) {
+	return NewBlockHeaderBuilder().
+		Shard(shard).
+		Nonce(nonce).
+		Hash(fmt.Sprintf("block-%d-%d", shard, nonce)).
+		Epoch(int(nonce) / 5).
+		Timestamp(d.start.Add(time.Duration(nonce) * 6 * time.Second)).
+		Build(), nil
+}
+
+// TestClusterWorkersShareCrossShardTransactions runs several workers of a cluster in the same process. The workers join
+// and leave while the chain grows, and follow a transaction whose smart contract results cross three shards.
+func TestClusterWorkersShareCrossShardTransactions(t *testing.T) {
+	shards := Shards{0, 1, 2, ShardMetachain}
+	ds := newTestDataSource(shards)
+
+	scr := func(hash string, from, to Shard) *Transaction {
+		return NewTransactionBuilder().Hash(hash).OriginalTransactionHash("cross").SourceShard(from).DestinationShard(to).Value("1").Build()
+	}
+
+	/* A call from shard 0 to a contract of shard 1, which calls a contract of shard 2 */
+	original := NewTransactionBuilder().Hash("cross").Sender("erd1alice").Receiver("erd1contract").Value("1000").Data(base64.StdEncoding.EncodeToString([]byte("buy"))).SourceShard(0).DestinationShard(1).Status("success").Build()
+	ds.add(0, 5, original, scr("cross-1", 0, 1))
+	ds.add(1, 8, scr("cross-1", 0, 1), scr("cross-2", 1, 2))
+	ds.add(2, 11, scr("cross-2", 1, 2))
+
+	storage := NewInMemoryStateStorage()
+	if err := storage.PersistLastState(shards, NewState(NewCrossShardDictionary(), NonceByShard{0: 0, 1: 0, 2: 0, ShardMetachain: 0}, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu        sync.Mutex
+		emitted   = map[string]string{}
+		delivered = make([]string, 0)
+		completed = make([]LifecycleEvent, 0)
+	)
+
+	membership, coordinator, store := NewInMemoryMembership(), NewInMemoryCoordinator(), NewInMemoryCrossShardStore()
+
+	newWorker := func(id string) *ClusterWorker {
+		oo := Options{}
+		w, err := NewClusterWorker(membership,
+			oo.DataSource(ds),
+			oo.StateStorage(storage),
+			oo.CrossShardStore(store),
+			oo.Coordinator(coordinator, "cluster", id),
+			oo.LeaseTTL(time.Minute),
+			oo.WaitForFinalizedCrossShardSmartContractResults(true),
+			oo.OnTransactionsReceived(func(shard Shard, nonce Nonce, txs []*Transaction, _ string) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				for _, tx := range txs {
+					if tx.Hash() == "cross" && tx.ResultTree() != nil && len(tx.ResultTree().Results()) == 2 && tx.Sender() == "erd1alice" {
+						delivered = append(delivered, fmt.Sprintf("%d/%d", shard, nonce))
+					}
+				}
+
+				key := fmt.Sprintf("%d/%d", shard, nonce)
+				if previous, found := emitted[key]; found {
+					t.Errorf("block %s emitted by %s and %s", key, previous, id)
+				}
+
+				emitted[key] = id
+			}),
+			oo.OnLifecycleEvent(func(e LifecycleEvent) {
+				if e.Stage != StageCompleted || e.Hash != "cross" {
+					return
+				}
+
+				mu.Lock()
+				defer mu.Unlock()
+
+				completed = append(completed, e)
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		return w
+	}
+
+	workers := []*ClusterWorker{newWorker("w0"), newWorker("w1"), newWorker("w2")}
+	active := map[string]bool{"w0": true, "w1": true, "w2": true}
+
+	const lastNonce = 16
+	for round := 1; round <= lastNonce+2; round++ {
+		if round <= lastNonce {
+			ds.setTip(Nonce(round))
+		}
+
+		if round == 13 {
+			if err := workers[1].Leave(); err != nil {
+				t.Fatal(err)
+			}
+
+			active["w1"] = false
+		}
+
+		var wg sync.WaitGroup
+		for _, w := range workers {
+			if !active[w.ID()] {
+				continue
+			}
+
+			wg.Add(1)
+
+			go func(w *ClusterWorker) {
+				defer wg.Done()
+
+				if err := w.RunOnce(); err != nil {
+					t.Error(err)
+				}
+			}(w)
+		}
+
+		wg.Wait()
+	}
+
+	for _, shard := range shards {
+		for nonce := 1; nonce <= lastNonce; nonce++ {
+			if _, found := emitted[fmt.Sprintf("%d/%d", shard, nonce)]; !found {
+				t.Errorf("block %d of %s was not emitted", nonce, shard.Name())
+			}
+		}
+	}
+
+	if len(completed) != 1 {
+		t.Fatalf("expected the cross-shard transaction to complete once, got %d completion(s)", len(completed))
+	}
+
+	if e := completed[0]; e.Shard != 2 || e.Nonce != 11 || e.Transaction.Sender() != "erd1alice" || e.Transaction.Value() != "1000" {
+		t.Fatalf("unexpected completion in block %d of %s for %+v", e.Nonce, e.Shard.Name(), e.Transaction)
+	}
+
+	if len(delivered) != 1 || delivered[0] != "2/11" {
+		t.Fatalf("expected the cross-shard transaction to be delivered with its results by block 11 of shard 2, got %v", delivered)
+	}
+
+	keys, err := store.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 0 {
+		t.Fatalf("cross-shard transactions still tracked: %v", keys)
+	}
+}
+
+// TestClusterWorkersReportEpochsAcrossShards checks that the epochs are reported once by the cluster, with the blocks of
+// every shard counted, although each worker only processes some of the shards.
+func TestClusterWorkersReportEpochsAcrossShards(t *testing.T) {
+	shards := Shards{0, 1, 2, ShardMetachain}
+	ds := newTestDataSource(shards)
+
+	storage := NewInMemoryStateStorage()
+	if err := storage.PersistLastState(shards, NewState(NewCrossShardDictionary(), NonceByShard{0: 0, 1: 0, 2: 0, ShardMetachain: 0}, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		mu          sync.Mutex
+		transitions = make([]EpochTransition, 0)
+	)
+
+	membership, coordinator := NewInMemoryMembership(), NewInMemoryCoordinator()
+
+	workers := make([]*ClusterWorker, 0)
+	for _, id := range []string{"w0", "w1", "w2"} {
+		oo := Options{}
+		w, err := NewClusterWorker(membership,
+			oo.DataSource(ds),
+			oo.StateStorage(storage),
+			oo.CrossShardStore(NewInMemoryCrossShardStore()),
+			oo.Coordinator(coordinator, "cluster", id),
+			oo.LeaseTTL(time.Minute),
+			oo.OnEpochTransition(func(transition EpochTransition) {
+				mu.Lock()
+				defer mu.Unlock()
+
+				transitions = append(transitions, transition)
+			}),
+		)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		workers = append(workers, w)
+	}
+
+	for round := 1; round <= 18; round++ {
+		ds.setTip(Nonce(round))
+
+		var wg sync.WaitGroup
+		for _, w := range workers {
+			wg.Add(1)
+
+			go func(w *ClusterWorker) {
+				defer wg.Done()
+
+				if err := w.RunOnce(); err != nil {
+					t.Error(err)
+				}
+			}(w)
+		}
+
+		wg.Wait()
+	}
+
+	/* Nonces 1 to 4 are in epoch 0, then each epoch holds 5 nonces up to epoch 3, reached by every shard */
+	expected := []struct {
+		epoch  int
+		blocks int
+	}{
+		{epoch: 0, blocks: 4 * len(shards)},
+		{epoch: 1, blocks: 5 * len(shards)},
+		{epoch: 2, blocks: 5 * len(shards)},
+	}
+
+	if len(transitions) != len(expected) {
+		t.Fatalf("expected %d epoch transitions, got %+v", len(expected), transitions)
+	}
+
+	for i, e := range expected {
+		if transitions[i].Epoch != e.epoch || transitions[i].TotalBlocks() != e.blocks || len(transitions[i].Blocks) != len(shards) {
+			t.Errorf("expected epoch %d with %d blocks of every shard, got %+v", e.epoch, e.blocks, transitions[i])
+		}
+	}
+}
+
+// slowDataSource takes delay to serve the transactions of each block.
+type slowDataSource struct {
+	*testDataSource
+	delay time.Duration
+}
+
+func (d *slowDataSource) GetShardTransactions(shard Shard, nonce Nonce) (*BlockHeader, []*Transaction, error) {
+	time.Sleep(d.delay)
+
+	return d.testDataSource.GetShardTransactions(shard, nonce)
+}
+
+// TestClusterWorkerStaysMemberWhileProcessing checks that a worker catching up for longer than the TTL of its
+// membership is not dropped from the members, which would hand its shards over to the other workers.
+func TestClusterWorkerStaysMemberWhileProcessing(t *testing.T) {
+	const ttl = 90 * time.Millisecond
+
+	ds := &slowDataSource{testDataSource: newTestDataSource(Shards{0}), delay: 30 * time.Millisecond}
+	ds.setTip(10)
+
+	storage := NewInMemoryStateStorage()
+	if err := storage.PersistLastState(Shards{0}, NewState(NewCrossShardDictionary(), NonceByShard{0: 0}, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	membership := NewInMemoryMembership()
+
+	oo := Options{}
+	w, err := NewClusterWorker(membership,
+		oo.DataSource(ds),
+		oo.StateStorage(storage),
+		oo.Coordinator(NewInMemoryCoordinator(), "cluster", "w0"),
+		oo.LeaseTTL(ttl),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan error)
+	start := time.Now()
+
+	go func() {
+		done <- w.RunOnce()
+	}()
+
+	for {
+		select {
+		case err := <-done:
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if elapsed := time.Since(start); elapsed < 2*ttl {
+				t.Fatalf("processing took %s, not longer than the TTL", elapsed)
+			}
+
+			return
+		case <-time.After(10 * time.Millisecond):
+			members, err := membership.Members("cluster")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(members) != 1 {
+				t.Fatalf("worker dropped from the members after %s", time.Since(start))
+			}
+		}
+	}
+}
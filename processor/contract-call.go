@@ -0,0 +1,146 @@
+package processor
+
+import (
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/thefabric-io/elrond-transaction-processor/bech32"
+)
+
+const (
+	esdtTransferFunction         = "ESDTTransfer"
+	esdtNFTTransferFunction      = "ESDTNFTTransfer"
+	multiESDTNFTTransferFunction = "MultiESDTNFTTransfer"
+)
+
+// contractCall returns the contract called by the transaction, the endpoint and its hex encoded arguments. The calls
+// made along with token transfers are unwrapped: the endpoint and its arguments follow the transfer arguments, and
+// the contract of a NFT transfer is its destination rather than the receiver of the transaction, which is the sender.
+func contractCall(tx *Transaction) (string, string, []string) {
+	function, arguments := tx.Function(), tx.Arguments()
+
+	switch function {
+	case esdtTransferFunction:
+		// ESDTTransfer@token@amount@endpoint@arguments...
+		if len(arguments) > 2 {
+			return tx.receiver, decodeHexString(arguments[2]), arguments[3:]
+		}
+	case esdtNFTTransferFunction:
+		// ESDTNFTTransfer@token@nonce@amount@destination@endpoint@arguments...
+		if len(arguments) > 4 {
+			if destination, err := decodeHexAddress(arguments[3]); err == nil {
+				return destination, decodeHexString(arguments[4]), arguments[5:]
+			}
+		}
+	case multiESDTNFTTransferFunction:
+		// MultiESDTNFTTransfer@destination@count@(token@nonce@amount)*count@endpoint@arguments...
+		if len(arguments) > 1 {
+			destination, err := decodeHexAddress(arguments[0])
+			count, ok := new(big.Int).SetString(arguments[1], 16)
+			if err == nil && ok && count.IsInt64() {
+				next := 2 + 3*int(count.Int64())
+				if next >= 2 && len(arguments) > next {
+					return destination, decodeHexString(arguments[next]), arguments[next+1:]
+				}
+			}
+		}
+	}
+
+	return tx.receiver, function, arguments
+}
+
+func decodeHexString(s string) string {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return ""
+	}
+
+	return string(b)
+}
+
+func decodeHexAddress(s string) (string, error) {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return "", err
+	}
+
+	return bech32.Encode(addressHRP, b)
+}
+
+// decodeWithABIs decodes the calls to the contracts registered with ContractABI and the events they emitted.
+func (p *Processor) decodeWithABIs(transactions Transactions) {
+	if len(p.abis) == 0 {
+		return
+	}
+
+	for _, tx := range transactions {
+		p.decodeCall(tx)
+
+		if tx.innerTransaction != nil {
+			p.decodeCall(tx.innerTransaction)
+		}
+
+		if tx.logs != nil {
+			p.decodeEvents(tx)
+		}
+	}
+}
+
+func (p *Processor) decodeCall(tx *Transaction) {
+	contract, endpoint, arguments := contractCall(tx)
+
+	a, found := p.abis[contract]
+	if !found || endpoint == "" {
+		return
+	}
+
+	raw := make([][]byte, len(arguments))
+	for i, argument := range arguments {
+		b, err := hex.DecodeString(argument)
+		if err != nil {
+			p.logIfVerbose(fmt.Sprintf("\t| Could not decode argument %d of transaction %s: %s\n", i, tx.hash, err))
+
+			return
+		}
+
+		raw[i] = b
+	}
+
+	call, err := a.DecodeCall(endpoint, raw)
+	if err != nil {
+		p.logIfVerbose(fmt.Sprintf("\t| Could not decode transaction %s with the ABI of %s: %s\n", tx.hash, contract, err))
+
+		return
+	}
+
+	tx.decodedCall = call
+}
+
+func (p *Processor) decodeEvents(tx *Transaction) {
+	for _, e := range tx.logs.events {
+		a, found := p.abis[e.address]
+		if !found {
+			continue
+		}
+
+		topics, err := e.TopicsDecoded()
+		if err != nil {
+			continue
+		}
+
+		data, err := e.DataDecoded()
+		if err != nil {
+			continue
+		}
+
+		decoded, err := a.DecodeEvent(topics, data)
+		if err != nil {
+			p.logIfVerbose(fmt.Sprintf("\t| Could not decode event %s of transaction %s with the ABI of %s: %s\n", e.identifier, tx.hash, e.address, err))
+
+			continue
+		}
+
+		e.decoded = decoded
+	}
+}
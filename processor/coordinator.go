@@ -0,0 +1,87 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	ErrLeaseNotAcquired  = errors.New("lease is held by another instance")
+	ErrLeaseLost         = errors.New("lease has been lost")
+	ErrStaleFencingToken = errors.New("state write rejected, fencing token is stale")
+)
+
+const (
+	LeasePerProcessor LeaseMode = iota
+	LeasePerShard
+)
+
+const (
+	defaultLeaseTTL     = 30 * time.Second
+	leaseRenewalsPerTTL = 3
+)
+
+// LeaseMode defines whether a single lease covers every shard of a processor or each shard has its own lease, which
+// lets several instances sharing a processor ID split the shards between them.
+type LeaseMode int
+
+// FencingToken increases every time a lease changes hands. State storages implementing FencedStateStorage reject
+// writes carrying a token lower than the last one they accepted.
+type FencingToken int64
+
+type Lease struct {
+	key       string
+	owner     string
+	token     FencingToken
+	expiresAt time.Time
+}
+
+func NewLease(key, owner string, token FencingToken, expiresAt time.Time) *Lease {
+	return &Lease{key: key, owner: owner, token: token, expiresAt: expiresAt}
+}
+
+func (l *Lease) Key() string {
+	return l.key
+}
+
+func (l *Lease) Owner() string {
+	return l.owner
+}
+
+func (l *Lease) Token() FencingToken {
+	return l.token
+}
+
+func (l *Lease) ExpiresAt() time.Time {
+	return l.expiresAt
+}
+
+func (l *Lease) IsExpired(now time.Time) bool {
+	return !now.Before(l.expiresAt)
+}
+
+// Coordinator grants time-limited leases so that two instances never process the same shard at the same time.
+type Coordinator interface {
+	// Acquire grants the lease to owner if it is free, expired or already held by owner.
+	// ErrLeaseNotAcquired is returned when another owner holds it.
+	Acquire(key, owner string, ttl time.Duration) (*Lease, error)
+	// Renew extends the lease. ErrLeaseLost is returned when the lease expired or changed hands in the meantime.
+	Renew(lease *Lease, ttl time.Duration) (*Lease, error)
+	Release(lease *Lease) error
+}
+
+// FencedStateStorage is implemented by state storages able to reject writes coming from an instance whose lease has
+// been taken over by another one.
+type FencedStateStorage interface {
+	StateStorage
+	PersistLastStateFenced(shards Shards, state *State, tokens map[Shard]FencingToken) error
+}
+
+func processorLeaseKey(processorID string) string {
+	return fmt.Sprintf("%s:processor", processorID)
+}
+
+func shardLeaseKey(processorID string, shard Shard) string {
+	return fmt.Sprintf("%s:shard:%d", processorID, shard)
+}
@@ -1,14 +1,5 @@
 package processor
 
-import (
-	"log"
-	"time"
-)
-
-const (
-	minPruningElapsedTime = 600 * time.Second
-)
-
 func NewCrossShardDictionary() CrossShardDictionary {
 	return CrossShardDictionary{}
 }
@@ -40,13 +31,3 @@ func (m CrossShardDictionary) FindTransaction(h string) *CrossShardTransaction {
 func (m CrossShardDictionary) Delete(h string) {
 	delete(m, h)
 }
-
-func (m CrossShardDictionary) PruneTransactions() {
-	for h, crossShardTransaction := range m {
-		elapsed := time.Now().Sub(crossShardTransaction.created)
-		if elapsed > minPruningElapsedTime {
-			log.Printf("pruning transaction with hash %s since its elapsed time is %.2f seconds", h, elapsed.Seconds())
-			delete(m, h)
-		}
-	}
-}
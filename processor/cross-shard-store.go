@@ -0,0 +1,172 @@
+package processor
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+var ErrCrossShardTransactionNotTracked = errors.New("cross-shard transaction is not tracked")
+
+// CrossShardStore keeps track of the cross-shard transactions awaiting smart contract results. Processors working on
+// different shards of the same chain must share a single store, since the counter of a transaction is incremented in
+// its source shard and decremented in the destination shards of its results.
+type CrossShardStore interface {
+	FindTransaction(h string) (*CrossShardTransaction, error)
+	// Set starts tracking the transaction along with its counter in a single step.
+	Set(h string, t *CrossShardTransaction) error
+	// Delete stops tracking the transaction and returns it, or nil when it is not tracked. Among concurrent callers,
+	// only one gets the transaction.
+	Delete(h string) (*CrossShardTransaction, error)
+	Keys() ([]string, error)
+	// Expired returns the hashes of the transactions tracked in one of the shards since a block older than
+	// createdBefore, unless it is zero, or whose nonce is below the one of noncesBefore for its shard.
+	Expired(shards Shards, createdBefore time.Time, noncesBefore NonceByShard) ([]string, error)
+	// AddToCounter atomically adds delta to the counter of a tracked transaction and returns the new value, or
+	// ErrCrossShardTransactionNotTracked. When the counter reaches zero, the transaction stops being tracked in the
+	// same step and is returned completed, with its results, to the single caller that completed it.
+	AddToCounter(h string, delta int) (int, *CrossShardTransaction, error)
+	// AddResult collects a smart contract result of the transaction, see CrossShardTransaction.AddResult. Results of
+	// transactions that are not tracked are ignored.
+	AddResult(h string, result *Transaction) error
+}
+
+// NewCrossShardDictionaryStore exposes a CrossShardDictionary as a CrossShardStore, for processors running alone.
+func NewCrossShardDictionaryStore(d CrossShardDictionary) CrossShardStore {
+	return dictionaryStore{dictionary: d}
+}
+
+type dictionaryStore struct {
+	dictionary CrossShardDictionary
+}
+
+func (s dictionaryStore) FindTransaction(h string) (*CrossShardTransaction, error) {
+	return s.dictionary.FindTransaction(h), nil
+}
+
+func (s dictionaryStore) Set(h string, t *CrossShardTransaction) error {
+	s.dictionary.Set(h, t)
+
+	return nil
+}
+
+func (s dictionaryStore) Delete(h string) (*CrossShardTransaction, error) {
+	t := s.dictionary.FindTransaction(h)
+	s.dictionary.Delete(h)
+
+	return t, nil
+}
+
+func (s dictionaryStore) Keys() ([]string, error) {
+	return s.dictionary.Keys(), nil
+}
+
+// Expired scans the dictionary, which only holds the transactions pending in a single process.
+func (s dictionaryStore) Expired(shards Shards, createdBefore time.Time, noncesBefore NonceByShard) ([]string, error) {
+	hashes := make([]string, 0)
+	for h, t := range s.dictionary {
+		if !shards.Contains(t.shard) {
+			continue
+		}
+
+		if !createdBefore.IsZero() && t.created.Before(createdBefore) {
+			hashes = append(hashes, h)
+
+			continue
+		}
+
+		if nonce, found := noncesBefore.Nonce(t.shard); found && t.nonce < nonce {
+			hashes = append(hashes, h)
+		}
+	}
+
+	return hashes, nil
+}
+
+func (s dictionaryStore) AddToCounter(h string, delta int) (int, *CrossShardTransaction, error) {
+	t := s.dictionary.FindTransaction(h)
+	if t == nil {
+		return 0, nil, ErrCrossShardTransactionNotTracked
+	}
+
+	t.counter += delta
+	if t.counter > 0 {
+		return t.counter, nil, nil
+	}
+
+	s.dictionary.Delete(h)
+
+	return t.counter, t, nil
+}
+
+func (s dictionaryStore) AddResult(h string, result *Transaction) error {
+	if t := s.dictionary.FindTransaction(h); t != nil {
+		t.AddResult(result)
+	}
+
+	return nil
+}
+
+// NewInMemoryCrossShardStore returns a CrossShardStore safe for concurrent use by the processors of a single process.
+func NewInMemoryCrossShardStore() CrossShardStore {
+	return &lockedStore{store: NewCrossShardDictionaryStore(NewCrossShardDictionary())}
+}
+
+type lockedStore struct {
+	mu    sync.Mutex
+	store CrossShardStore
+}
+
+func (s *lockedStore) FindTransaction(h string) (*CrossShardTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	t, err := s.store.FindTransaction(h)
+	if t == nil || err != nil {
+		return t, err
+	}
+
+	return t.copy(), nil
+}
+
+func (s *lockedStore) Set(h string, t *CrossShardTransaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.Set(h, t.copy())
+}
+
+func (s *lockedStore) Delete(h string) (*CrossShardTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.Delete(h)
+}
+
+func (s *lockedStore) Keys() ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.Keys()
+}
+
+func (s *lockedStore) Expired(shards Shards, createdBefore time.Time, noncesBefore NonceByShard) ([]string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.Expired(shards, createdBefore, noncesBefore)
+}
+
+func (s *lockedStore) AddToCounter(h string, delta int) (int, *CrossShardTransaction, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.AddToCounter(h, delta)
+}
+
+func (s *lockedStore) AddResult(h string, result *Transaction) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.store.AddResult(h, result)
+}
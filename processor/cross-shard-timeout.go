@@ -0,0 +1,57 @@
+package processor
+
+import (
+	"fmt"
+	"log"
+	"time"
+)
+
+const (
+	defaultCrossShardTimeout = 600 * time.Second
+)
+
+type OnCrossShardTransactionTimedOutFunc func(t *CrossShardTransaction)
+
+// pruneCrossShardTransactions gives up on the cross-shard transactions whose smart contract results did not all
+// finalize in time, that is which exceeded the block time elapsed since the block they started to be tracked in, or
+// the nonce distance from that block in its shard, whichever timeouts are set. Timeouts are measured against the
+// processed blocks rather than the wall clock, so that restarting or catching up does not expire transactions whose
+// results are only a few blocks away. Only the transactions tracked in the shards of the processor are pruned: the
+// workers of a cluster sharing a cross-shard store process their shards at their own pace, the blocks of a worker
+// ahead would expire the transactions of a worker behind.
+func (p *Processor) pruneCrossShardTransactions() error {
+	if p.lastBlockTime.IsZero() {
+		return nil
+	}
+
+	var createdBefore time.Time
+	if p.crossShardTimeout > 0 {
+		createdBefore = p.lastBlockTime.Add(-p.crossShardTimeout)
+	}
+
+	noncesBefore := NonceByShard{}
+	if p.crossShardNonceTimeout > 0 {
+		for _, shard := range p.shards {
+			if nonce, found := p.internalState.LastProcessedNonceInShard(shard); found {
+				noncesBefore.PutNonce(shard, nonce.Subtract(p.crossShardNonceTimeout))
+			}
+		}
+	}
+
+	pruned, err := p.internalState.PruneCrossShardDictionary(p.shards, createdBefore, noncesBefore)
+	if err != nil {
+		return fmt.Errorf("could not prune cross-shard transactions: %w", err)
+	}
+
+	for _, t := range pruned {
+		log.Printf("Cross-shard transaction %s from block %d of %s timed out with %d SCR(s) pending\n", t.transaction.hash, t.nonce, t.shard.Name(), t.counter)
+
+		p.emitLifecycleEvent(StageTimedOut, t.Transaction(), nil, "")
+
+		if p.onCrossShardTransactionTimedOutFunc != nil {
+			p.onCrossShardTransactionTimedOutFunc(t)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,77 @@
+package processor
+
+import (
+	"fmt"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestPruneCrossShardTransactions(t *testing.T) {
+	oo := Options{}
+	start := time.Unix(1600000000, 0)
+
+	/* The processor owns shard 0, shard 1 is processed by another worker sharing the store */
+	tracked := []struct {
+		hash    string
+		created time.Duration
+		shard   Shard
+		nonce   Nonce
+	}{
+		{hash: "old-0", created: 0, shard: 0, nonce: 10},
+		{hash: "recent-0", created: 9 * time.Minute, shard: 0, nonce: 100},
+		{hash: "stale-0", created: 9*time.Minute + 30*time.Second, shard: 0, nonce: 20},
+		{hash: "old-1", created: 0, shard: 1, nonce: 10},
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected []string
+	}{
+		{name: "no timeout", opts: []Option{oo.CrossShardTimeout(0)}, expected: []string{}},
+		{name: "block time", opts: []Option{oo.CrossShardTimeout(5 * time.Minute)}, expected: []string{"old-0"}},
+		{name: "nonce distance", opts: []Option{oo.CrossShardTimeout(0), oo.CrossShardNonceTimeout(50)}, expected: []string{"old-0", "stale-0"}},
+		{name: "block time or nonce distance", opts: []Option{oo.CrossShardTimeout(30 * time.Second), oo.CrossShardNonceTimeout(50)}, expected: []string{"old-0", "recent-0", "stale-0"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			timedOut := make([]string, 0)
+			opts := append([]Option{
+				oo.DataSource(newTestDataSource(Shards{0, 1})),
+				oo.StateStorage(NewInMemoryStateStorage()),
+				oo.OnCrossShardTransactionTimedOut(func(t *CrossShardTransaction) {
+					timedOut = append(timedOut, t.Transaction().Hash())
+				}),
+			}, tt.opts...)
+
+			p, err := NewProcessor(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			store := NewInMemoryCrossShardStore()
+			for _, tr := range tracked {
+				tx := NewTransactionBuilder().Hash(tr.hash).Build()
+				if err := store.Set(tr.hash, RestoreCrossShardTransaction(tx, 1, start.Add(tr.created), tr.shard, tr.nonce)); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			/* Shard 1 lags behind shard 0 by more than the timeouts */
+			p.shards = Shards{0}
+			p.internalState = NewStateWithCrossShardStore(store, NonceByShard{0: 100, 1: 10}, nil)
+			p.lastBlockTime = start.Add(10 * time.Minute)
+
+			if err := p.pruneCrossShardTransactions(); err != nil {
+				t.Fatal(err)
+			}
+
+			sort.Strings(timedOut)
+			if fmt.Sprint(timedOut) != fmt.Sprint(tt.expected) {
+				t.Fatalf("expected %v to time out, got %v", tt.expected, timedOut)
+			}
+		})
+	}
+}
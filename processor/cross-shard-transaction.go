@@ -2,24 +2,70 @@ package processor
 
 import "time"
 
-func NewCrossShardTransaction(t *Transaction) *CrossShardTransaction {
-	return &CrossShardTransaction{transaction: *t}
+// NewCrossShardTransaction starts tracking a transaction seen in the given block, whose time and nonce are used to
+// decide when the transaction timed out.
+func NewCrossShardTransaction(t *Transaction, header *BlockHeader) *CrossShardTransaction {
+	return &CrossShardTransaction{transaction: *t, created: header.timestamp, shard: header.shard, nonce: header.nonce}
+}
+
+// RestoreCrossShardTransaction rebuilds a cross-shard transaction read from a CrossShardStore.
+func RestoreCrossShardTransaction(t *Transaction, counter int, created time.Time, shard Shard, nonce Nonce) *CrossShardTransaction {
+	return &CrossShardTransaction{transaction: *t, counter: counter, created: created, shard: shard, nonce: nonce}
 }
 
 type CrossShardTransaction struct {
 	transaction Transaction
 	counter     int
 	created     time.Time
+	shard       Shard
+	nonce       Nonce
+	results     Transactions
+}
+
+func (t *CrossShardTransaction) Transaction() *Transaction {
+	return &t.transaction
+}
+
+func (t *CrossShardTransaction) Counter() int {
+	return t.counter
+}
+
+// Created returns the time of the block the transaction started to be tracked in.
+func (t *CrossShardTransaction) Created() time.Time {
+	return t.created
+}
+
+// Shard returns the shard of the block the transaction started to be tracked in.
+func (t *CrossShardTransaction) Shard() Shard {
+	return t.shard
+}
+
+// Nonce returns the nonce of the block the transaction started to be tracked in.
+func (t *CrossShardTransaction) Nonce() Nonce {
+	return t.nonce
+}
+
+// Results returns the smart contract results collected for the transaction so far.
+func (t *CrossShardTransaction) Results() Transactions {
+	return t.results
+}
+
+// AddResult collects a smart contract result of the transaction, unless it was already collected.
+func (t *CrossShardTransaction) AddResult(r *Transaction) {
+	if t.results.FindByHash(r.hash) != nil {
+		return
+	}
+
+	t.results = append(t.results, r)
 }
 
 func (t *CrossShardTransaction) CounterIsZero() bool {
 	return t.counter == 0
 }
 
-func (t *CrossShardTransaction) IncrementCounter() {
-	t.counter++
-}
+func (t *CrossShardTransaction) copy() *CrossShardTransaction {
+	c := RestoreCrossShardTransaction(&t.transaction, t.counter, t.created, t.shard, t.nonce)
+	c.results = append(Transactions(nil), t.results...)
 
-func (t *CrossShardTransaction) DecrementCounter() {
-	t.counter--
+	return c
 }
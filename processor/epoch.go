@@ -0,0 +1,363 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+)
+
+// EpochTransition reports an epoch once every processed shard produced a block of a later epoch. The counts only
+// cover the blocks processed by the processor, the first epoch after a fresh start is therefore partial.
+type EpochTransition struct {
+	// Epoch is the epoch that ended, the shards are now in Epoch+1 or later.
+	Epoch        int
+	Blocks       map[Shard]int
+	Transactions map[Shard]int
+	// FirstBlockTime and LastBlockTime are the timestamps of the first and last processed blocks of the epoch.
+	FirstBlockTime time.Time
+	LastBlockTime  time.Time
+}
+
+func (t EpochTransition) TotalBlocks() int {
+	total := 0
+	for _, n := range t.Blocks {
+		total += n
+	}
+
+	return total
+}
+
+func (t EpochTransition) TotalTransactions() int {
+	total := 0
+	for _, n := range t.Transactions {
+		total += n
+	}
+
+	return total
+}
+
+type OnEpochTransitionFunc func(transition EpochTransition)
+
+// NewEpochProgress returns the progress of a processor through the epochs, kept in its State.
+func NewEpochProgress() *EpochProgress {
+	return &EpochProgress{shards: map[Shard]*shardEpoch{}}
+}
+
+// EpochProgress tracks the current epoch and round of each shard and counts the blocks and transactions of each shard
+// in the epochs not yet reported. The progress of a shard only depends on its own blocks, so that it can be persisted
+// by the instance processing the shard, see Shard and Merge.
+type EpochProgress struct {
+	shards map[Shard]*shardEpoch
+}
+
+type shardEpoch struct {
+	Epoch     int                      `json:"epoch"`
+	Round     int                      `json:"round"`
+	LastNonce Nonce                    `json:"lastNonce"`
+	Epochs    map[int]*EpochTransition `json:"epochs,omitempty"`
+	// Reported is the last epoch reported by a cluster, recorded in the progress of the first shard of the cluster.
+	Reported *int `json:"reported,omitempty"`
+}
+
+// EpochProgressStorage is implemented by state storages keeping the epoch progress of each shard, so that the
+// workers of a cluster can report the epochs across all the shards of the cluster.
+type EpochProgressStorage interface {
+	// FetchEpochProgress returns the persisted progress of the shards, the shards never persisted being left out.
+	FetchEpochProgress(shards Shards) (*EpochProgress, error)
+}
+
+// CurrentEpoch returns the epoch of the last processed block of the shard.
+func (e *EpochProgress) CurrentEpoch(shard Shard) (int, bool) {
+	s, found := e.shards[shard]
+	if !found {
+		return 0, false
+	}
+
+	return s.Epoch, true
+}
+
+// CurrentRound returns the round of the last processed block of the shard.
+func (e *EpochProgress) CurrentRound(shard Shard) (int, bool) {
+	s, found := e.shards[shard]
+	if !found {
+		return 0, false
+	}
+
+	return s.Round, true
+}
+
+// Shard returns the progress of the shard alone, nil when the shard made no progress.
+func (e *EpochProgress) Shard(shard Shard) *EpochProgress {
+	s, found := e.shards[shard]
+	if !found {
+		return nil
+	}
+
+	return &EpochProgress{shards: map[Shard]*shardEpoch{shard: s}}
+}
+
+// Merge adds the progress of the shards of other, replacing the progress of these shards if any.
+func (e *EpochProgress) Merge(other *EpochProgress) {
+	if other == nil {
+		return
+	}
+
+	for shard, s := range other.shards {
+		e.shards[shard] = s
+	}
+}
+
+// add counts the block in its epoch. Blocks at or below the last nonce counted in their shard, replayed after a
+// restart, are ignored so that they are not counted twice.
+func (e *EpochProgress) add(header *BlockHeader, transactions int) {
+	s, found := e.shards[header.shard]
+	if found && !header.nonce.IsGreaterThan(s.LastNonce) {
+		return
+	}
+
+	if !found {
+		s = &shardEpoch{}
+		e.shards[header.shard] = s
+	}
+
+	s.LastNonce = header.nonce
+	s.Round = header.round
+	if header.epoch > s.Epoch || !found {
+		s.Epoch = header.epoch
+	}
+
+	if s.Epochs == nil {
+		s.Epochs = map[int]*EpochTransition{}
+	}
+
+	epoch, found := s.Epochs[header.epoch]
+	if !found {
+		epoch = &EpochTransition{Epoch: header.epoch, Blocks: map[Shard]int{}, Transactions: map[Shard]int{}}
+		s.Epochs[header.epoch] = epoch
+	}
+
+	epoch.Blocks[header.shard]++
+	epoch.Transactions[header.shard] += transactions
+
+	if epoch.FirstBlockTime.IsZero() || header.timestamp.Before(epoch.FirstBlockTime) {
+		epoch.FirstBlockTime = header.timestamp
+	}
+
+	if header.timestamp.After(epoch.LastBlockTime) {
+		epoch.LastBlockTime = header.timestamp
+	}
+}
+
+// completed removes and returns, oldest first, the epochs every given shard moved past.
+func (e *EpochProgress) completed(shards Shards) []EpochTransition {
+	transitions := e.transitions(shards, nil)
+	if len(transitions) != 0 {
+		e.drop(shards, transitions[len(transitions)-1].Epoch)
+	}
+
+	return transitions
+}
+
+// transitions returns, oldest first, the epochs after reported that every given shard moved past, with the counts
+// of the shards summed up.
+func (e *EpochProgress) transitions(shards Shards, reported *int) []EpochTransition {
+	if len(shards) == 0 {
+		return nil
+	}
+
+	lowestEpoch := -1
+	for _, shard := range shards {
+		s, found := e.shards[shard]
+		if !found {
+			return nil
+		}
+
+		if lowestEpoch == -1 || s.Epoch < lowestEpoch {
+			lowestEpoch = s.Epoch
+		}
+	}
+
+	byEpoch := map[int]*EpochTransition{}
+	for _, shard := range shards {
+		for epoch, counts := range e.shards[shard].Epochs {
+			if epoch >= lowestEpoch || reported != nil && epoch <= *reported {
+				continue
+			}
+
+			transition, found := byEpoch[epoch]
+			if !found {
+				transition = &EpochTransition{Epoch: epoch, Blocks: map[Shard]int{}, Transactions: map[Shard]int{}}
+				byEpoch[epoch] = transition
+			}
+
+			transition.add(counts)
+		}
+	}
+
+	transitions := make([]EpochTransition, 0, len(byEpoch))
+	for _, transition := range byEpoch {
+		transitions = append(transitions, *transition)
+	}
+
+	sort.Slice(transitions, func(i, j int) bool {
+		return transitions[i].Epoch < transitions[j].Epoch
+	})
+
+	return transitions
+}
+
+// drop removes the counts of the shards up to the epoch.
+func (e *EpochProgress) drop(shards Shards, upTo int) {
+	for _, shard := range shards {
+		if s, found := e.shards[shard]; found {
+			for epoch := range s.Epochs {
+				if epoch <= upTo {
+					delete(s.Epochs, epoch)
+				}
+			}
+		}
+	}
+}
+
+// pending reports whether one of the shards counted blocks of an epoch it moved past.
+func (e *EpochProgress) pending(shards Shards) bool {
+	for _, shard := range shards {
+		if s, found := e.shards[shard]; found {
+			for epoch := range s.Epochs {
+				if epoch < s.Epoch {
+					return true
+				}
+			}
+		}
+	}
+
+	return false
+}
+
+func (e *EpochProgress) reported(shard Shard) *int {
+	if s, found := e.shards[shard]; found {
+		return s.Reported
+	}
+
+	return nil
+}
+
+func (e *EpochProgress) markReported(shard Shard, epoch int) {
+	if s, found := e.shards[shard]; found {
+		s.Reported = &epoch
+	}
+}
+
+func (t *EpochTransition) add(other *EpochTransition) {
+	for shard, n := range other.Blocks {
+		t.Blocks[shard] += n
+	}
+
+	for shard, n := range other.Transactions {
+		t.Transactions[shard] += n
+	}
+
+	if t.FirstBlockTime.IsZero() || other.FirstBlockTime.Before(t.FirstBlockTime) {
+		t.FirstBlockTime = other.FirstBlockTime
+	}
+
+	if other.LastBlockTime.After(t.LastBlockTime) {
+		t.LastBlockTime = other.LastBlockTime
+	}
+}
+
+type epochProgressJSON struct {
+	Shards map[Shard]*shardEpoch `json:"shards"`
+}
+
+func (e *EpochProgress) MarshalJSON() ([]byte, error) {
+	return json.Marshal(epochProgressJSON{Shards: e.shards})
+}
+
+func (e *EpochProgress) UnmarshalJSON(data []byte) error {
+	v := epochProgressJSON{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*e = *NewEpochProgress()
+
+	for shard, s := range v.Shards {
+		e.shards[shard] = s
+	}
+
+	return nil
+}
+
+func (p *Processor) trackEpoch(header *BlockHeader, transactions Transactions) {
+	progress := p.internalState.EpochProgress()
+	progress.add(header, len(transactions))
+
+	if p.clusterShards == nil {
+		p.reportEpochs(progress.completed(p.shards))
+
+		return
+	}
+
+	if err := p.trackClusterEpoch(progress); err != nil {
+		log.Printf("could not track the epochs of the cluster: %s\n", err)
+	}
+}
+
+// trackClusterEpoch reports the epochs every shard of the cluster moved past. The worker holding the first shard of
+// the cluster reports them from the progress of the other shards persisted by their workers, and records the last
+// reported epoch in the progress of its shard. The other workers drop their counts of the epochs reported.
+func (p *Processor) trackClusterEpoch(progress *EpochProgress) error {
+	storage, ok := p.stateStorage.(EpochProgressStorage)
+	if !ok || !progress.pending(p.shards) {
+		return nil
+	}
+
+	reporter := p.clusterShards[0]
+	if !p.shards.Contains(reporter) {
+		persisted, err := storage.FetchEpochProgress(Shards{reporter})
+		if err != nil {
+			return err
+		}
+
+		if reported := persisted.reported(reporter); reported != nil {
+			progress.drop(p.shards, *reported)
+		}
+
+		return nil
+	}
+
+	cluster, err := storage.FetchEpochProgress(p.clusterShards.Difference(p.shards))
+	if err != nil {
+		return err
+	}
+
+	for _, shard := range p.shards {
+		cluster.Merge(progress.Shard(shard))
+	}
+
+	transitions := cluster.transitions(p.clusterShards, progress.reported(reporter))
+	if len(transitions) == 0 {
+		return nil
+	}
+
+	last := transitions[len(transitions)-1].Epoch
+	progress.markReported(reporter, last)
+	progress.drop(p.shards, last)
+
+	p.reportEpochs(transitions)
+
+	return nil
+}
+
+func (p *Processor) reportEpochs(transitions []EpochTransition) {
+	for _, transition := range transitions {
+		p.logIfVerbose(fmt.Sprintf("Epoch %d completed: %d block(s) and %d transaction(s)\n", transition.Epoch, transition.TotalBlocks(), transition.TotalTransactions()))
+
+		if p.onEpochTransitionFunc != nil {
+			p.onEpochTransitionFunc(transition)
+		}
+	}
+}
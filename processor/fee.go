@@ -0,0 +1,198 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	ErrInvalidGasPriceModifier = errors.New("gas price modifier is not a decimal number")
+)
+
+// NewFeeConfig returns the parameters of the network used to compute the fees of transactions, as read from the
+// erd_min_gas_limit, erd_gas_per_data_byte and erd_gas_price_modifier fields of its configuration.
+func NewFeeConfig(minGasLimit, gasPerDataByte int, gasPriceModifier string) (*FeeConfig, error) {
+	modifier, ok := new(big.Rat).SetString(gasPriceModifier)
+	if !ok || modifier.Sign() <= 0 {
+		return nil, ErrInvalidGasPriceModifier
+	}
+
+	return &FeeConfig{minGasLimit: minGasLimit, gasPerDataByte: gasPerDataByte, gasPriceModifier: modifier}, nil
+}
+
+type FeeConfig struct {
+	minGasLimit      int
+	gasPerDataByte   int
+	gasPriceModifier *big.Rat
+}
+
+func (c *FeeConfig) MinGasLimit() int {
+	return c.minGasLimit
+}
+
+func (c *FeeConfig) GasPerDataByte() int {
+	return c.gasPerDataByte
+}
+
+func (c *FeeConfig) GasPriceModifier() string {
+	return c.gasPriceModifier.RatString()
+}
+
+// ComputeFee returns the fee paid by the sender of the transaction and the gas it used, given the value of the gas
+// refunds it received.
+//
+// The gas needed to move the balance, made of the minimum gas limit and of the gas per byte of data, is paid at the
+// gas price. A transaction calling a function also pays the rest of its gas limit at the gas price multiplied by the
+// gas price modifier, minus the refund of the gas it did not use.
+func (c *FeeConfig) ComputeFee(tx *Transaction, refund *big.Int) (*big.Int, int) {
+	data, _ := tx.B64DataDecoded()
+	gasPrice := big.NewInt(int64(tx.gasPrice))
+
+	moveBalanceGas := c.minGasLimit + len(data)*c.gasPerDataByte
+	if tx.gasLimit > 0 && moveBalanceGas > tx.gasLimit {
+		moveBalanceGas = tx.gasLimit
+	}
+
+	fee := new(big.Int).Mul(big.NewInt(int64(moveBalanceGas)), gasPrice)
+
+	if !isSmartContractCall(tx) || tx.gasLimit <= moveBalanceGas {
+		return fee, moveBalanceGas
+	}
+
+	processingGasPrice := new(big.Rat).Mul(new(big.Rat).SetInt(gasPrice), c.gasPriceModifier)
+
+	processingFee := new(big.Rat).Mul(big.NewRat(int64(tx.gasLimit-moveBalanceGas), 1), processingGasPrice)
+	processingFee.Sub(processingFee, new(big.Rat).SetInt(refund))
+	if processingFee.Sign() < 0 {
+		processingFee.SetInt64(0)
+	}
+
+	fee.Add(fee, new(big.Int).Quo(processingFee.Num(), processingFee.Denom()))
+
+	gasUsed := moveBalanceGas
+	if processingGasPrice.Sign() > 0 {
+		processingGas := new(big.Rat).Quo(processingFee, processingGasPrice)
+		gasUsed += int(new(big.Int).Quo(processingGas.Num(), processingGas.Denom()).Int64())
+	}
+
+	return fee, gasUsed
+}
+
+func isSmartContractCall(tx *Transaction) bool {
+	return isSmartContractAddress(tx.receiver) || tx.Function() != ""
+}
+
+// FeeReconciliation compares the fees computed for the transactions sent from a block's shard with the fees the block
+// reports. Differences are expected when gas refunds of cross-shard transactions land in later blocks.
+type FeeReconciliation struct {
+	Shard           Shard
+	Nonce           Nonce
+	BlockHash       string
+	ComputedFees    string
+	AccumulatedFees string
+	DeveloperFees   string
+}
+
+// Difference returns the accumulated fees of the block minus the computed fees.
+func (r FeeReconciliation) Difference() string {
+	accumulated, ok := new(big.Int).SetString(r.AccumulatedFees, 10)
+	if !ok {
+		accumulated = new(big.Int)
+	}
+
+	computed, ok := new(big.Int).SetString(r.ComputedFees, 10)
+	if !ok {
+		computed = new(big.Int)
+	}
+
+	return new(big.Int).Sub(accumulated, computed).String()
+}
+
+func (r FeeReconciliation) Matches() bool {
+	return r.Difference() == "0"
+}
+
+type OnFeeReconciliationFunc func(r FeeReconciliation)
+
+// computeFees sets the fee and gas used of the transactions of the block, other than smart contract results and
+// rewards, from the gas refunds found in the block or in their result tree, then reconciles them with the block.
+func (p *Processor) computeFees(header *BlockHeader, transactions Transactions) {
+	if p.feeConfig == nil {
+		return
+	}
+
+	refunds := map[string]Transactions{}
+	addRefund := func(r *Transaction) {
+		if ClassifySCR(r).kind == SCRKindRefund && refunds[r.originalTransactionHash].FindByHash(r.hash) == nil {
+			refunds[r.originalTransactionHash] = append(refunds[r.originalTransactionHash], r)
+		}
+	}
+
+	for _, tx := range transactions {
+		if tx.HasOriginalTransactionHash() {
+			addRefund(tx)
+		}
+
+		if tx.resultTree != nil {
+			for _, r := range tx.resultTree.Results() {
+				addRefund(r)
+			}
+		}
+	}
+
+	computed := new(big.Int)
+
+	for _, tx := range transactions {
+		if tx.HasOriginalTransactionHash() || tx.kind == KindReward || tx.kind == KindSmartContractResult {
+			continue
+		}
+
+		refund := new(big.Int)
+		for _, r := range refunds[tx.hash] {
+			if v, ok := new(big.Int).SetString(r.value, 10); ok {
+				refund.Add(refund, v)
+			}
+		}
+
+		fee, gasUsed := p.feeConfig.ComputeFee(tx, refund)
+		tx.fee, tx.gasUsed = fee.String(), gasUsed
+
+		if tx.IsFromShard(header.shard) {
+			computed.Add(computed, fee)
+		}
+	}
+
+	// The fees of a hyperblock are the ones of its metachain block, they do not cover its transactions
+	if p.hyperblocks {
+		return
+	}
+
+	reconciliation := FeeReconciliation{
+		Shard:           header.shard,
+		Nonce:           header.nonce,
+		BlockHash:       header.hash,
+		ComputedFees:    computed.String(),
+		AccumulatedFees: header.accumulatedFees,
+		DeveloperFees:   header.developerFees,
+	}
+
+	if !reconciliation.Matches() {
+		p.logIfVerbose(fmt.Sprintf("\t| Computed fees %s differ from the accumulated fees %s of block %d in %s\n", reconciliation.ComputedFees, reconciliation.AccumulatedFees, header.nonce, header.shard.Name()))
+	}
+
+	if p.onFeeReconciliationFunc != nil {
+		p.onFeeReconciliationFunc(reconciliation)
+	}
+}
+
+func (p *Processor) fetchFeeConfig() error {
+	config, err := p.dataSource.GetNetworkConfig()
+	if err != nil {
+		return fmt.Errorf("could not fetch network config: %w", err)
+	}
+
+	p.feeConfig, err = config.FeeConfig()
+
+	return err
+}
@@ -0,0 +1,124 @@
+package processor
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+)
+
+// testDelegationContract is a staking provider contract of the mainnet.
+const testDelegationContract = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqpllllsxvv3xm"
+
+func TestNewFeeConfig(t *testing.T) {
+	tests := []struct {
+		modifier string
+		expected string
+		err      error
+	}{
+		{modifier: "0.01", expected: "1/100"},
+		{modifier: "1", expected: "1"},
+		{modifier: "0", err: ErrInvalidGasPriceModifier},
+		{modifier: "-0.01", err: ErrInvalidGasPriceModifier},
+		{modifier: "", err: ErrInvalidGasPriceModifier},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.modifier, func(t *testing.T) {
+			c, err := NewFeeConfig(50000, 1500, tt.modifier)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected the error %v, got %v", tt.err, err)
+			}
+
+			if err == nil && c.GasPriceModifier() != tt.expected {
+				t.Fatalf("expected the modifier %s, got %s", tt.expected, c.GasPriceModifier())
+			}
+		})
+	}
+}
+
+func TestComputeFee(t *testing.T) {
+	/* The parameters of the mainnet */
+	c, err := NewFeeConfig(50000, 1500, "0.01")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		receiver string
+		data     string
+		gasLimit int
+		refund   int64
+		fee      string
+		gasUsed  int
+	}{
+		{
+			name:     "transfer",
+			receiver: testCaller,
+			gasLimit: 50000,
+			fee:      "50000000000000",
+			gasUsed:  50000,
+		},
+		{
+			name:     "transfer with a higher gas limit",
+			receiver: testCaller,
+			gasLimit: 100000,
+			fee:      "50000000000000",
+			gasUsed:  50000,
+		},
+		{
+			name:     "call refunded at the modified gas price",
+			receiver: testDelegationContract,
+			data:     "claimRewards",
+			gasLimit: 6000000,
+			refund:   49320000000000,
+			fee:      "78000000000000",
+			gasUsed:  1068000,
+		},
+		{
+			name:     "call without refund",
+			receiver: testDelegationContract,
+			data:     "claimRewards",
+			gasLimit: 6000000,
+			fee:      "127320000000000",
+			gasUsed:  6000000,
+		},
+		{
+			name:     "built-in function sent to an account",
+			receiver: testCaller,
+			data:     "ESDTTransfer@555344432d633736663166@0f4240",
+			gasLimit: 500000,
+			fee:      "116870000000000",
+			gasUsed:  500000,
+		},
+		{
+			name:     "gas limit below the move balance gas",
+			receiver: testDelegationContract,
+			data:     "claimRewards",
+			gasLimit: 60000,
+			fee:      "60000000000000",
+			gasUsed:  60000,
+		},
+		{
+			name:     "refund above the processing fee",
+			receiver: testDelegationContract,
+			data:     "claimRewards",
+			gasLimit: 6000000,
+			refund:   100000000000000,
+			fee:      "68000000000000",
+			gasUsed:  68000,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newTestCall("tx", testCaller, tt.receiver, "0", tt.data)
+			tx.gasLimit = tt.gasLimit
+
+			fee, gasUsed := c.ComputeFee(tx, big.NewInt(tt.refund))
+			if fee.String() != tt.fee || gasUsed != tt.gasUsed {
+				t.Fatalf("expected a fee of %s for %d gas, got %s for %d gas", tt.fee, tt.gasUsed, fee, gasUsed)
+			}
+		})
+	}
+}
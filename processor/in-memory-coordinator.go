@@ -0,0 +1,78 @@
+package processor
+
+import (
+	"sync"
+	"time"
+)
+
+// NewInMemoryCoordinator returns a Coordinator sharing leases between processors of the same process, mostly useful
+// for tests.
+func NewInMemoryCoordinator() *InMemoryCoordinator {
+	return &InMemoryCoordinator{
+		leases: map[string]*Lease{},
+		tokens: map[string]FencingToken{},
+		now:    time.Now,
+	}
+}
+
+type InMemoryCoordinator struct {
+	mu     sync.Mutex
+	leases map[string]*Lease
+	tokens map[string]FencingToken
+	now    func() time.Time
+}
+
+func (c *InMemoryCoordinator) Acquire(key, owner string, ttl time.Duration) (*Lease, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+
+	current, found := c.leases[key]
+	if found && !current.IsExpired(now) {
+		if current.owner != owner {
+			return nil, ErrLeaseNotAcquired
+		}
+
+		current = NewLease(key, owner, current.token, now.Add(ttl))
+		c.leases[key] = current
+
+		return current, nil
+	}
+
+	c.tokens[key]++
+
+	lease := NewLease(key, owner, c.tokens[key], now.Add(ttl))
+	c.leases[key] = lease
+
+	return lease, nil
+}
+
+func (c *InMemoryCoordinator) Renew(lease *Lease, ttl time.Duration) (*Lease, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	now := c.now()
+
+	current, found := c.leases[lease.key]
+	if !found || current.IsExpired(now) || current.owner != lease.owner || current.token != lease.token {
+		return nil, ErrLeaseLost
+	}
+
+	renewed := NewLease(lease.key, lease.owner, lease.token, now.Add(ttl))
+	c.leases[lease.key] = renewed
+
+	return renewed, nil
+}
+
+func (c *InMemoryCoordinator) Release(lease *Lease) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	current, found := c.leases[lease.key]
+	if found && current.owner == lease.owner && current.token == lease.token {
+		delete(c.leases, lease.key)
+	}
+
+	return nil
+}
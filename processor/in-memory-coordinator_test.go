@@ -0,0 +1,158 @@
+package processor
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestInMemoryCoordinator(t *testing.T) {
+	const ttl = 30 * time.Second
+
+	type step struct {
+		at     time.Duration
+		action string
+		owner  string
+		token  FencingToken
+		err    error
+	}
+
+	tests := []struct {
+		name  string
+		steps []step
+	}{
+		{
+			name: "acquire a free lease",
+			steps: []step{
+				{action: "acquire", owner: "a", token: 1},
+			},
+		},
+		{
+			name: "acquire a lease held by another owner",
+			steps: []step{
+				{action: "acquire", owner: "a", token: 1},
+				{at: 10 * time.Second, action: "acquire", owner: "b", err: ErrLeaseNotAcquired},
+			},
+		},
+		{
+			name: "acquire a lease already held keeps its token",
+			steps: []step{
+				{action: "acquire", owner: "a", token: 1},
+				{at: 10 * time.Second, action: "acquire", owner: "a", token: 1},
+			},
+		},
+		{
+			name: "renew extends the lease",
+			steps: []step{
+				{action: "acquire", owner: "a", token: 1},
+				{at: 20 * time.Second, action: "renew", owner: "a", token: 1},
+				{at: 40 * time.Second, action: "acquire", owner: "b", err: ErrLeaseNotAcquired},
+			},
+		},
+		{
+			name: "expired lease changes hands with a new token",
+			steps: []step{
+				{action: "acquire", owner: "a", token: 1},
+				{at: 30 * time.Second, action: "acquire", owner: "b", token: 2},
+				{at: 31 * time.Second, action: "renew", owner: "a", err: ErrLeaseLost},
+			},
+		},
+		{
+			name: "released lease changes hands with a new token",
+			steps: []step{
+				{action: "acquire", owner: "a", token: 1},
+				{at: time.Second, action: "release", owner: "a"},
+				{at: 2 * time.Second, action: "acquire", owner: "b", token: 2},
+				{at: 3 * time.Second, action: "renew", owner: "a", err: ErrLeaseLost},
+			},
+		},
+		{
+			name: "stale fencing token is rejected",
+			steps: []step{
+				{action: "acquire", owner: "a", token: 1},
+				{at: time.Second, action: "persist", owner: "a"},
+				{at: 30 * time.Second, action: "acquire", owner: "b", token: 2},
+				{at: 31 * time.Second, action: "persist", owner: "b"},
+				{at: 32 * time.Second, action: "persist", owner: "a", err: ErrStaleFencingToken},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Unix(1600000000, 0)
+			now := start
+
+			c := NewInMemoryCoordinator()
+			c.now = func() time.Time {
+				return now
+			}
+
+			storage := NewInMemoryStateStorage()
+			leases := map[string]*Lease{}
+
+			for i, s := range tt.steps {
+				now = start.Add(s.at)
+
+				var (
+					lease *Lease
+					err   error
+				)
+
+				switch s.action {
+				case "acquire":
+					lease, err = c.Acquire("cluster:shard:0", s.owner, ttl)
+				case "renew":
+					lease, err = c.Renew(leases[s.owner], ttl)
+				case "release":
+					err = c.Release(leases[s.owner])
+				case "persist":
+					state := NewState(NewCrossShardDictionary(), NonceByShard{0: Nonce(i)}, nil)
+					err = storage.PersistLastStateFenced(Shards{0}, state, map[Shard]FencingToken{0: leases[s.owner].Token()})
+				}
+
+				if !errors.Is(err, s.err) {
+					t.Fatalf("step %d: expected %v, got %v", i, s.err, err)
+				}
+
+				if lease == nil {
+					continue
+				}
+
+				if lease.Token() != s.token || lease.Owner() != s.owner || !lease.ExpiresAt().Equal(now.Add(ttl)) {
+					t.Fatalf("step %d: expected a lease of %s with token %d until %s, got %+v", i, s.owner, s.token, now.Add(ttl), lease)
+				}
+
+				leases[s.owner] = lease
+			}
+		})
+	}
+}
+
+func TestLeaseTTLMustBePositive(t *testing.T) {
+	tests := []struct {
+		name string
+		ttl  time.Duration
+		err  error
+	}{
+		{name: "zero", ttl: 0, err: ErrLeaseTTLMustBePositive},
+		{name: "negative", ttl: -time.Second, err: ErrLeaseTTLMustBePositive},
+		{name: "too short to be renewed", ttl: 2 * time.Nanosecond, err: ErrLeaseTTLMustBePositive},
+		{name: "positive", ttl: time.Second},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			oo := Options{}
+			_, err := NewProcessor(
+				oo.DataSource(newTestDataSource(Shards{0})),
+				oo.StateStorage(NewInMemoryStateStorage()),
+				oo.Coordinator(NewInMemoryCoordinator(), "cluster", "a"),
+				oo.LeaseTTL(tt.ttl),
+			)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+		})
+	}
+}
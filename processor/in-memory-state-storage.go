@@ -0,0 +1,122 @@
+package processor
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// NewInMemoryStateStorage returns a StateStorage keeping the state in memory, useful for one-shot jobs and tests.
+func NewInMemoryStateStorage() *InMemoryStateStorage {
+	return &InMemoryStateStorage{lastProcessedNonces: NonceByShard{}, fencingTokens: map[Shard]FencingToken{}, epochProgress: map[Shard][]byte{}}
+}
+
+type InMemoryStateStorage struct {
+	mu                  sync.Mutex
+	lastProcessedNonces NonceByShard
+	fencingTokens       map[Shard]FencingToken
+	epochProgress       map[Shard][]byte
+}
+
+func (s *InMemoryStateStorage) FetchLastState(shards []Shard) (*State, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	lastProcessedNonces := NonceByShard{}
+	for _, shard := range shards {
+		nonce, found := s.lastProcessedNonces.Nonce(shard)
+		if !found {
+			return nil, ErrStateNotFound
+		}
+
+		lastProcessedNonces.PutNonce(shard, nonce)
+	}
+
+	epochProgress, err := s.fetchEpochProgress(shards)
+	if err != nil {
+		return nil, err
+	}
+
+	state := NewState(NewCrossShardDictionary(), lastProcessedNonces, nil)
+	state.SetEpochProgress(epochProgress)
+
+	return state, nil
+}
+
+func (s *InMemoryStateStorage) FetchEpochProgress(shards Shards) (*EpochProgress, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.fetchEpochProgress(shards)
+}
+
+func (s *InMemoryStateStorage) fetchEpochProgress(shards Shards) (*EpochProgress, error) {
+	epochProgress := NewEpochProgress()
+	for _, shard := range shards {
+		b, found := s.epochProgress[shard]
+		if !found {
+			continue
+		}
+
+		shardProgress := NewEpochProgress()
+		if err := json.Unmarshal(b, shardProgress); err != nil {
+			return nil, err
+		}
+
+		epochProgress.Merge(shardProgress)
+	}
+
+	return epochProgress, nil
+}
+
+func (s *InMemoryStateStorage) PersistLastState(shards Shards, state *State) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, shard := range shards {
+		if nonce, found := state.LastProcessedNonceInShard(shard); found {
+			s.lastProcessedNonces.PutNonce(shard, nonce)
+		}
+	}
+
+	return s.persistEpochProgress(shards, state)
+}
+
+func (s *InMemoryStateStorage) PersistLastStateFenced(shards Shards, state *State, tokens map[Shard]FencingToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, shard := range shards {
+		if tokens[shard] < s.fencingTokens[shard] {
+			return fmt.Errorf("%w: %s", ErrStaleFencingToken, shard.Name())
+		}
+	}
+
+	for _, shard := range shards {
+		s.fencingTokens[shard] = tokens[shard]
+		if nonce, found := state.LastProcessedNonceInShard(shard); found {
+			s.lastProcessedNonces.PutNonce(shard, nonce)
+		}
+	}
+
+	return s.persistEpochProgress(shards, state)
+}
+
+// The progress is kept serialized so that the state of a running processor does not alias the persisted one.
+func (s *InMemoryStateStorage) persistEpochProgress(shards Shards, state *State) error {
+	for _, shard := range shards {
+		shardProgress := state.EpochProgress().Shard(shard)
+		if shardProgress == nil {
+			continue
+		}
+
+		b, err := json.Marshal(shardProgress)
+		if err != nil {
+			return err
+		}
+
+		s.epochProgress[shard] = b
+	}
+
+	return nil
+}
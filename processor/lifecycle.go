@@ -0,0 +1,106 @@
+package processor
+
+import "time"
+
+// LifecycleStage is a step in the life of a transaction across shards.
+type LifecycleStage string
+
+const (
+	// StageSourceExecuted is emitted when the transaction is seen in a block of its source shard.
+	StageSourceExecuted LifecycleStage = "source-executed"
+	// StageDestinationExecuted is emitted when the transaction is seen in a block of its destination shard.
+	StageDestinationExecuted LifecycleStage = "destination-executed"
+	// StageSCRsPending is emitted when the transaction produced cross-shard smart contract results the processor waits
+	// for, see WaitForFinalizedCrossShardSmartContractResults.
+	StageSCRsPending LifecycleStage = "scrs-pending"
+	// StageCompleted is emitted once the transaction and its smart contract results were executed successfully.
+	StageCompleted LifecycleStage = "completed"
+	// StageFailed is emitted once the transaction or one of its smart contract results failed.
+	StageFailed LifecycleStage = "failed"
+	// StageTimedOut is emitted when the smart contract results of the transaction were not all seen before it was
+	// pruned.
+	StageTimedOut LifecycleStage = "timed-out"
+)
+
+// LifecycleEvent reports a stage reached by the transaction of the given hash. Shard, Nonce and BlockHash locate the
+// block the stage was observed in, they are empty for StageTimedOut.
+//
+// Events are delivered at least once: blocks replayed by the processor emit their events again.
+type LifecycleEvent struct {
+	Stage       LifecycleStage
+	Hash        string
+	Transaction *Transaction
+	Shard       Shard
+	Nonce       Nonce
+	BlockHash   string
+	// Outcome is set for StageCompleted and StageFailed.
+	Outcome Outcome
+	At      time.Time
+}
+
+type OnLifecycleEventFunc func(event LifecycleEvent)
+
+func (p *Processor) emitLifecycleEvent(stage LifecycleStage, tx *Transaction, header *BlockHeader, outcome Outcome) {
+	if p.onLifecycleEventFunc == nil {
+		return
+	}
+
+	event := LifecycleEvent{
+		Stage:       stage,
+		Hash:        tx.hash,
+		Transaction: tx,
+		Outcome:     outcome,
+		At:          time.Now(),
+	}
+
+	if header != nil {
+		event.Shard = header.shard
+		event.Nonce = header.nonce
+		event.BlockHash = header.hash
+	}
+
+	p.onLifecycleEventFunc(event)
+}
+
+// emitExecutionEvents reports the transactions of the block executed in their source or destination shard. Smart
+// contract results are not followed on their own, they are part of the lifecycle of their original transaction.
+func (p *Processor) emitExecutionEvents(header *BlockHeader, transactions Transactions) {
+	if p.onLifecycleEventFunc == nil {
+		return
+	}
+
+	for _, tx := range transactions {
+		if tx.HasOriginalTransactionHash() {
+			continue
+		}
+
+		if tx.IsFromShard(header.shard) {
+			p.emitLifecycleEvent(StageSourceExecuted, tx, header, "")
+		}
+
+		if tx.IsDestinationTo(header.shard) {
+			p.emitLifecycleEvent(StageDestinationExecuted, tx, header, "")
+		}
+	}
+}
+
+// emitCompletionEvent reports the end of the lifecycle of a transaction, from its result tree when it has one and from
+// its status otherwise.
+func (p *Processor) emitCompletionEvent(tx *Transaction, header *BlockHeader) {
+	if p.onLifecycleEventFunc == nil || tx.HasOriginalTransactionHash() {
+		return
+	}
+
+	outcome := OutcomeSuccess
+	if tx.resultTree != nil {
+		outcome = tx.resultTree.outcome
+	} else if tx.status == "fail" || tx.status == "invalid" {
+		outcome = OutcomeFailed
+	}
+
+	if outcome == OutcomeSuccess {
+		p.emitLifecycleEvent(StageCompleted, tx, header, outcome)
+	} else {
+		p.emitLifecycleEvent(StageFailed, tx, header, outcome)
+	}
+}
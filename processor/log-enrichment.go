@@ -0,0 +1,123 @@
+package processor
+
+import (
+	"fmt"
+	"sync"
+)
+
+const (
+	defaultEnrichmentConcurrency = 4
+	defaultEnrichmentCacheSize   = 10000
+)
+
+// LogSource returns the log of a transaction, e.g. elrondgateway.Client reading transaction/{hash}?withResults=true.
+// A transaction which wrote no log has a nil log.
+type LogSource interface {
+	GetTransactionLogs(hash string) (*TransactionLog, error)
+}
+
+// TransactionSelector chooses the transactions to enrich, for instance the ones calling a given contract.
+type TransactionSelector func(tx *Transaction) bool
+
+// logCache keeps the logs of the last transactions fetched, so that the transactions delivered again when blocks are
+// replayed are not fetched again.
+type logCache struct {
+	mu     sync.Mutex
+	size   int
+	logs   map[string]*TransactionLog
+	hashes []string
+}
+
+func newLogCache(size int) *logCache {
+	return &logCache{size: size, logs: map[string]*TransactionLog{}}
+}
+
+func (c *logCache) get(hash string) (*TransactionLog, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	l, found := c.logs[hash]
+
+	return l, found
+}
+
+func (c *logCache) put(hash string, l *TransactionLog) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, found := c.logs[hash]; found || c.size <= 0 {
+		return
+	}
+
+	if len(c.hashes) >= c.size {
+		delete(c.logs, c.hashes[0])
+		c.hashes = c.hashes[1:]
+	}
+
+	c.logs[hash] = l
+	c.hashes = append(c.hashes, hash)
+}
+
+// enrichLogs attaches their log to the selected transactions, fetching at most enrichmentConcurrency of them at once.
+func (p *Processor) enrichLogs(transactions Transactions) error {
+	if p.logSource == nil {
+		return nil
+	}
+
+	if p.logCache == nil {
+		p.logCache = newLogCache(p.enrichmentCacheSize)
+	}
+
+	concurrency := p.enrichmentConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+
+	var (
+		wg       sync.WaitGroup
+		mu       sync.Mutex
+		firstErr error
+	)
+
+	slots := make(chan struct{}, concurrency)
+
+	for _, tx := range transactions {
+		if p.logSelector != nil && !p.logSelector(tx) {
+			continue
+		}
+
+		if l, found := p.logCache.get(tx.hash); found {
+			tx.logs = l
+
+			continue
+		}
+
+		wg.Add(1)
+		slots <- struct{}{}
+
+		go func(tx *Transaction) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+
+			l, err := p.logSource.GetTransactionLogs(tx.hash)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("could not fetch logs of transaction %s: %w", tx.hash, err)
+				}
+				mu.Unlock()
+
+				return
+			}
+
+			tx.logs = l
+			p.logCache.put(tx.hash, l)
+		}(tx)
+	}
+
+	wg.Wait()
+
+	return firstErr
+}
@@ -0,0 +1,119 @@
+package processor
+
+import (
+	"hash/fnv"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Membership keeps track of the workers of a cluster. A worker is a member as long as it keeps joining before its
+// previous registration expires.
+type Membership interface {
+	Join(clusterID, workerID string, ttl time.Duration) error
+	Leave(clusterID, workerID string) error
+	Members(clusterID string) ([]string, error)
+}
+
+// AssignShards distributes the shards between the members using rendezvous hashing, capped so that no member gets
+// more than its fair share. Only a few shards move to another worker when a member joins or leaves the cluster.
+func AssignShards(shards Shards, members []string) map[string]Shards {
+	assignment := make(map[string]Shards, len(members))
+	if len(members) == 0 {
+		return assignment
+	}
+
+	capacity := (len(shards) + len(members) - 1) / len(members)
+
+	ordered := make(Shards, len(shards))
+	copy(ordered, shards)
+	ordered.Sort()
+
+	for _, shard := range ordered {
+		var (
+			owner     string
+			bestScore uint64
+		)
+
+		for _, member := range members {
+			if len(assignment[member]) >= capacity {
+				continue
+			}
+
+			score := rendezvousScore(member, shard)
+			if owner == "" || score > bestScore || (score == bestScore && member < owner) {
+				owner, bestScore = member, score
+			}
+		}
+
+		assignment[owner] = append(assignment[owner], shard)
+	}
+
+	return assignment
+}
+
+func rendezvousScore(member string, shard Shard) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(member))
+	_, _ = h.Write([]byte{byte(shard), byte(shard >> 8), byte(shard >> 16), byte(shard >> 24)})
+
+	// FNV alone barely mixes the last bytes into the high bits, which would favour some members for every shard
+	x := h.Sum64()
+	x ^= x >> 30
+	x *= 0xbf58476d1ce4e5b9
+	x ^= x >> 27
+	x *= 0x94d049bb133111eb
+	x ^= x >> 31
+
+	return x
+}
+
+// NewInMemoryMembership returns a Membership shared by the workers of a single process, mostly useful for tests.
+func NewInMemoryMembership() *InMemoryMembership {
+	return &InMemoryMembership{clusters: map[string]map[string]time.Time{}}
+}
+
+type InMemoryMembership struct {
+	mu       sync.Mutex
+	clusters map[string]map[string]time.Time
+}
+
+func (m *InMemoryMembership) Join(clusterID, workerID string, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, found := m.clusters[clusterID]; !found {
+		m.clusters[clusterID] = map[string]time.Time{}
+	}
+
+	m.clusters[clusterID][workerID] = time.Now().Add(ttl)
+
+	return nil
+}
+
+func (m *InMemoryMembership) Leave(clusterID, workerID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.clusters[clusterID], workerID)
+
+	return nil
+}
+
+func (m *InMemoryMembership) Members(clusterID string) ([]string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+
+	members := make([]string, 0, len(m.clusters[clusterID]))
+	for workerID, expiresAt := range m.clusters[clusterID] {
+		if now.Before(expiresAt) {
+			members = append(members, workerID)
+		}
+	}
+
+	sort.Strings(members)
+
+	return members, nil
+}
@@ -0,0 +1,81 @@
+package processor
+
+import "time"
+
+func NewNetworkConfigBuilder() *NetworkConfigBuilder {
+	return &NetworkConfigBuilder{config: &NetworkConfig{}}
+}
+
+type NetworkConfigBuilder struct {
+	config *NetworkConfig
+}
+
+func (b *NetworkConfigBuilder) NewNetworkConfig() *NetworkConfigBuilder {
+	b.config = &NetworkConfig{}
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) ChainID(c string) *NetworkConfigBuilder {
+	b.config.chainID = c
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) Denomination(d int) *NetworkConfigBuilder {
+	b.config.denomination = d
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) NumShards(n int) *NetworkConfigBuilder {
+	b.config.numShards = n
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) RoundDuration(r time.Duration) *NetworkConfigBuilder {
+	b.config.roundDuration = r
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) RoundsPerEpoch(r int) *NetworkConfigBuilder {
+	b.config.roundsPerEpoch = r
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) StartTime(s time.Time) *NetworkConfigBuilder {
+	b.config.startTime = s
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) MinGasLimit(m int) *NetworkConfigBuilder {
+	b.config.minGasLimit = m
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) MinGasPrice(m int) *NetworkConfigBuilder {
+	b.config.minGasPrice = m
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) GasPerDataByte(g int) *NetworkConfigBuilder {
+	b.config.gasPerDataByte = g
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) GasPriceModifier(g string) *NetworkConfigBuilder {
+	b.config.gasPriceModifier = g
+
+	return b
+}
+
+func (b *NetworkConfigBuilder) Build() *NetworkConfig {
+	return b.config
+}
@@ -0,0 +1,75 @@
+package processor
+
+import "time"
+
+// NetworkConfig is the configuration of the network, which does not change between epochs.
+type NetworkConfig struct {
+	chainID          string
+	denomination     int
+	numShards        int
+	roundDuration    time.Duration
+	roundsPerEpoch   int
+	startTime        time.Time
+	minGasLimit      int
+	minGasPrice      int
+	gasPerDataByte   int
+	gasPriceModifier string
+}
+
+func (c *NetworkConfig) ChainID() string {
+	return c.chainID
+}
+
+// Denomination returns the number of decimals of the native token.
+func (c *NetworkConfig) Denomination() int {
+	return c.denomination
+}
+
+// NumShards returns the number of shards, the metachain excluded.
+func (c *NetworkConfig) NumShards() int {
+	return c.numShards
+}
+
+func (c *NetworkConfig) RoundDuration() time.Duration {
+	return c.roundDuration
+}
+
+func (c *NetworkConfig) RoundsPerEpoch() int {
+	return c.roundsPerEpoch
+}
+
+// StartTime returns the time of the genesis block.
+func (c *NetworkConfig) StartTime() time.Time {
+	return c.startTime
+}
+
+func (c *NetworkConfig) MinGasLimit() int {
+	return c.minGasLimit
+}
+
+func (c *NetworkConfig) MinGasPrice() int {
+	return c.minGasPrice
+}
+
+func (c *NetworkConfig) GasPerDataByte() int {
+	return c.gasPerDataByte
+}
+
+func (c *NetworkConfig) GasPriceModifier() string {
+	return c.gasPriceModifier
+}
+
+// Shards returns the shards of the network, the metachain included.
+func (c *NetworkConfig) Shards() Shards {
+	shards := make(Shards, 0, c.numShards+1)
+	for i := 0; i < c.numShards; i++ {
+		shards = append(shards, Shard(i))
+	}
+
+	return append(shards, ShardMetachain)
+}
+
+// FeeConfig returns the parameters used to compute the fees of transactions.
+func (c *NetworkConfig) FeeConfig() (*FeeConfig, error) {
+	return NewFeeConfig(c.minGasLimit, c.gasPerDataByte, c.gasPriceModifier)
+}
@@ -0,0 +1,79 @@
+package processor
+
+func NewNetworkStatusBuilder() *NetworkStatusBuilder {
+	return &NetworkStatusBuilder{status: &NetworkStatus{}}
+}
+
+type NetworkStatusBuilder struct {
+	status *NetworkStatus
+}
+
+func (b *NetworkStatusBuilder) NewNetworkStatus() *NetworkStatusBuilder {
+	b.status = &NetworkStatus{}
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) Shard(s Shard) *NetworkStatusBuilder {
+	b.status.shard = s
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) Nonce(n Nonce) *NetworkStatusBuilder {
+	b.status.nonce = n
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) HighestFinalNonce(n Nonce) *NetworkStatusBuilder {
+	b.status.highestFinalNonce = n
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) CurrentRound(c int) *NetworkStatusBuilder {
+	b.status.currentRound = c
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) Epoch(e int) *NetworkStatusBuilder {
+	b.status.epoch = e
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) RoundsPerEpoch(r int) *NetworkStatusBuilder {
+	b.status.roundsPerEpoch = r
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) NonceAtEpochStart(n Nonce) *NetworkStatusBuilder {
+	b.status.nonceAtEpochStart = n
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) RoundAtEpochStart(r int) *NetworkStatusBuilder {
+	b.status.roundAtEpochStart = r
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) NoncesPassedInCurrentEpoch(n int) *NetworkStatusBuilder {
+	b.status.noncesPassedInCurrentEpoch = n
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) RoundsPassedInCurrentEpoch(r int) *NetworkStatusBuilder {
+	b.status.roundsPassedInCurrentEpoch = r
+
+	return b
+}
+
+func (b *NetworkStatusBuilder) Build() *NetworkStatus {
+	return b.status
+}
@@ -0,0 +1,57 @@
+package processor
+
+// NetworkStatus is the progress of a shard of the network.
+type NetworkStatus struct {
+	shard                      Shard
+	nonce                      Nonce
+	highestFinalNonce          Nonce
+	currentRound               int
+	epoch                      int
+	roundsPerEpoch             int
+	nonceAtEpochStart          Nonce
+	roundAtEpochStart          int
+	noncesPassedInCurrentEpoch int
+	roundsPassedInCurrentEpoch int
+}
+
+func (s *NetworkStatus) Shard() Shard {
+	return s.shard
+}
+
+// Nonce returns the nonce of the last block of the shard.
+func (s *NetworkStatus) Nonce() Nonce {
+	return s.nonce
+}
+
+// HighestFinalNonce returns the nonce of the last final block of the shard, which can no longer be replaced.
+func (s *NetworkStatus) HighestFinalNonce() Nonce {
+	return s.highestFinalNonce
+}
+
+func (s *NetworkStatus) CurrentRound() int {
+	return s.currentRound
+}
+
+func (s *NetworkStatus) Epoch() int {
+	return s.epoch
+}
+
+func (s *NetworkStatus) RoundsPerEpoch() int {
+	return s.roundsPerEpoch
+}
+
+func (s *NetworkStatus) NonceAtEpochStart() Nonce {
+	return s.nonceAtEpochStart
+}
+
+func (s *NetworkStatus) RoundAtEpochStart() int {
+	return s.roundAtEpochStart
+}
+
+func (s *NetworkStatus) NoncesPassedInCurrentEpoch() int {
+	return s.noncesPassedInCurrentEpoch
+}
+
+func (s *NetworkStatus) RoundsPassedInCurrentEpoch() int {
+	return s.roundsPassedInCurrentEpoch
+}
@@ -0,0 +1,129 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	ErrRangeIsEmpty          = errors.New("nonce range is empty")
+	ErrRangeBoundsMismatch   = errors.New("nonce range bounds must target the same shards")
+	ErrRangeChunksOutOfBound = errors.New("nonce range chunk index must be within the number of chunks")
+	ErrRangeAheadOfShard     = errors.New("nonce range ends after the current nonce of the shard")
+)
+
+// NewNonceRange defines an inclusive range of nonces to process for each shard present in both bounds.
+func NewNonceRange(from, to NonceByShard) (*NonceRange, error) {
+	if len(from) == 0 {
+		return nil, ErrRangeIsEmpty
+	}
+
+	if len(from) != len(to) {
+		return nil, ErrRangeBoundsMismatch
+	}
+
+	for shard := range from {
+		if _, found := to.Nonce(shard); !found {
+			return nil, ErrRangeBoundsMismatch
+		}
+	}
+
+	return &NonceRange{from: from, to: to}, nil
+}
+
+type NonceRange struct {
+	from NonceByShard
+	to   NonceByShard
+}
+
+func (r *NonceRange) From() NonceByShard {
+	return r.from
+}
+
+func (r *NonceRange) To() NonceByShard {
+	return r.to
+}
+
+func (r *NonceRange) Shards() Shards {
+	shards := make(Shards, 0, len(r.from))
+	for shard := range r.from {
+		shards = append(shards, shard)
+	}
+
+	shards.Sort()
+
+	return shards
+}
+
+func (r *NonceRange) Contains(shard Shard, nonce Nonce) bool {
+	from, found := r.from.Nonce(shard)
+	if !found {
+		return false
+	}
+
+	to, _ := r.to.Nonce(shard)
+
+	return !from.IsGreaterThan(nonce) && !nonce.IsGreaterThan(to)
+}
+
+func (r *NonceRange) NumberOfNonces() int {
+	var total int
+	for shard, from := range r.from {
+		to, _ := r.to.Nonce(shard)
+		if !from.IsGreaterThan(to) {
+			total += int(to.Subtract(from)) + 1
+		}
+	}
+
+	return total
+}
+
+// Split divides the range of every shard into n contiguous chunks, so that each chunk can be processed by a different
+// worker. Chunk i holds the i-th part of every shard.
+func (r *NonceRange) Split(n int) []*NonceRange {
+	if n < 1 {
+		n = 1
+	}
+
+	chunks := make([]*NonceRange, n)
+	for i := range chunks {
+		chunks[i] = &NonceRange{from: NonceByShard{}, to: NonceByShard{}}
+	}
+
+	for shard, from := range r.from {
+		to, _ := r.to.Nonce(shard)
+
+		size := int(to.Subtract(from)) + 1
+		if size < 0 {
+			size = 0
+		}
+
+		start := from
+		for i, chunk := range chunks {
+			length := size / n
+			if i < size%n {
+				length++
+			}
+
+			chunk.from.PutNonce(shard, start)
+			chunk.to.PutNonce(shard, start+Nonce(length)-1)
+
+			start += Nonce(length)
+		}
+	}
+
+	return chunks
+}
+
+// Chunk returns the i-th of n chunks as returned by Split.
+func (r *NonceRange) Chunk(i, n int) (*NonceRange, error) {
+	if i < 0 || i >= n {
+		return nil, ErrRangeChunksOutOfBound
+	}
+
+	return r.Split(n)[i], nil
+}
+
+func (r *NonceRange) String() string {
+	return fmt.Sprintf("from %v to %v", r.from, r.to)
+}
@@ -0,0 +1,174 @@
+package processor
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// rangeOf returns the bounds of the range in each shard, e.g. "0:1-5 1:10-12".
+func rangeOf(r *NonceRange) string {
+	bounds := ""
+	for _, shard := range r.Shards() {
+		from, _ := r.From().Nonce(shard)
+		to, _ := r.To().Nonce(shard)
+		if bounds != "" {
+			bounds += " "
+		}
+
+		bounds += fmt.Sprintf("%d:%d-%d", shard, from, to)
+	}
+
+	return bounds
+}
+
+func TestNonceRangeSplit(t *testing.T) {
+	r, err := NewNonceRange(NonceByShard{0: 1, 1: 10}, NonceByShard{0: 10, 1: 12})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		n        int
+		expected []string
+	}{
+		{name: "single chunk", n: 1, expected: []string{"0:1-10 1:10-12"}},
+		{name: "no chunk", n: 0, expected: []string{"0:1-10 1:10-12"}},
+		{name: "even chunks", n: 2, expected: []string{"0:1-5 1:10-11", "0:6-10 1:12-12"}},
+		{name: "first chunks take the remainder", n: 3, expected: []string{"0:1-4 1:10-10", "0:5-7 1:11-11", "0:8-10 1:12-12"}},
+		{name: "more chunks than nonces in a shard", n: 4, expected: []string{"0:1-3 1:10-10", "0:4-6 1:11-11", "0:7-8 1:12-12", "0:9-10 1:13-12"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := r.Split(tt.n)
+
+			got := make([]string, 0, len(chunks))
+			total := 0
+			for _, chunk := range chunks {
+				got = append(got, rangeOf(chunk))
+				total += chunk.NumberOfNonces()
+			}
+
+			if fmt.Sprint(got) != fmt.Sprint(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, got)
+			}
+
+			if total != r.NumberOfNonces() {
+				t.Fatalf("expected the chunks to hold %d nonces, got %d", r.NumberOfNonces(), total)
+			}
+		})
+	}
+}
+
+func TestNonceRangeChunk(t *testing.T) {
+	r, err := NewNonceRange(NonceByShard{0: 1}, NonceByShard{0: 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		i, n     int
+		expected string
+		err      error
+	}{
+		{name: "first chunk", i: 0, n: 3, expected: "0:1-4"},
+		{name: "last chunk", i: 2, n: 3, expected: "0:8-10"},
+		{name: "negative index", i: -1, n: 3, err: ErrRangeChunksOutOfBound},
+		{name: "index past the chunks", i: 3, n: 3, err: ErrRangeChunksOutOfBound},
+		{name: "no chunk", i: 0, n: 0, err: ErrRangeChunksOutOfBound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunk, err := r.Chunk(tt.i, tt.n)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			if err == nil && rangeOf(chunk) != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, rangeOf(chunk))
+			}
+		})
+	}
+}
+
+func TestNonceResolverRangeForWindow(t *testing.T) {
+	/* The blocks of the test data source are six seconds apart from the start */
+	ds := newTestDataSource(Shards{0, 1})
+	ds.setTip(100)
+
+	tests := []struct {
+		name         string
+		since, until time.Duration
+		expected     string
+	}{
+		{name: "window between blocks", since: 10 * time.Second, until: 61 * time.Second, expected: "0:2-10 1:2-10"},
+		{name: "window on blocks", since: 12 * time.Second, until: 60 * time.Second, expected: "0:2-9 1:2-9"},
+		{name: "window not over", since: 590 * time.Second, until: time.Hour, expected: "0:99-100 1:99-100"},
+		{name: "window after the tip", since: time.Hour, until: 2 * time.Hour, expected: "0:101-100 1:101-100"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewNonceResolver(ds).RangeForWindow(Shards{0, 1}, ds.start.Add(tt.since), ds.start.Add(tt.until))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if rangeOf(r) != tt.expected {
+				t.Fatalf("expected %s, got %s", tt.expected, rangeOf(r))
+			}
+		})
+	}
+}
+
+func TestProcessorRange(t *testing.T) {
+	tests := []struct {
+		name      string
+		to        Nonce
+		processed int
+		err       error
+	}{
+		{name: "range before the tip", to: 8, processed: 4},
+		{name: "range up to the tip", to: 10, processed: 6},
+		{name: "range after the tip", to: 11, err: ErrRangeAheadOfShard},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ds := newTestDataSource(Shards{0, 1})
+			ds.setTip(10)
+
+			r, err := NewNonceRange(NonceByShard{0: 5}, NonceByShard{0: tt.to})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			processed := 0
+			oo := Options{}
+			p, err := NewProcessor(
+				oo.DataSource(ds),
+				oo.StateStorage(NewInMemoryStateStorage()),
+				oo.Range(r),
+				oo.OnTransactionsReceived(func(Shard, Nonce, []*Transaction, string) {
+					processed++
+				}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := p.Start(); !errors.Is(err, tt.err) {
+				t.Fatalf("expected %v, got %v", tt.err, err)
+			}
+
+			if processed != tt.processed {
+				t.Fatalf("expected %d block(s) processed, got %d", tt.processed, processed)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+package processor
+
+import (
+	"fmt"
+	"time"
+)
+
+func NewNonceResolver(dataSource DataSource) *NonceResolver {
+	return &NonceResolver{dataSource: dataSource}
+}
+
+// NonceResolver translates wall-clock times and epochs into block nonces by binary searching the block headers of
+// each shard through the data source.
+type NonceResolver struct {
+	dataSource DataSource
+}
+
+// FirstNoncesAtTime returns, for every shard, the first nonce whose block timestamp is at or after t.
+// When no such block exists yet, the nonce following the current tip of the shard is returned.
+func (r *NonceResolver) FirstNoncesAtTime(shards Shards, t time.Time) (NonceByShard, error) {
+	return r.firstNoncesMatching(shards, func(h *BlockHeader) bool {
+		return h.IsAtOrAfter(t)
+	})
+}
+
+// FirstNoncesAtEpoch returns, for every shard, the first nonce whose block belongs to the given epoch or a later one.
+// When no such block exists yet, the nonce following the current tip of the shard is returned.
+func (r *NonceResolver) FirstNoncesAtEpoch(shards Shards, epoch int) (NonceByShard, error) {
+	return r.firstNoncesMatching(shards, func(h *BlockHeader) bool {
+		return h.Epoch() >= epoch
+	})
+}
+
+func (r *NonceResolver) firstNoncesMatching(shards Shards, predicate func(h *BlockHeader) bool) (NonceByShard, error) {
+	result := make(NonceByShard, len(shards))
+
+	for _, shard := range shards {
+		nonce, err := r.firstNonceMatching(shard, predicate)
+		if err != nil {
+			return nil, err
+		}
+
+		result.PutNonce(shard, nonce)
+	}
+
+	return result, nil
+}
+
+// The predicate must be monotonic over the nonces of the shard (false for every block before a given nonce and true
+// for every block after), which holds for both timestamps and epochs.
+func (r *NonceResolver) firstNonceMatching(shard Shard, predicate func(h *BlockHeader) bool) (Nonce, error) {
+	currentNonce, err := r.dataSource.GetCurrentNonceForShard(shard)
+	if err != nil {
+		return 0, fmt.Errorf("could not fetch current nonce for %s: %w", shard.Name(), err)
+	}
+
+	low, high := Nonce(0), currentNonce.Increment()
+	for low < high {
+		middle := low + (high-low)/2
+
+		header, err := r.dataSource.GetBlockHeader(shard, middle)
+		if err != nil {
+			return 0, fmt.Errorf("could not fetch block header for nonce %d in %s: %w", middle, shard.Name(), err)
+		}
+
+		if predicate(header) {
+			high = middle
+		} else {
+			low = middle.Increment()
+		}
+	}
+
+	return low, nil
+}
+
+// RangeForWindow returns the range of nonces whose block timestamps are within [since, until) in every shard. The
+// range of a shard ends at its current nonce while until is not reached yet.
+func (r *NonceResolver) RangeForWindow(shards Shards, since, until time.Time) (*NonceRange, error) {
+	from, err := r.FirstNoncesAtTime(shards, since)
+	if err != nil {
+		return nil, err
+	}
+
+	to, err := r.FirstNoncesAtTime(shards, until)
+	if err != nil {
+		return nil, err
+	}
+
+	for shard, nonce := range to {
+		to.PutNonce(shard, nonce-1)
+	}
+
+	return NewNonceRange(from, to)
+}
@@ -2,6 +2,10 @@ package processor
 
 type Nonce int
 
+// NoNonceProcessed is the last processed nonce of a shard in which no block was processed yet, processing then starts
+// with the block of nonce 0.
+const NoNonceProcessed Nonce = -1
+
 func (n Nonce) Equals(n2 Nonce) bool {
 	return n == n2
 }
@@ -0,0 +1,258 @@
+package processor
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+func newLeaseKeeper(coordinator Coordinator, processorID, instanceID string, mode LeaseMode, ttl time.Duration) *leaseKeeper {
+	return &leaseKeeper{
+		coordinator: coordinator,
+		processorID: processorID,
+		instanceID:  instanceID,
+		mode:        mode,
+		ttl:         ttl,
+		byShard:     map[Shard]*Lease{},
+	}
+}
+
+// leaseKeeper holds the leases of a processor and renews them in the background while the processor is running.
+type leaseKeeper struct {
+	mu          sync.Mutex
+	coordinator Coordinator
+	processorID string
+	instanceID  string
+	mode        LeaseMode
+	ttl         time.Duration
+	byShard     map[Shard]*Lease
+	// heartbeat is called along with each renewal, e.g. to keep a cluster worker registered while it processes.
+	heartbeat func()
+	stop      chan struct{}
+	done      chan struct{}
+}
+
+// acquire returns the shards whose lease is held by the instance. In LeasePerProcessor mode, either every shard or
+// none is returned.
+func (k *leaseKeeper) acquire(shards Shards) (Shards, error) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	if k.mode == LeasePerProcessor {
+		lease, err := k.coordinator.Acquire(processorLeaseKey(k.processorID), k.instanceID, k.ttl)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, shard := range shards {
+			k.byShard[shard] = lease
+		}
+
+		return shards, nil
+	}
+
+	held := make(Shards, 0, len(shards))
+	for _, shard := range shards {
+		lease, err := k.coordinator.Acquire(shardLeaseKey(k.processorID, shard), k.instanceID, k.ttl)
+		if err != nil {
+			log.Printf("Skipping %s: %s\n", shard.Name(), err)
+			delete(k.byShard, shard)
+
+			continue
+		}
+
+		k.byShard[shard] = lease
+		held = append(held, shard)
+	}
+
+	if len(held) == 0 {
+		return nil, ErrLeaseNotAcquired
+	}
+
+	return held, nil
+}
+
+func (k *leaseKeeper) startRenewing() {
+	k.stop = make(chan struct{})
+	k.done = make(chan struct{})
+
+	go func() {
+		defer close(k.done)
+
+		ticker := time.NewTicker(k.ttl / leaseRenewalsPerTTL)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-k.stop:
+				return
+			case <-ticker.C:
+				k.renew()
+
+				if k.heartbeat != nil {
+					k.heartbeat()
+				}
+			}
+		}
+	}()
+}
+
+func (k *leaseKeeper) stopRenewing() {
+	close(k.stop)
+	<-k.done
+}
+
+func (k *leaseKeeper) renew() {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	renewed := map[string]*Lease{}
+	for shard, lease := range k.byShard {
+		if r, done := renewed[lease.key]; done {
+			if r == nil {
+				delete(k.byShard, shard)
+			} else {
+				k.byShard[shard] = r
+			}
+
+			continue
+		}
+
+		r, err := k.coordinator.Renew(lease, k.ttl)
+		if err != nil {
+			log.Printf("Could not renew lease %s for %s: %s\n", lease.key, shard.Name(), err)
+			renewed[lease.key] = nil
+			delete(k.byShard, shard)
+
+			continue
+		}
+
+		renewed[lease.key] = r
+		k.byShard[shard] = r
+	}
+}
+
+func (k *leaseKeeper) holds(shard Shard) bool {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	lease, found := k.byShard[shard]
+
+	return found && !lease.IsExpired(time.Now())
+}
+
+func (k *leaseKeeper) heldShards(shards Shards) Shards {
+	held := make(Shards, 0, len(shards))
+	for _, shard := range shards {
+		if k.holds(shard) {
+			held = append(held, shard)
+		}
+	}
+
+	return held
+}
+
+func (k *leaseKeeper) tokens(shards Shards) map[Shard]FencingToken {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	tokens := make(map[Shard]FencingToken, len(shards))
+	for _, shard := range shards {
+		if lease, found := k.byShard[shard]; found {
+			tokens[shard] = lease.token
+		}
+	}
+
+	return tokens
+}
+
+func (k *leaseKeeper) releaseAll() error {
+	return k.releaseExcept(nil)
+}
+
+// releaseExcept releases the leases of every shard that is not part of kept.
+func (k *leaseKeeper) releaseExcept(kept Shards) error {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+
+	keptKeys := map[string]bool{}
+	for _, shard := range kept {
+		if lease, found := k.byShard[shard]; found {
+			keptKeys[lease.key] = true
+		}
+	}
+
+	released := map[string]bool{}
+	for shard, lease := range k.byShard {
+		if kept.Contains(shard) {
+			continue
+		}
+
+		delete(k.byShard, shard)
+
+		if released[lease.key] || keptKeys[lease.key] {
+			continue
+		}
+
+		released[lease.key] = true
+		if err := k.coordinator.Release(lease); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// ReleaseLeases gives up the leases held by the processor so that another instance can take over immediately
+// instead of waiting for them to expire. It should be called on graceful shutdown.
+func (p *Processor) ReleaseLeases() error {
+	if p.leases == nil {
+		return nil
+	}
+
+	return p.leases.releaseAll()
+}
+
+func (p *Processor) acquireLeases() error {
+	shards, err := p.leases.acquire(p.shards)
+	if err != nil {
+		return err
+	}
+
+	if len(shards) != len(p.shards) {
+		log.Printf("Holding leases for %s only\n", shards)
+	}
+
+	p.shards = shards
+
+	return nil
+}
+
+func (p *Processor) holdsLease(shard Shard) bool {
+	return p.leases == nil || p.leases.holds(shard)
+}
+
+func (p *Processor) persistLastState() error {
+	return p.persistShardsState(p.shards)
+}
+
+func (p *Processor) persistShardState(shard Shard) error {
+	return p.persistShardsState(Shards{shard})
+}
+
+func (p *Processor) persistShardsState(shards Shards) error {
+	if p.leases == nil {
+		return p.stateStorage.PersistLastState(shards, p.internalState)
+	}
+
+	shards = p.leases.heldShards(shards)
+	if len(shards) == 0 {
+		return ErrLeaseLost
+	}
+
+	if fenced, ok := p.stateStorage.(FencedStateStorage); ok {
+		return fenced.PersistLastStateFenced(shards, p.internalState, p.leases.tokens(shards))
+	}
+
+	return p.stateStorage.PersistLastState(shards, p.internalState)
+}
@@ -1,5 +1,11 @@
 package processor
 
+import (
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/abi"
+)
+
 type Option func(*Processor)
 
 type Options struct{}
@@ -39,12 +45,209 @@ func (oo *Options) OnTransactionsReceived(f OnTransactionReceivedFunc) Option {
 	}
 }
 
+// OnLifecycleEvent reports the stages reached by the transactions across shards, see LifecycleStage.
+func (oo *Options) OnLifecycleEvent(f OnLifecycleEventFunc) Option {
+	return func(p *Processor) {
+		p.onLifecycleEventFunc = f
+	}
+}
+
+// CrossShardTimeout sets the block time after which a cross-shard transaction still waiting for smart contract results
+// is given up, see OnCrossShardTransactionTimedOut. It defaults to 10 minutes, zero disables it.
+func (oo *Options) CrossShardTimeout(d time.Duration) Option {
+	return func(p *Processor) {
+		p.crossShardTimeout = d
+	}
+}
+
+// CrossShardNonceTimeout gives up on a cross-shard transaction still waiting for smart contract results once its shard
+// processed n blocks after the one it was seen in. It is disabled by default.
+func (oo *Options) CrossShardNonceTimeout(n Nonce) Option {
+	return func(p *Processor) {
+		p.crossShardNonceTimeout = n
+	}
+}
+
+// OnCrossShardTransactionTimedOut receives the cross-shard transactions given up by the processor, along with the
+// smart contract results collected for them, so that they can be reconciled.
+func (oo *Options) OnCrossShardTransactionTimedOut(f OnCrossShardTransactionTimedOutFunc) Option {
+	return func(p *Processor) {
+		p.onCrossShardTransactionTimedOutFunc = f
+	}
+}
+
+// FeeConfig computes the fee and gas used of every transaction, and reconciles them with the fees of their block.
+func (oo *Options) FeeConfig(c *FeeConfig) Option {
+	return func(p *Processor) {
+		p.feeConfig = c
+	}
+}
+
+// ComputeFees computes the fee and gas used of every transaction like FeeConfig, with the parameters read from the
+// configuration of the network when the processor starts.
+func (oo *Options) ComputeFees() Option {
+	return func(p *Processor) {
+		p.feesFromNetworkConfig = true
+	}
+}
+
+// OnFeeReconciliation receives the comparison of the computed fees with the fees of each processed block, see
+// FeeConfig.
+func (oo *Options) OnFeeReconciliation(f OnFeeReconciliationFunc) Option {
+	return func(p *Processor) {
+		p.onFeeReconciliationFunc = f
+	}
+}
+
+// OnEpochTransition receives the block and transaction counts of an epoch once every processed shard moved past it.
+func (oo *Options) OnEpochTransition(f OnEpochTransitionFunc) Option {
+	return func(p *Processor) {
+		p.onEpochTransitionFunc = f
+	}
+}
+
+// EnrichLogs attaches their log to the delivered transactions accepted by the selector, or to all of them when it is
+// nil. The logs are fetched from the source for each block before it is delivered.
+func (oo *Options) EnrichLogs(source LogSource, selector TransactionSelector) Option {
+	return func(p *Processor) {
+		p.logSource = source
+		p.logSelector = selector
+	}
+}
+
+// EnrichmentConcurrency sets the number of logs fetched at once, 4 by default.
+func (oo *Options) EnrichmentConcurrency(n int) Option {
+	return func(p *Processor) {
+		p.enrichmentConcurrency = n
+	}
+}
+
+// EnrichmentCacheSize sets the number of transactions whose logs are kept in memory, 10000 by default.
+func (oo *Options) EnrichmentCacheSize(n int) Option {
+	return func(p *Processor) {
+		p.enrichmentCacheSize = n
+	}
+}
+
+// ContractABI decodes the calls to the contract of the given address and the events it emits with its ABI, see
+// abi.Load. The decoded values are available from Transaction.DecodedCall and Event.Decoded.
+func (oo *Options) ContractABI(address string, a *abi.ABI) Option {
+	return func(p *Processor) {
+		if p.abis == nil {
+			p.abis = map[string]*abi.ABI{}
+		}
+
+		p.abis[address] = a
+	}
+}
+
+// Sinks delivers every processed block to the given sinks, in addition to the OnTransactionsReceived callback.
+func (oo *Options) Sinks(ss ...Sink) Option {
+	return func(p *Processor) {
+		p.sinks = append(p.sinks, ss...)
+	}
+}
+
 func (oo *Options) PastTransactionBufferPerShard(d int) Option {
 	return func(p *Processor) {
 		p.pastBlocksBuffer = d
 	}
 }
 
+// StartFrom sets the position used to initialise the state when the state storage reports ErrStateNotFound.
+func (oo *Options) StartFrom(s *StartPosition) Option {
+	return func(p *Processor) {
+		p.startPosition = s
+	}
+}
+
+// IncludeShards restricts processing to the given shards. State is only fetched and persisted for these shards.
+func (oo *Options) IncludeShards(shards ...Shard) Option {
+	return func(p *Processor) {
+		p.includedShards = append(p.includedShards, shards...)
+	}
+}
+
+// ExcludeShards removes the given shards from processing. State is neither fetched nor persisted for these shards.
+func (oo *Options) ExcludeShards(shards ...Shard) Option {
+	return func(p *Processor) {
+		p.excludedShards = append(p.excludedShards, shards...)
+	}
+}
+
+// OnlyMetachain restricts processing to the metachain, replacing the shards included before.
+func (oo *Options) OnlyMetachain() Option {
+	return func(p *Processor) {
+		p.includedShards = Shards{ShardMetachain}
+	}
+}
+
+// Hyperblocks walks the nonces of the metachain only, expecting the data source to return for each of them the
+// transactions of its hyperblock, e.g. elrondgateway.HyperblockClient. These transactions are notarized in every shard
+// involved, so they are all delivered as they come and the cross-shard dictionary is not used. It replaces the shards
+// included before.
+func (oo *Options) Hyperblocks() Option {
+	return func(p *Processor) {
+		p.hyperblocks = true
+		p.includedShards = Shards{ShardMetachain}
+	}
+}
+
+// Range bounds the processor to an inclusive range of nonces instead of following the tip of each shard.
+// Only the shards of the range are processed. A range ending after the current nonce of one of its shards is rejected
+// when the processor starts, with ErrRangeAheadOfShard.
+func (oo *Options) Range(r *NonceRange) Option {
+	return func(p *Processor) {
+		p.nonceRange = r
+	}
+}
+
+func (oo *Options) OnRangeCompleted(f OnRangeCompletedFunc) Option {
+	return func(p *Processor) {
+		p.onRangeCompletedFunc = f
+	}
+}
+
+// Coordinator makes the processor hold a lease before processing, so that several instances sharing the same
+// processorID never process a shard twice. instanceID must be unique to each running instance.
+func (oo *Options) Coordinator(c Coordinator, processorID, instanceID string) Option {
+	return func(p *Processor) {
+		p.coordinator = c
+		p.processorID = processorID
+		p.instanceID = instanceID
+	}
+}
+
+// LeasePerShard makes the processor hold one lease per shard instead of a single lease for all of them.
+func (oo *Options) LeasePerShard() Option {
+	return func(p *Processor) {
+		p.leaseMode = LeasePerShard
+	}
+}
+
+// LeaseTTL sets the lifetime of leases, which are renewed every third of it while the processor is running. A TTL
+// that is not positive is rejected by NewProcessor.
+// Start must be called again before the TTL elapses to keep the leases between runs.
+func (oo *Options) LeaseTTL(d time.Duration) Option {
+	return func(p *Processor) {
+		p.leaseTTL = d
+	}
+}
+
+// CrossShardStore replaces the cross-shard dictionary of the state with a store shared by several processors.
+func (oo *Options) CrossShardStore(s CrossShardStore) Option {
+	return func(p *Processor) {
+		p.crossShardStore = s
+	}
+}
+
+// PersistEveryBlock persists the state of a shard after each processed block instead of at the end of each run.
+func (oo *Options) PersistEveryBlock() Option {
+	return func(p *Processor) {
+		p.persistEveryBlock = true
+	}
+}
+
 func (oo *Options) Verbose() Option {
 	return func(p *Processor) {
 		p.verbose = true
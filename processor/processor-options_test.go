@@ -0,0 +1,42 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestSelectShards(t *testing.T) {
+	oo := Options{}
+	available := Shards{0, 1, 2, ShardMetachain}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected Shards
+	}{
+		{name: "all shards", expected: Shards{0, 1, 2, ShardMetachain}},
+		{name: "include", opts: []Option{oo.IncludeShards(0, 2)}, expected: Shards{0, 2}},
+		{name: "include an unavailable shard", opts: []Option{oo.IncludeShards(1, 5)}, expected: Shards{1}},
+		{name: "exclude", opts: []Option{oo.ExcludeShards(ShardMetachain)}, expected: Shards{0, 1, 2}},
+		{name: "include and exclude", opts: []Option{oo.IncludeShards(0, 1), oo.ExcludeShards(1)}, expected: Shards{0}},
+		{name: "only metachain", opts: []Option{oo.OnlyMetachain()}, expected: Shards{ShardMetachain}},
+		{name: "only metachain after include", opts: []Option{oo.IncludeShards(0, 1), oo.OnlyMetachain()}, expected: Shards{ShardMetachain}},
+		{name: "hyperblocks after include", opts: []Option{oo.IncludeShards(0, 1), oo.Hyperblocks()}, expected: Shards{ShardMetachain}},
+		{name: "only metachain excluded", opts: []Option{oo.OnlyMetachain(), oo.ExcludeShards(ShardMetachain)}, expected: Shards{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := append([]Option{oo.DataSource(newTestDataSource(available)), oo.StateStorage(NewInMemoryStateStorage())}, tt.opts...)
+
+			p, err := NewProcessor(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if selected := p.selectShards(available); fmt.Sprint(selected) != fmt.Sprint(tt.expected) {
+				t.Fatalf("expected %v, got %v", tt.expected, selected)
+			}
+		})
+	}
+}
@@ -1,13 +1,17 @@
 package processor
 
 import (
-	"log"
+	"sort"
 	"time"
 )
 
 func NewState(dictionary CrossShardDictionary, fromNonces, toNonces NonceByShard) *State {
+	return NewStateWithCrossShardStore(NewCrossShardDictionaryStore(dictionary), fromNonces, toNonces)
+}
+
+func NewStateWithCrossShardStore(store CrossShardStore, fromNonces, toNonces NonceByShard) *State {
 	s := State{
-		crossShardDictionary:        dictionary,
+		crossShardStore:             store,
 		lastProcessedNoncesInternal: fromNonces,
 		toNonces:                    toNonces,
 	}
@@ -16,9 +20,10 @@ func NewState(dictionary CrossShardDictionary, fromNonces, toNonces NonceByShard
 }
 
 type State struct {
-	crossShardDictionary        CrossShardDictionary
+	crossShardStore             CrossShardStore
 	lastProcessedNoncesInternal NonceByShard
 	toNonces                    NonceByShard
+	epochProgress               *EpochProgress
 }
 
 func (s *State) LastProcessedNonces() NonceByShard {
@@ -39,30 +44,73 @@ func (s *State) LastNonceToProcessInShard(shard Shard) (Nonce, bool) {
 
 func (s *State) AddBufferToLastProcessNonces(buffer int) {
 	for shard, nonce := range s.lastProcessedNoncesInternal {
-		s.lastProcessedNoncesInternal[shard] = nonce.Subtract(Nonce(buffer + 1))
+		nonce = nonce.Subtract(Nonce(buffer + 1))
+		if nonce < NoNonceProcessed {
+			nonce = NoNonceProcessed
+		}
+
+		s.lastProcessedNoncesInternal[shard] = nonce
+	}
+}
+
+// EpochProgress returns the epochs reached by the shards, persisted along with the last processed nonces by the
+// state storages that support it.
+func (s *State) EpochProgress() *EpochProgress {
+	if s.epochProgress == nil {
+		s.epochProgress = NewEpochProgress()
 	}
+
+	return s.epochProgress
+}
+
+func (s *State) SetEpochProgress(e *EpochProgress) {
+	s.epochProgress = e
 }
 
-func (s *State) FindCrossShardTransactionByHash(h string) *CrossShardTransaction {
-	return s.crossShardDictionary.FindTransaction(h)
+func (s *State) CrossShardStore() CrossShardStore {
+	return s.crossShardStore
 }
 
-func (s *State) SetCrossShardTransactionByHash(h string, t *CrossShardTransaction) {
-	s.crossShardDictionary.Set(h, t)
+func (s *State) FindCrossShardTransactionByHash(h string) (*CrossShardTransaction, error) {
+	return s.crossShardStore.FindTransaction(h)
 }
 
-func (s *State) DeleteCrossShardTransaction(h string) {
-	s.crossShardDictionary.Delete(h)
+func (s *State) SetCrossShardTransactionByHash(h string, t *CrossShardTransaction) error {
+	return s.crossShardStore.Set(h, t)
 }
 
-func (s *State) PruneCrossShardDictionary() {
-	for h, crossShardTransaction := range s.crossShardDictionary {
-		elapsed := time.Now().Sub(crossShardTransaction.created)
-		if elapsed > 600*time.Second {
-			log.Printf("pruning transaction with hash %s since its elapsed time is %.2f seconds", h, elapsed.Seconds())
-			s.crossShardDictionary.Delete(h)
+func (s *State) DeleteCrossShardTransaction(h string) error {
+	_, err := s.crossShardStore.Delete(h)
+
+	return err
+}
+
+// PruneCrossShardDictionary stops tracking the cross-shard transactions tracked in one of the shards since a block
+// older than createdBefore, unless it is zero, or whose nonce is below the one of noncesBefore for its shard, that is
+// the ones that waited too long for their smart contract results, and returns them. A transaction pruned by another
+// processor in the meantime is left out.
+func (s *State) PruneCrossShardDictionary(shards Shards, createdBefore time.Time, noncesBefore NonceByShard) ([]*CrossShardTransaction, error) {
+	pruned := make([]*CrossShardTransaction, 0)
+
+	hashes, err := s.crossShardStore.Expired(shards, createdBefore, noncesBefore)
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(hashes)
+
+	for _, h := range hashes {
+		crossShardTransaction, err := s.crossShardStore.Delete(h)
+		if err != nil {
+			return nil, err
+		}
+
+		if crossShardTransaction != nil {
+			pruned = append(pruned, crossShardTransaction)
 		}
 	}
+
+	return pruned, nil
 }
 
 func (s *State) NumberOfRemainingNonces() int {
@@ -8,6 +8,7 @@ import (
 	"time"
 
 	"github.com/schollz/progressbar/v3"
+	"github.com/thefabric-io/elrond-transaction-processor/abi"
 )
 
 var (
@@ -16,6 +17,10 @@ var (
 	ErrPastTransactionMustBePositive = errors.New("past transaction buffer must be positive")
 	ErrLastNonceToProcessNotFound    = errors.New("last nonce to process is not found")
 	ErrLastProcessedNonceNotFound    = errors.New("last processed nonce is not found")
+	ErrStateNotFound                 = errors.New("state of processor is not found")
+	ErrNoShardSelected               = errors.New("no shard is selected for processing")
+	ErrCoordinationIdentityUndefined = errors.New("processor and instance IDs must be defined when using a coordinator")
+	ErrLeaseTTLMustBePositive        = errors.New("lease TTL must be positive")
 )
 
 const (
@@ -25,6 +30,18 @@ const (
 
 type OnTransactionReceivedFunc func(shard Shard, nonce Nonce, transactions []*Transaction, blockHash string)
 
+type OnRangeCompletedFunc func(report RangeReport)
+
+// RangeReport summarises the work done by a processor configured with a nonce range once every shard reached the
+// upper bound of the range.
+type RangeReport struct {
+	Range                 *NonceRange
+	ProcessedBlocks       int
+	ProcessedTransactions int
+	StartedAt             time.Time
+	CompletedAt           time.Time
+}
+
 var defaultTransactionProcessor = Processor{
 	pastBlocksBuffer: defaultPastTransactionBuffer,
 	waitForFinalizedCrossShardSmartContractResults: false,
@@ -32,8 +49,14 @@ var defaultTransactionProcessor = Processor{
 	includeCrossShardStartedTransactions: false,
 	onTransactionsReceivedFunc:           nil,
 	verbose:                              false,
+	leaseMode:                            LeasePerProcessor,
+	leaseTTL:                             defaultLeaseTTL,
+	replayPastBlocks:                     true,
+	crossShardTimeout:                    defaultCrossShardTimeout,
+	enrichmentConcurrency:                defaultEnrichmentConcurrency,
+	enrichmentCacheSize:                  defaultEnrichmentCacheSize,
 	internalState: &State{
-		crossShardDictionary:        NewCrossShardDictionary(),
+		crossShardStore:             NewCrossShardDictionaryStore(NewCrossShardDictionary()),
 		lastProcessedNoncesInternal: NonceByShard{},
 	},
 }
@@ -58,6 +81,10 @@ func NewProcessor(opts ...Option) (*Processor, error) {
 		return nil, err
 	}
 
+	if p.coordinator != nil {
+		p.leases = newLeaseKeeper(p.coordinator, p.processorID, p.instanceID, p.leaseMode, p.leaseTTL)
+	}
+
 	return &p, nil
 }
 
@@ -66,9 +93,46 @@ type Processor struct {
 	stateStorage                                   StateStorage
 	startDate                                      time.Time
 	shards                                         Shards
+	selectedShards                                 Shards
+	includedShards                                 Shards
+	assignedShards                                 Shards
+	clusterShards                                  Shards
+	excludedShards                                 Shards
+	warnedExcludedShards                           map[Shard]bool
+	coordinator                                    Coordinator
+	processorID                                    string
+	instanceID                                     string
+	leaseMode                                      LeaseMode
+	leaseTTL                                       time.Duration
+	leases                                         *leaseKeeper
+	crossShardStore                                CrossShardStore
+	replayPastBlocks                               bool
+	persistEveryBlock                              bool
+	sinks                                          []Sink
+	startPosition                                  *StartPosition
+	nonceRange                                     *NonceRange
+	onRangeCompletedFunc                           OnRangeCompletedFunc
+	processedBlocks                                int
+	processedTransactions                          int
 	onTransactionsReceivedFunc                     OnTransactionReceivedFunc
+	onLifecycleEventFunc                           OnLifecycleEventFunc
+	onCrossShardTransactionTimedOutFunc            OnCrossShardTransactionTimedOutFunc
+	crossShardTimeout                              time.Duration
+	crossShardNonceTimeout                         Nonce
+	lastBlockTime                                  time.Time
+	feeConfig                                      *FeeConfig
+	feesFromNetworkConfig                          bool
+	onFeeReconciliationFunc                        OnFeeReconciliationFunc
+	onEpochTransitionFunc                          OnEpochTransitionFunc
+	logSource                                      LogSource
+	logSelector                                    TransactionSelector
+	logCache                                       *logCache
+	enrichmentConcurrency                          int
+	enrichmentCacheSize                            int
+	abis                                           map[string]*abi.ABI
 	pastBlocksBuffer                               int
 	waitForFinalizedCrossShardSmartContractResults bool
+	hyperblocks                                    bool
 	notifyEmptyBlocks                              bool
 	includeCrossShardStartedTransactions           bool
 	internalState                                  *State
@@ -90,6 +154,15 @@ func (p *Processor) Validate() error {
 		return ErrPastTransactionMustBePositive
 	}
 
+	if p.coordinator != nil && (p.processorID == "" || p.instanceID == "") {
+		return ErrCoordinationIdentityUndefined
+	}
+
+	/* Leases are renewed every third of their TTL, which must be at least a nanosecond */
+	if p.leaseTTL/leaseRenewalsPerTTL <= 0 {
+		return ErrLeaseTTLMustBePositive
+	}
+
 	return nil
 }
 
@@ -105,12 +178,43 @@ func (p *Processor) Start() (err error) {
 		panic(err)
 	}
 
-	p.internalState, err = p.stateStorage.FetchLastState(p.shards)
+	p.shards = p.selectShards(p.shards)
+
+	if p.assignedShards != nil {
+		p.shards = p.shards.Intersect(p.assignedShards)
+	}
+
+	if p.nonceRange != nil {
+		p.shards = p.shards.Intersect(p.nonceRange.Shards())
+	}
+
+	if len(p.shards) == 0 {
+		return ErrNoShardSelected
+	}
+
+	if p.feesFromNetworkConfig && p.feeConfig == nil {
+		if err = p.fetchFeeConfig(); err != nil {
+			return err
+		}
+	}
+
+	if p.leases != nil {
+		if err = p.acquireLeases(); err != nil {
+			return err
+		}
+
+		p.leases.startRenewing()
+		defer p.leases.stopRenewing()
+	}
+
+	p.internalState, err = p.fetchLastState()
 	if err != nil {
-		return fmt.Errorf("could not fetch last state of processor: %w", err)
+		return err
 	}
 
-	p.internalState.AddBufferToLastProcessNonces(p.pastBlocksBuffer)
+	if p.crossShardStore != nil {
+		p.internalState.crossShardStore = p.crossShardStore
+	}
 
 	if p.internalState.toNonces == nil {
 		p.internalState.toNonces, err = p.dataSource.GetCurrentNoncesForShards(p.shards)
@@ -123,9 +227,8 @@ func (p *Processor) Start() (err error) {
 		return err
 	}
 
-	p.internalState.PruneCrossShardDictionary()
-
 	p.startDate = time.Now()
+	p.processedBlocks, p.processedTransactions = 0, 0
 
 	log.Printf("Targeted shards: %s\n\n", p.shards)
 
@@ -143,6 +246,14 @@ func (p *Processor) Start() (err error) {
 		for _, shard := range p.shards {
 			shardName := shard.Name()
 
+			if !p.holdsLease(shard) {
+				if p.leaseMode == LeasePerProcessor {
+					return ErrLeaseLost
+				}
+
+				continue
+			}
+
 			lastNonceToProcess, found := p.internalState.LastNonceToProcessInShard(shard)
 			if !found {
 				return ErrLastNonceToProcessNotFound
@@ -186,27 +297,190 @@ func (p *Processor) Start() (err error) {
 			p.logIfVerbose(fmt.Sprintf("Setting last processed nonce for %s to %d\n\n", shard.Name(), nonce))
 			p.internalState.lastProcessedNoncesInternal.PutNonce(shard, nonce)
 
+			if p.persistEveryBlock {
+				if err := p.flushSinks(); err != nil {
+					return err
+				}
+
+				if err := p.persistShardState(shard); err != nil {
+					return err
+				}
+			}
+
 			p.incrementProgressBar()
 		}
+
+		if err := p.pruneCrossShardTransactions(); err != nil {
+			return err
+		}
 	}
 
+	p.reportRangeCompletion()
+
 	return nil
 }
 
+func (p *Processor) selectShards(available Shards) Shards {
+	selected := available
+	if len(p.includedShards) != 0 {
+		selected = selected.Intersect(p.includedShards)
+	}
+
+	if len(p.excludedShards) != 0 {
+		selected = selected.Difference(p.excludedShards)
+	}
+
+	excluded := available.Difference(selected)
+	if len(excluded) != 0 && p.waitForFinalizedCrossShardSmartContractResults {
+		log.Printf("Warning: %s are excluded, cross-shard SCRs involving them will never be finalized\n", excluded)
+	}
+
+	p.warnedExcludedShards = map[Shard]bool{}
+	p.selectedShards = selected
+
+	return selected
+}
+
+func (p *Processor) warnIfCounterpartShardIsExcluded(tx *Transaction) {
+	shard := tx.destinationShard
+	if p.selectedShards.Contains(shard) || p.warnedExcludedShards[shard] {
+		return
+	}
+
+	p.warnedExcludedShards[shard] = true
+
+	log.Printf("Warning: cross-shard SCR %s for original tx hash %s targets %s which is not processed, the original transaction will stay pending until pruned\n", tx.hash, tx.originalTransactionHash, shard.Name())
+}
+
+func (p *Processor) fetchLastState() (*State, error) {
+	if p.nonceRange != nil {
+		return p.fetchRangeState()
+	}
+
+	state, err := p.stateStorage.FetchLastState(p.shards)
+	if err == nil {
+		if p.replayPastBlocks {
+			state.AddBufferToLastProcessNonces(p.pastBlocksBuffer)
+		}
+
+		return state, nil
+	}
+
+	if !errors.Is(err, ErrStateNotFound) || p.startPosition == nil {
+		return nil, fmt.Errorf("could not fetch last state of processor: %w", err)
+	}
+
+	log.Printf("No previous state found, resolving start position from %s\n", p.startPosition)
+
+	lastProcessedNonces, err := p.startPosition.LastProcessedNonces(NewNonceResolver(p.dataSource), p.shards)
+	if err != nil {
+		return nil, fmt.Errorf("could not resolve start position %s: %w", p.startPosition, err)
+	}
+
+	return NewState(NewCrossShardDictionary(), lastProcessedNonces, nil), nil
+}
+
+// A range is resumed from the state storage when a previous run over the same state left its cursor within the
+// range. Any other cursor is ignored so that a range never processes blocks outside its bounds.
+func (p *Processor) fetchRangeState() (*State, error) {
+	currentNonces, err := p.dataSource.GetCurrentNoncesForShards(p.shards)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch current nonces for shards: %w", err)
+	}
+
+	/* The blocks after the current nonce do not exist yet, the range would never complete */
+	for _, shard := range p.shards {
+		to, _ := p.nonceRange.To().Nonce(shard)
+		if current, found := currentNonces.Nonce(shard); found && to.IsGreaterThan(current) {
+			return nil, fmt.Errorf("%w: %s ends at %d, its current nonce is %d", ErrRangeAheadOfShard, shard.Name(), to, current)
+		}
+	}
+
+	state, err := p.stateStorage.FetchLastState(p.shards)
+	if err != nil && !errors.Is(err, ErrStateNotFound) {
+		return nil, fmt.Errorf("could not fetch last state of processor: %w", err)
+	}
+
+	lastProcessedNonces := NonceByShard{}
+	for _, shard := range p.shards {
+		from, _ := p.nonceRange.From().Nonce(shard)
+		to, _ := p.nonceRange.To().Nonce(shard)
+
+		lastProcessedNonce := from - 1
+		if state != nil {
+			if nonce, found := state.LastProcessedNonceInShard(shard); found && p.nonceRange.Contains(shard, nonce) {
+				lastProcessedNonce = nonce
+			}
+		}
+
+		if lastProcessedNonce.IsGreaterThan(to) {
+			lastProcessedNonce = to
+		}
+
+		lastProcessedNonces.PutNonce(shard, lastProcessedNonce)
+	}
+
+	store := NewCrossShardDictionaryStore(NewCrossShardDictionary())
+	if state != nil && state.crossShardStore != nil {
+		store = state.crossShardStore
+	}
+
+	rangeState := NewStateWithCrossShardStore(store, lastProcessedNonces, p.nonceRange.To())
+	if state != nil {
+		rangeState.epochProgress = state.epochProgress
+	}
+
+	return rangeState, nil
+}
+
+func (p *Processor) reportRangeCompletion() {
+	if p.nonceRange == nil {
+		return
+	}
+
+	report := RangeReport{
+		Range:                 p.nonceRange,
+		ProcessedBlocks:       p.processedBlocks,
+		ProcessedTransactions: p.processedTransactions,
+		StartedAt:             p.startDate,
+		CompletedAt:           time.Now(),
+	}
+
+	log.Printf("Completed range %s: %d block(s) and %d transaction(s) processed in %s\n", p.nonceRange, report.ProcessedBlocks, report.ProcessedTransactions, report.CompletedAt.Sub(report.StartedAt))
+
+	if p.onRangeCompletedFunc != nil {
+		p.onRangeCompletedFunc(report)
+	}
+}
+
 func (p *Processor) processValidTransactions(shard Shard, nonce Nonce) error {
 	p.logIfVerbose(fmt.Sprintf("Begin transaction processing for nonce %d in %s\n", nonce, shard.Name()))
 
-	blockHash, transactions, err := p.dataSource.GetShardTransactions(shard, nonce)
+	header, transactions, err := p.dataSource.GetShardTransactions(shard, nonce)
 	if err != nil {
 		log.Println(err)
 
 		return err
 	}
 
+	if header.timestamp.After(p.lastBlockTime) {
+		p.lastBlockTime = header.timestamp
+	}
+
+	if p.hyperblocks {
+		return p.processHyperblockTransactions(header, transactions)
+	}
+
 	validTransactions := make(Transactions, 0)
 
+	p.emitExecutionEvents(header, transactions)
+
 	if p.waitForFinalizedCrossShardSmartContractResults {
-		finalizedTransactions := p.finalizedCrossShardScrTransactions(shard, transactions)
+		finalizedTransactions, err := p.finalizedCrossShardScrTransactions(header, transactions)
+		if err != nil {
+			return fmt.Errorf("could not track cross-shard transactions in %s: %w", shard.Name(), err)
+		}
+
 		for _, tx := range finalizedTransactions {
 			validTransactions = append(validTransactions, tx)
 		}
@@ -220,34 +494,100 @@ func (p *Processor) processValidTransactions(shard Shard, nonce Nonce) error {
 			continue
 		}
 
+		// transactions finalized with their result tree in this block were already added
+		if validTransactions.FindByHash(tx.hash) != nil {
+			continue
+		}
+
 		// we skip transactions that are cross shard and still pending for smart-contract results
-		if cst := p.internalState.FindCrossShardTransactionByHash(tx.hash); cst != nil {
+		cst, err := p.internalState.FindCrossShardTransactionByHash(tx.hash)
+		if err != nil {
+			return err
+		}
+
+		if cst != nil {
 			p.logIfVerbose(fmt.Sprintf("\t| Transaction with hash %s is still awaiting cross shard SCRs, skipping...", tx.hash))
 
 			continue
 		}
 
 		validTransactions = append(validTransactions, tx)
+
+		// transactions finalized with their result tree in this block already reported their completion
+		if tx.IsDestinationTo(shard) && tx.resultTree == nil {
+			p.emitCompletionEvent(tx, header)
+		}
 	}
 
+	p.computeFees(header, transactions)
+
+	return p.deliver(header, transactions, validTransactions)
+}
+
+// A hyperblock only holds transactions notarized in both their source and destination shards, they all reached the
+// end of their lifecycle.
+func (p *Processor) processHyperblockTransactions(header *BlockHeader, transactions Transactions) error {
+	for _, tx := range transactions {
+		p.emitCompletionEvent(tx, header)
+	}
+
+	p.computeFees(header, transactions)
+
+	return p.deliver(header, transactions, transactions)
+}
+
+func (p *Processor) deliver(header *BlockHeader, transactions, validTransactions Transactions) error {
+	if err := p.enrichLogs(validTransactions); err != nil {
+		return fmt.Errorf("could not enrich block %d of %s: %w", header.nonce, header.shard.Name(), err)
+	}
+
+	p.decodeWithABIs(validTransactions)
+	p.trackEpoch(header, transactions)
+
+	p.processedBlocks++
+	p.processedTransactions += len(validTransactions)
+
 	if !validTransactions.IsEmpty() || p.notifyEmptyBlocks {
 		p.logIfVerbose(fmt.Sprintf("\t| Sending %d valid transaction(s) to event consumer...\n", len(validTransactions)))
 
-		p.onTransactionsReceivedFunc(shard, nonce, validTransactions, blockHash)
+		if p.onTransactionsReceivedFunc != nil {
+			p.onTransactionsReceivedFunc(header.shard, header.nonce, validTransactions, header.Hash())
+		}
+
+		if err := p.sendToSinks(NewBlock(header, validTransactions)); err != nil {
+			return err
+		}
 	}
 
 	return nil
 }
 
-func (p *Processor) finalizedCrossShardScrTransactions(shard Shard, transactions Transactions) []*Transaction {
+func (p *Processor) finalizedCrossShardScrTransactions(header *BlockHeader, transactions Transactions) ([]*Transaction, error) {
+	shard := header.shard
 	finalizedTransactions := make(Transactions, 0)
+	withoutPendingResults := make([]string, 0)
 
 	/*
-		Step 1: we add (incrementing counter) pending transactions in the dictionary from current shard to another one
+		Step 1: we add (incrementing counter) pending transactions in the dictionary from current shard to another one.
+		A transaction starts to be tracked with the counter of its first result, so that no other processor sharing the
+		store can see it at zero before it is counted.
 	*/
 	for _, tx := range transactions {
 		if tx.IsPendingAndOutgoingFromShard(shard) {
-			crossShardTransaction := p.internalState.FindCrossShardTransactionByHash(tx.originalTransactionHash)
+			p.warnIfCounterpartShardIsExcluded(tx)
+
+			holdsFinalization := true
+			if result := ClassifySCR(tx); !result.holdsFinalization() {
+				p.logIfVerbose(fmt.Sprintf("\t| Not incrementing counter for cross-shard SCR, original tx hash %s, tx hash %s since it is a %s with code %q\n", tx.originalTransactionHash, tx.hash, result.kind, result.code))
+
+				holdsFinalization = false
+			}
+
+			crossShardTransaction, err := p.internalState.FindCrossShardTransactionByHash(tx.originalTransactionHash)
+			if err != nil {
+				return nil, err
+			}
+
 			if crossShardTransaction == nil {
 				originalTx := transactions.FindByHash(tx.originalTransactionHash)
 				if originalTx == nil {
@@ -258,73 +598,129 @@ func (p *Processor) finalizedCrossShardScrTransactions(shard Shard, transactions
 
 				p.logIfVerbose(fmt.Sprintf("\t| Creating dictionary for original tx hash %s\n", tx.originalTransactionHash))
 
-				crossShardTransaction = NewCrossShardTransaction(originalTx)
-				p.internalState.SetCrossShardTransactionByHash(originalTx.hash, crossShardTransaction)
+				crossShardTransaction = NewCrossShardTransaction(originalTx, header)
+				if holdsFinalization {
+					crossShardTransaction.counter = 1
+				}
+
+				if err := p.internalState.SetCrossShardTransactionByHash(originalTx.hash, crossShardTransaction); err != nil {
+					return nil, err
+				}
+
+				if !holdsFinalization {
+					withoutPendingResults = append(withoutPendingResults, originalTx.hash)
+				}
+
+				p.emitLifecycleEvent(StageSCRsPending, originalTx, header, "")
+
+				continue
 			}
 
-			if tx.DataEquals("@6f6b") {
-				p.logIfVerbose(fmt.Sprintf("\t| Not incrementing counter for cross-shard SCR, original tx hash %s, tx hash %s since the data is @ok (@6f6b)\n", tx.originalTransactionHash, tx.hash))
+			if !holdsFinalization {
+				continue
+			}
+
+			counter, _, err := p.internalState.crossShardStore.AddToCounter(tx.originalTransactionHash, 1)
+			if errors.Is(err, ErrCrossShardTransactionNotTracked) {
+				p.logIfVerbose(fmt.Sprintf("\t| Cross-shard transaction %s completed before SCR %s was counted\n", tx.originalTransactionHash, tx.hash))
 
 				continue
 			}
 
-			crossShardTransaction.IncrementCounter()
+			if err != nil {
+				return nil, err
+			}
 
-			p.logIfVerbose(fmt.Sprintf("\t| Detected new cross-shard SCR for original tx hash %s, tx hash %s, counter = %d\n", tx.originalTransactionHash, tx.hash, crossShardTransaction.counter))
+			p.logIfVerbose(fmt.Sprintf("\t| Detected new cross-shard SCR for original tx hash %s, tx hash %s, counter = %d\n", tx.originalTransactionHash, tx.hash, counter))
+		}
+	}
 
-			p.internalState.crossShardDictionary.Set(tx.originalTransactionHash, crossShardTransaction)
+	/*
+		Step 2: We collect the smart contract results of the pending transactions, whatever their shards, to deliver
+		their tree once finalized
+	*/
+	for _, tx := range transactions {
+		if tx.HasOriginalTransactionHash() {
+			if err := p.internalState.crossShardStore.AddResult(tx.originalTransactionHash, tx); err != nil {
+				return nil, err
+			}
 		}
 	}
 
 	/*
-		Step 2: We remove (decrementing counter) pending transactions in the dictionary from another shard to current shard
+		Step 3: We remove (decrementing counter) pending transactions in the dictionary from another shard to current
+		shard. The transactions whose counter reaches zero are completed by the processor that decremented it, in the
+		same step, and the ones created without any result to wait for are completed right away.
 	*/
+	completed := make([]*CrossShardTransaction, 0)
+
 	for _, tx := range transactions {
 		if tx.IsPendingAndIncomingToShard(shard) {
-			cst := p.internalState.FindCrossShardTransactionByHash(tx.originalTransactionHash)
-			if cst == nil {
-				p.logIfVerbose(fmt.Sprintf("\t| No counter available for cross-shard SCR, original tx hash %s, tx hash %s", tx.originalTransactionHash, tx.hash))
+			// Ignore the results that only report back to the sender, they were not counted in their source shard
+			if result := ClassifySCR(tx); !result.holdsFinalization() {
+				p.logIfVerbose(fmt.Sprintf("\t| Not decrementing counter for cross-shard SCR, original tx hash %s, tx hash %s since it is a %s with code %q\n", tx.originalTransactionHash, tx.hash, result.kind, result.code))
 
 				continue
 			}
 
-			// Ignore the transaction if data is '@ok'
-			if tx.DataEquals("@6f6b") {
-				p.logIfVerbose(fmt.Sprintf("\t| Not decrementing counter for cross-shard SCR, original tx hash %s, tx hash %s since the data is @ok (@6f6b)\n", tx.originalTransactionHash, tx.hash))
+			counter, crossShardTransaction, err := p.internalState.crossShardStore.AddToCounter(tx.originalTransactionHash, -1)
+			if errors.Is(err, ErrCrossShardTransactionNotTracked) {
+				p.logIfVerbose(fmt.Sprintf("\t| No counter available for cross-shard SCR, original tx hash %s, tx hash %s", tx.originalTransactionHash, tx.hash))
 
 				continue
 			}
 
-			cst.DecrementCounter()
+			if err != nil {
+				return nil, err
+			}
 
-			p.logIfVerbose(fmt.Sprintf("\t  Finalized cross-shard SCR for original tx hash %s, tx hash %s, counter = %d\n", tx.originalTransactionHash, tx.hash, cst.counter))
+			p.logIfVerbose(fmt.Sprintf("\t  Finalized cross-shard SCR for original tx hash %s, tx hash %s, counter = %d\n", tx.originalTransactionHash, tx.hash, counter))
 
-			p.internalState.crossShardDictionary.Set(tx.originalTransactionHash, cst)
+			if crossShardTransaction != nil {
+				completed = append(completed, crossShardTransaction)
+			}
+		}
+	}
+
+	for _, hash := range withoutPendingResults {
+		_, crossShardTransaction, err := p.internalState.crossShardStore.AddToCounter(hash, 0)
+		if err != nil && !errors.Is(err, ErrCrossShardTransactionNotTracked) {
+			return nil, err
+		}
+
+		if crossShardTransaction != nil {
+			completed = append(completed, crossShardTransaction)
 		}
 	}
 
 	/*
-		Step 3. If the counter reaches zero, we remove the value from the cross shard dictionary
+		Step 4. The completed transactions are delivered with their result tree
 	*/
-	for hash, crossShardTransaction := range p.internalState.crossShardDictionary {
-		if crossShardTransaction.CounterIsZero() {
-			p.logIfVerbose(fmt.Sprintf("\t| Completed cross-shard transaction for original tx hash %s", hash))
-
-			// We only add the transaction to the finalized transactions if it isn't already in the list of completed transactions
-			tx := transactions.FindByHash(hash)
-			if tx != nil {
-				finalizedTransactions = append(finalizedTransactions, tx)
-			}
-
-			p.internalState.DeleteCrossShardTransaction(hash)
+	for _, crossShardTransaction := range completed {
+		hash := crossShardTransaction.transaction.hash
+		p.logIfVerbose(fmt.Sprintf("\t| Completed cross-shard transaction for original tx hash %s", hash))
+
+		// The transaction is delivered with the block completing it, from the store when it was seen in an earlier block
+		tx := transactions.FindByHash(hash)
+		if tx == nil {
+			tx = crossShardTransaction.Transaction()
 		}
+
+		tx.resultTree = NewResultTree(tx, crossShardTransaction.Results())
+		finalizedTransactions = append(finalizedTransactions, tx)
+
+		p.emitCompletionEvent(tx, header)
 	}
 
-	return finalizedTransactions
+	return finalizedTransactions, nil
 }
 
 func (p *Processor) end() {
-	p.stateStorage.PersistLastState(p.shards, p.internalState)
+	if err := p.flushSinks(); err != nil {
+		log.Printf("not persisting last state of processor: %s\n", err)
+	} else if err := p.persistLastState(); err != nil {
+		log.Printf("could not persist last state of processor: %s\n", err)
+	}
 
 	if p.progressBar != nil {
 		_ = p.progressBar.Finish()
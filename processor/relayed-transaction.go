@@ -0,0 +1,157 @@
+package processor
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"strings"
+
+	"github.com/thefabric-io/elrond-transaction-processor/bech32"
+)
+
+// RelayedVersion is the version of the protocol of a relayed transaction, whose fees are paid by a relayer on behalf of
+// the sender of an inner transaction.
+type RelayedVersion int
+
+const (
+	NotRelayed RelayedVersion = iota
+	RelayedV1
+	RelayedV2
+)
+
+const (
+	relayedV1Prefix = "relayedTx@"
+	relayedV2Prefix = "relayedTxV2@"
+	addressHRP      = "erd"
+	publicKeyLength = 32
+)
+
+// relayedV1InnerTransaction is the JSON encoding of the inner transaction of a relayed v1 transaction, in which
+// addresses are base64 encoded public keys.
+type relayedV1InnerTransaction struct {
+	Nonce    uint64      `json:"nonce"`
+	Value    json.Number `json:"value"`
+	Receiver []byte      `json:"receiver"`
+	Sender   []byte      `json:"sender"`
+	GasPrice uint64      `json:"gasPrice"`
+	GasLimit uint64      `json:"gasLimit"`
+	Data     []byte      `json:"data"`
+}
+
+// unwrapRelayed decodes the inner transaction of a relayed transaction. The inner transaction shares the hash, status
+// and shards of the relayed one.
+//
+// Relayed v1 data is "relayedTx@<hex encoded JSON transaction>". Relayed v2 data is
+// "relayedTxV2@<receiver>@<nonce>@<data>@<signature>" in hex, the inner sender being the receiver of the relayed
+// transaction and the inner value being zero.
+func (t *Transaction) unwrapRelayed() {
+	t.relayedVersion, t.innerTransaction = NotRelayed, nil
+
+	data, err := base64.StdEncoding.DecodeString(t.data)
+	if err != nil {
+		return
+	}
+
+	switch s := string(data); {
+	case strings.HasPrefix(s, relayedV1Prefix):
+		t.innerTransaction = t.unwrapRelayedV1(strings.Split(s[len(relayedV1Prefix):], "@"))
+		if t.innerTransaction != nil {
+			t.relayedVersion = RelayedV1
+		}
+	case strings.HasPrefix(s, relayedV2Prefix):
+		t.innerTransaction = t.unwrapRelayedV2(strings.Split(s[len(relayedV2Prefix):], "@"))
+		if t.innerTransaction != nil {
+			t.relayedVersion = RelayedV2
+		}
+	}
+}
+
+func (t *Transaction) unwrapRelayedV1(arguments []string) *Transaction {
+	if len(arguments) != 1 {
+		return nil
+	}
+
+	encoded, err := hex.DecodeString(arguments[0])
+	if err != nil {
+		return nil
+	}
+
+	inner := relayedV1InnerTransaction{}
+	if err := json.Unmarshal(encoded, &inner); err != nil {
+		return nil
+	}
+
+	sender, receiver := encodePublicKey(inner.Sender), encodePublicKey(inner.Receiver)
+	if sender == "" || receiver == "" {
+		return nil
+	}
+
+	value := inner.Value.String()
+	if value == "" {
+		value = "0"
+	}
+
+	return t.newInnerTransaction(Nonce(inner.Nonce), sender, receiver, value, inner.Data, int(inner.GasPrice), int(inner.GasLimit))
+}
+
+func (t *Transaction) unwrapRelayedV2(arguments []string) *Transaction {
+	if len(arguments) != 4 {
+		return nil
+	}
+
+	receiverKey, err := hex.DecodeString(arguments[0])
+	if err != nil {
+		return nil
+	}
+
+	receiver := encodePublicKey(receiverKey)
+	if receiver == "" {
+		return nil
+	}
+
+	nonce := new(big.Int)
+	if arguments[1] != "" {
+		if _, ok := nonce.SetString(arguments[1], 16); !ok {
+			return nil
+		}
+	}
+
+	data, err := hex.DecodeString(arguments[2])
+	if err != nil {
+		return nil
+	}
+
+	return t.newInnerTransaction(Nonce(nonce.Int64()), t.receiver, receiver, "0", data, t.gasPrice, t.gasLimit)
+}
+
+// encodePublicKey returns the address of a public key, or an empty address when the key is not a public key.
+func encodePublicKey(key []byte) string {
+	if len(key) != publicKeyLength {
+		return ""
+	}
+
+	address, err := bech32.Encode(addressHRP, key)
+	if err != nil {
+		return ""
+	}
+
+	return address
+}
+
+func (t *Transaction) newInnerTransaction(nonce Nonce, sender, receiver, value string, data []byte, gasPrice, gasLimit int) *Transaction {
+	return &Transaction{
+		kind:             t.kind,
+		hash:             t.hash,
+		nonce:            nonce,
+		value:            value,
+		sender:           sender,
+		receiver:         receiver,
+		data:             base64.StdEncoding.EncodeToString(data),
+		status:           t.status,
+		sourceShard:      t.sourceShard,
+		destinationShard: t.destinationShard,
+		gasPrice:         gasPrice,
+		gasLimit:         gasLimit,
+	}
+}
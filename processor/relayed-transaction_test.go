@@ -0,0 +1,158 @@
+package processor
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/thefabric-io/elrond-transaction-processor/bech32"
+)
+
+func TestUnwrapRelayed(t *testing.T) {
+	relayer, err := bech32.Encode(addressHRP, bytes.Repeat([]byte{0x42}, 32))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, callerKey, err := bech32.Decode(testCaller)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, pairKey, err := bech32.Decode(testPairContract)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	/* The inner transaction of a relayed v1 transaction is JSON, with base64 encoded public keys and data */
+	inner, err := json.Marshal(map[string]interface{}{
+		"nonce":    uint64(3521),
+		"value":    json.Number("0"),
+		"receiver": pairKey,
+		"sender":   callerKey,
+		"gasPrice": 1000000000,
+		"gasLimit": 25000000,
+		"data":     []byte(testSwapData),
+		"chainID":  base64.StdEncoding.EncodeToString([]byte("1")),
+		"version":  1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	invalid, err := json.Marshal(map[string]interface{}{"nonce": 1, "value": "0", "receiver": pairKey, "sender": callerKey[:20]})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		receiver string
+		data     string
+		version  RelayedVersion
+		nonce    Nonce
+		sender   string
+		inner    string
+		value    string
+		function string
+		gasLimit int
+	}{
+		{
+			name:     "relayed v1",
+			receiver: testCaller,
+			data:     "relayedTx@" + hex.EncodeToString(inner),
+			version:  RelayedV1,
+			nonce:    3521,
+			sender:   testCaller,
+			inner:    testPairContract,
+			value:    "0",
+			function: "ESDTTransfer",
+			gasLimit: 25000000,
+		},
+		{
+			name:     "relayed v2",
+			receiver: testCaller,
+			data:     "relayedTxV2@" + hex.EncodeToString(pairKey) + "@0dc1@" + hex.EncodeToString([]byte(testSwapData)) + "@" + hex.EncodeToString(bytes.Repeat([]byte{0x07}, 64)),
+			version:  RelayedV2,
+			nonce:    3521,
+			sender:   testCaller,
+			inner:    testPairContract,
+			value:    "0",
+			function: "ESDTTransfer",
+			gasLimit: 30000000,
+		},
+		{
+			name:     "relayed v2 with a zero nonce",
+			receiver: testCaller,
+			data:     "relayedTxV2@" + hex.EncodeToString(pairKey) + "@@" + hex.EncodeToString([]byte("claimRewards")) + "@" + hex.EncodeToString(bytes.Repeat([]byte{0x07}, 64)),
+			version:  RelayedV2,
+			sender:   testCaller,
+			inner:    testPairContract,
+			value:    "0",
+			function: "claimRewards",
+			gasLimit: 30000000,
+		},
+		{
+			name:     "relayed v1 not hex encoded",
+			receiver: testCaller,
+			data:     "relayedTx@" + string(inner),
+		},
+		{
+			name:     "relayed v1 from an invalid address",
+			receiver: testCaller,
+			data:     "relayedTx@" + hex.EncodeToString(invalid),
+		},
+		{
+			name:     "relayed v2 without signature",
+			receiver: testCaller,
+			data:     "relayedTxV2@" + hex.EncodeToString(pairKey) + "@0dc1@" + hex.EncodeToString([]byte(testSwapData)),
+		},
+		{
+			name:     "relayed v2 to an invalid address",
+			receiver: testCaller,
+			data:     "relayedTxV2@0102@0dc1@" + hex.EncodeToString([]byte(testSwapData)) + "@00",
+		},
+		{
+			name:     "not relayed",
+			receiver: testPairContract,
+			data:     testSwapData,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tx := newTestCall("relayed", relayer, tt.receiver, "0", tt.data)
+
+			if tx.RelayedVersion() != tt.version || tx.IsRelayed() != (tt.version != NotRelayed) {
+				t.Fatalf("expected the version %d, got %d", tt.version, tx.RelayedVersion())
+			}
+
+			inner := tx.InnerTransaction()
+			if tt.version == NotRelayed {
+				if inner != nil {
+					t.Fatalf("expected no inner transaction, got %+v", inner)
+				}
+
+				return
+			}
+
+			if inner.Hash() != tx.Hash() || inner.Status() != tx.Status() {
+				t.Fatalf("expected the inner transaction to share the hash and status of %s, got %s %s", tx.Hash(), inner.Hash(), inner.Status())
+			}
+
+			if inner.Nonce() != tt.nonce || inner.Sender() != tt.sender || inner.Receiver() != tt.inner || inner.Value() != tt.value {
+				t.Fatalf("expected %d %s -> %s %s, got %d %s -> %s %s", tt.nonce, tt.sender, tt.inner, tt.value, inner.Nonce(), inner.Sender(), inner.Receiver(), inner.Value())
+			}
+
+			if inner.Function() != tt.function || inner.GasPrice() != 1000000000 || inner.GasLimit() != tt.gasLimit {
+				t.Fatalf("expected %s with %d gas, got %s with %d gas at %d", tt.function, tt.gasLimit, inner.Function(), inner.GasLimit(), inner.GasPrice())
+			}
+
+			if !inner.Involves(testCaller) || !tx.Involves(relayer) {
+				t.Fatal("expected the relayed transaction to involve the relayer and the inner sender")
+			}
+		})
+	}
+}
@@ -7,7 +7,10 @@ type StateStorage interface {
 
 type DataSource interface {
 	GetShards() ([]Shard, error)
+	GetNetworkConfig() (*NetworkConfig, error)
+	GetNetworkStatus(shard Shard) (*NetworkStatus, error)
 	GetCurrentNonceForShard(shard Shard) (Nonce, error)
 	GetCurrentNoncesForShards([]Shard) (NonceByShard, error)
-	GetShardTransactions(shard Shard, nonce Nonce) (string, []*Transaction, error)
+	GetShardTransactions(shard Shard, nonce Nonce) (*BlockHeader, []*Transaction, error)
+	GetBlockHeader(shard Shard, nonce Nonce) (*BlockHeader, error)
 }
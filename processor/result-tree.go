@@ -0,0 +1,204 @@
+package processor
+
+import (
+	"encoding/json"
+)
+
+// Outcome is the overall result of a transaction and of the smart contract results it triggered.
+type Outcome string
+
+const (
+	OutcomeSuccess    Outcome = "success"
+	OutcomeUserError  Outcome = "user-error"
+	OutcomeOutOfGas   Outcome = "out-of-gas"
+	OutcomeOutOfFunds Outcome = "out-of-funds"
+	OutcomeFailed     Outcome = "failed"
+)
+
+// NewResultTree links the smart contract results of the original transaction by their previous transaction hash. A
+// result whose previous transaction is unknown is attached to the root.
+func NewResultTree(original *Transaction, results Transactions) *ResultTree {
+	root := &ResultNode{transaction: original}
+
+	nodes := map[string]*ResultNode{original.hash: root}
+	for _, r := range results {
+		if _, found := nodes[r.hash]; !found {
+			nodes[r.hash] = &ResultNode{transaction: r}
+		}
+	}
+
+	for _, r := range results {
+		node := nodes[r.hash]
+		if node.linked || node == root {
+			continue
+		}
+
+		parent, found := nodes[r.previousTransactionHash]
+		if !found || parent == node || parent.isDescendantOf(node) {
+			parent = root
+		}
+
+		parent.children = append(parent.children, node)
+		node.parent = parent
+		node.linked = true
+	}
+
+	tree := &ResultTree{root: root}
+	tree.outcome, tree.failure = outcomeOf(root)
+
+	return tree
+}
+
+type ResultTree struct {
+	root    *ResultNode
+	outcome Outcome
+	failure *SCRResult
+}
+
+// Root returns the node of the original transaction.
+func (t *ResultTree) Root() *ResultNode {
+	return t.root
+}
+
+func (t *ResultTree) Outcome() Outcome {
+	return t.outcome
+}
+
+// Failure returns the first smart contract result reporting an error, parents first, or nil when there is none.
+func (t *ResultTree) Failure() *SCRResult {
+	return t.failure
+}
+
+// Results returns the smart contract results of the tree, parents first.
+func (t *ResultTree) Results() Transactions {
+	results := make(Transactions, 0)
+
+	t.root.walk(func(n *ResultNode) {
+		if n != t.root {
+			results = append(results, n.transaction)
+		}
+	})
+
+	return results
+}
+
+type ResultNode struct {
+	transaction *Transaction
+	parent      *ResultNode
+	children    []*ResultNode
+	linked      bool
+}
+
+func (n *ResultNode) Transaction() *Transaction {
+	return n.transaction
+}
+
+func (n *ResultNode) Children() []*ResultNode {
+	return n.children
+}
+
+func (n *ResultNode) isDescendantOf(ancestor *ResultNode) bool {
+	for p := n.parent; p != nil; p = p.parent {
+		if p == ancestor {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (n *ResultNode) walk(f func(n *ResultNode)) {
+	f(n)
+
+	for _, c := range n.children {
+		c.walk(f)
+	}
+}
+
+// outcomeOf returns the outcome of the first error found in the tree, parents first, or success when there is none.
+func outcomeOf(root *ResultNode) (Outcome, *SCRResult) {
+	var failure *SCRResult
+
+	root.walk(func(n *ResultNode) {
+		if failure != nil || n == root {
+			return
+		}
+
+		if r := ClassifySCR(n.transaction); r.IsError() {
+			failure = r
+		}
+	})
+
+	if failure != nil {
+		return failure.Outcome(), failure
+	}
+
+	if root.transaction.status == "fail" || root.transaction.status == "invalid" {
+		return OutcomeFailed, nil
+	}
+
+	return OutcomeSuccess, nil
+}
+
+type resultTreeJSON struct {
+	Outcome Outcome           `json:"outcome"`
+	Failure *scrResultJSON    `json:"failure,omitempty"`
+	Results []*resultNodeJSON `json:"results"`
+}
+
+type scrResultJSON struct {
+	Kind    SCRKind    `json:"kind"`
+	Code    ReturnCode `json:"code"`
+	Message string     `json:"message,omitempty"`
+}
+
+type resultNodeJSON struct {
+	Transaction *Transaction      `json:"transaction"`
+	Children    []*resultNodeJSON `json:"children,omitempty"`
+}
+
+// MarshalJSON encodes the outcome and the results of the tree, the original transaction being the one holding it.
+func (t *ResultTree) MarshalJSON() ([]byte, error) {
+	return json.Marshal(t.toJSON())
+}
+
+func (t *ResultTree) toJSON() *resultTreeJSON {
+	v := &resultTreeJSON{Outcome: t.outcome, Results: newResultNodesJSON(t.root.children)}
+	if t.failure != nil {
+		v.Failure = &scrResultJSON{Kind: t.failure.kind, Code: t.failure.code, Message: t.failure.message}
+	}
+
+	return v
+}
+
+func newResultNodesJSON(nodes []*ResultNode) []*resultNodeJSON {
+	v := make([]*resultNodeJSON, 0, len(nodes))
+	for _, n := range nodes {
+		v = append(v, &resultNodeJSON{Transaction: n.transaction, Children: newResultNodesJSON(n.children)})
+	}
+
+	return v
+}
+
+func restoreResultTree(original *Transaction, v *resultTreeJSON) *ResultTree {
+	root := &ResultNode{transaction: original}
+	root.children = restoreResultNodes(root, v.Results)
+
+	tree := &ResultTree{root: root, outcome: v.Outcome}
+	if v.Failure != nil {
+		tree.failure = &SCRResult{kind: v.Failure.Kind, code: v.Failure.Code, message: v.Failure.Message}
+	}
+
+	return tree
+}
+
+func restoreResultNodes(parent *ResultNode, v []*resultNodeJSON) []*ResultNode {
+	nodes := make([]*ResultNode, 0, len(v))
+	for _, n := range v {
+		node := &ResultNode{transaction: n.Transaction, parent: parent, linked: true}
+		node.children = restoreResultNodes(node, n.Children)
+		nodes = append(nodes, node)
+	}
+
+	return nodes
+}
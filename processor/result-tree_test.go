@@ -0,0 +1,150 @@
+package processor
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+// The samples follow the transactions of a swap on a pair contract of the mainnet: the caller sends WEGLD-bd4d79 with
+// swapTokensFixedInput, the pair sends USDC-c76f1f back and refunds the unused gas.
+const (
+	testCaller       = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+	testPairContract = "erd1qqqqqqqqqqqqqpgqeel2kumf0r8ffyhth7pqdujjat9nx0862jpsg2pqaq"
+	testESDTContract = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqzllls8a5w6u"
+	testSwapData     = "ESDTTransfer@5745474c442d626434643739@0de0b6b3a7640000@73776170546f6b656e734669786564496e707574@555344432d633736663166@01"
+)
+
+func newTestCall(hash, sender, receiver, value, data string) *Transaction {
+	return NewTransactionBuilder().Hash(hash).Sender(sender).Receiver(receiver).Value(value).Data(base64.StdEncoding.EncodeToString([]byte(data))).Status("success").GasPrice(1000000000).GasLimit(30000000).Build()
+}
+
+func newTestSCR(hash, previous, sender, receiver, value, data string) *Transaction {
+	return NewTransactionBuilder().Hash(hash).PreviousTransactionHash(previous).OriginalTransactionHash("swap").Sender(sender).Receiver(receiver).Value(value).Data(base64.StdEncoding.EncodeToString([]byte(data))).Kind(KindSmartContractResult).Build()
+}
+
+// shapeOf returns the hashes of the tree, each followed by its children in parentheses.
+func shapeOf(n *ResultNode) string {
+	if len(n.Children()) == 0 {
+		return n.Transaction().Hash()
+	}
+
+	children := make([]string, 0, len(n.Children()))
+	for _, c := range n.Children() {
+		children = append(children, shapeOf(c))
+	}
+
+	return n.Transaction().Hash() + "(" + strings.Join(children, " ") + ")"
+}
+
+func TestNewResultTree(t *testing.T) {
+	transfer := "ESDTTransfer@555344432d633736663166@0f4240"
+	userError := "@75736572206572726f72@536c697070616765206578636565646564"
+
+	tests := []struct {
+		name    string
+		status  string
+		results Transactions
+		shape   string
+		outcome Outcome
+		failure string
+	}{
+		{
+			name: "swap",
+			results: Transactions{
+				newTestSCR("transfer", "swap", testPairContract, testCaller, "0", transfer),
+				newTestSCR("refund", "swap", testPairContract, testCaller, "240000000000000", "@6f6b"),
+			},
+			shape:   "swap(transfer refund)",
+			outcome: OutcomeSuccess,
+		},
+		{
+			name: "results delivered before their parent",
+			results: Transactions{
+				newTestSCR("callback", "call", testESDTContract, testPairContract, "0", "@6f6b"),
+				newTestSCR("call", "swap", testPairContract, testESDTContract, "0", "ESDTLocalBurn@5745474c442d626434643739@01"),
+			},
+			shape:   "swap(call(callback))",
+			outcome: OutcomeSuccess,
+		},
+		{
+			name: "previous transaction unknown",
+			results: Transactions{
+				newTestSCR("transfer", "swap", testPairContract, testCaller, "0", transfer),
+				newTestSCR("orphan", "elsewhere", testPairContract, testCaller, "0", "@6f6b"),
+			},
+			shape:   "swap(transfer orphan)",
+			outcome: OutcomeSuccess,
+		},
+		{
+			name: "result delivered twice",
+			results: Transactions{
+				newTestSCR("transfer", "swap", testPairContract, testCaller, "0", transfer),
+				newTestSCR("transfer", "swap", testPairContract, testCaller, "0", transfer),
+			},
+			shape:   "swap(transfer)",
+			outcome: OutcomeSuccess,
+		},
+		{
+			name: "result of itself",
+			results: Transactions{
+				newTestSCR("loop", "loop", testPairContract, testCaller, "0", "@6f6b"),
+			},
+			shape:   "swap(loop)",
+			outcome: OutcomeSuccess,
+		},
+		{
+			name: "cycle falls back to the root",
+			results: Transactions{
+				newTestSCR("a", "b", testPairContract, testESDTContract, "0", "ESDTLocalBurn@5745474c442d626434643739@01"),
+				newTestSCR("b", "a", testESDTContract, testPairContract, "0", "@6f6b"),
+			},
+			shape:   "swap(b(a))",
+			outcome: OutcomeSuccess,
+		},
+		{
+			name: "error returned by a nested result",
+			results: Transactions{
+				newTestSCR("call", "swap", testPairContract, testESDTContract, "0", "ESDTLocalBurn@5745474c442d626434643739@01"),
+				newTestSCR("error", "call", testESDTContract, testCaller, "1000000000000000000", userError),
+			},
+			shape:   "swap(call(error))",
+			outcome: OutcomeUserError,
+			failure: "Slippage exceeded",
+		},
+		{
+			name:    "failed without results",
+			status:  "fail",
+			shape:   "swap",
+			outcome: OutcomeFailed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			original := newTestCall("swap", testCaller, testCaller, "0", testSwapData)
+			if tt.status != "" {
+				original.status = tt.status
+			}
+
+			tree := NewResultTree(original, tt.results)
+
+			if shape := shapeOf(tree.Root()); shape != tt.shape {
+				t.Fatalf("expected the tree %s, got %s", tt.shape, shape)
+			}
+
+			if tree.Outcome() != tt.outcome {
+				t.Fatalf("expected the outcome %s, got %s", tt.outcome, tree.Outcome())
+			}
+
+			failure := ""
+			if tree.Failure() != nil {
+				failure = tree.Failure().Message()
+			}
+
+			if failure != tt.failure {
+				t.Fatalf("expected the failure %q, got %q", tt.failure, failure)
+			}
+		})
+	}
+}
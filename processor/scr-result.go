@@ -0,0 +1,178 @@
+package processor
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+)
+
+// ReturnCode is the return code of a smart contract execution, as reported by its smart contract results.
+type ReturnCode string
+
+const (
+	ReturnCodeOk                     ReturnCode = "ok"
+	ReturnCodeFunctionNotFound       ReturnCode = "function not found"
+	ReturnCodeFunctionWrongSignature ReturnCode = "wrong signature for function"
+	ReturnCodeContractNotFound       ReturnCode = "contract not found"
+	ReturnCodeUserError              ReturnCode = "user error"
+	ReturnCodeOutOfGas               ReturnCode = "out of gas"
+	ReturnCodeAccountCollision       ReturnCode = "account collision"
+	ReturnCodeOutOfFunds             ReturnCode = "out of funds"
+	ReturnCodeCallStackOverflow      ReturnCode = "call stack overflow"
+	ReturnCodeContractInvalid        ReturnCode = "contract invalid"
+	ReturnCodeExecutionFailed        ReturnCode = "execution failed"
+	ReturnCodeUpgradeFailed          ReturnCode = "upgrade failed"
+	ReturnCodeSimulateFailed         ReturnCode = "simulate failed"
+)
+
+var knownReturnCodes = map[ReturnCode]bool{
+	ReturnCodeOk:                     true,
+	ReturnCodeFunctionNotFound:       true,
+	ReturnCodeFunctionWrongSignature: true,
+	ReturnCodeContractNotFound:       true,
+	ReturnCodeUserError:              true,
+	ReturnCodeOutOfGas:               true,
+	ReturnCodeAccountCollision:       true,
+	ReturnCodeOutOfFunds:             true,
+	ReturnCodeCallStackOverflow:      true,
+	ReturnCodeContractInvalid:        true,
+	ReturnCodeExecutionFailed:        true,
+	ReturnCodeUpgradeFailed:          true,
+	ReturnCodeSimulateFailed:         true,
+}
+
+// SCRKind is the role of a smart contract result in the execution of its original transaction.
+type SCRKind string
+
+const (
+	// SCRKindReturn carries the return code and data of an execution back to the caller.
+	SCRKindReturn SCRKind = "return"
+	// SCRKindCallback carries the return code and data of an asynchronous call back to the calling contract, which
+	// executes its callback.
+	SCRKindCallback SCRKind = "callback"
+	// SCRKindRefund gives the unused gas back to the sender.
+	SCRKindRefund SCRKind = "refund"
+	// SCRKindCall calls a function, or transfers tokens, on behalf of a contract.
+	SCRKindCall SCRKind = "call"
+	// SCRKindTransfer moves value without data.
+	SCRKindTransfer SCRKind = "transfer"
+)
+
+// smartContractAddressPrefix is shared by the addresses of contracts, whose public keys start with zero bytes.
+const smartContractAddressPrefix = "erd1qqqqqqqqqqqq"
+
+// SCRResult is the classification of a smart contract result, see ClassifySCR.
+type SCRResult struct {
+	kind      SCRKind
+	code      ReturnCode
+	message   string
+	arguments []string
+}
+
+// ClassifySCR reads the data of a smart contract result. Results starting with "@" carry a return code followed by
+// either the returned data, or the error message when the code is not ok. A successful return moving value without
+// returned data is a gas refund, and a return to a contract is a callback.
+func ClassifySCR(tx *Transaction) *SCRResult {
+	data, err := tx.B64DataDecoded()
+	if err != nil {
+		data = ""
+	}
+
+	if data == "" {
+		return &SCRResult{kind: SCRKindTransfer}
+	}
+
+	if !strings.HasPrefix(data, "@") {
+		return &SCRResult{kind: SCRKindCall, arguments: tx.Arguments()}
+	}
+
+	parts := strings.Split(data[1:], "@")
+
+	decoded, err := hex.DecodeString(parts[0])
+	if err != nil {
+		return &SCRResult{kind: SCRKindReturn, arguments: parts}
+	}
+
+	r := &SCRResult{kind: SCRKindReturn, code: ReturnCode(decoded), arguments: parts[1:]}
+
+	if r.IsError() && len(r.arguments) != 0 {
+		if message, err := hex.DecodeString(r.arguments[0]); err == nil {
+			r.message = string(message)
+			r.arguments = r.arguments[1:]
+		}
+	}
+
+	switch {
+	case isSmartContractAddress(tx.receiver):
+		r.kind = SCRKindCallback
+	case r.code == ReturnCodeOk && len(r.arguments) == 0 && hasValue(tx):
+		r.kind = SCRKindRefund
+	}
+
+	return r
+}
+
+func isSmartContractAddress(address string) bool {
+	return strings.HasPrefix(address, smartContractAddressPrefix)
+}
+
+func hasValue(tx *Transaction) bool {
+	v, ok := new(big.Int).SetString(tx.value, 10)
+
+	return ok && v.Sign() > 0
+}
+
+func (r *SCRResult) Kind() SCRKind {
+	return r.kind
+}
+
+// Code returns the return code carried by a return, callback or refund, and an empty code otherwise.
+func (r *SCRResult) Code() ReturnCode {
+	return r.code
+}
+
+// Message returns the error message following an error code.
+func (r *SCRResult) Message() string {
+	return r.message
+}
+
+// Arguments returns the hex encoded arguments following the return code or message, or the function of a call.
+func (r *SCRResult) Arguments() []string {
+	return r.arguments
+}
+
+func (r *SCRResult) IsError() bool {
+	return r.code != "" && r.code != ReturnCodeOk
+}
+
+// IsKnownCode reports whether the return code is one of the codes of the virtual machine.
+func (r *SCRResult) IsKnownCode() bool {
+	return knownReturnCodes[r.code]
+}
+
+// Outcome returns the outcome of a transaction whose first error is this result.
+func (r *SCRResult) Outcome() Outcome {
+	switch r.code {
+	case "", ReturnCodeOk:
+		return OutcomeSuccess
+	case ReturnCodeUserError:
+		return OutcomeUserError
+	case ReturnCodeOutOfGas:
+		return OutcomeOutOfGas
+	case ReturnCodeOutOfFunds:
+		return OutcomeOutOfFunds
+	default:
+		return OutcomeFailed
+	}
+}
+
+// holdsFinalization reports whether the original transaction must wait for this result to be executed in its
+// destination shard before being considered final. A plain successful return or a refund only reports back to the
+// sender, the execution it reports being already final.
+func (r *SCRResult) holdsFinalization() bool {
+	if r.kind == SCRKindRefund {
+		return false
+	}
+
+	return !(r.kind == SCRKindReturn && r.code == ReturnCodeOk && len(r.arguments) == 0)
+}
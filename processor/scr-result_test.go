@@ -0,0 +1,135 @@
+package processor
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestClassifySCR(t *testing.T) {
+	tests := []struct {
+		name      string
+		receiver  string
+		value     string
+		data      string
+		kind      SCRKind
+		code      ReturnCode
+		message   string
+		arguments []string
+		outcome   Outcome
+	}{
+		{
+			name:     "ok",
+			receiver: testCaller,
+			value:    "0",
+			data:     "@6f6b",
+			kind:     SCRKindReturn,
+			code:     ReturnCodeOk,
+			outcome:  OutcomeSuccess,
+		},
+		{
+			name:      "ok with returned data",
+			receiver:  testCaller,
+			value:     "0",
+			data:      "@6f6b@555344432d633736663166@0f4240",
+			kind:      SCRKindReturn,
+			code:      ReturnCodeOk,
+			arguments: []string{"555344432d633736663166", "0f4240"},
+			outcome:   OutcomeSuccess,
+		},
+		{
+			name:     "refund",
+			receiver: testCaller,
+			value:    "240000000000000",
+			data:     "@6f6b",
+			kind:     SCRKindRefund,
+			code:     ReturnCodeOk,
+			outcome:  OutcomeSuccess,
+		},
+		{
+			name:     "callback",
+			receiver: testPairContract,
+			value:    "0",
+			data:     "@6f6b",
+			kind:     SCRKindCallback,
+			code:     ReturnCodeOk,
+			outcome:  OutcomeSuccess,
+		},
+		{
+			name:     "user error",
+			receiver: testCaller,
+			value:    "1000000000000000000",
+			data:     "@75736572206572726f72@536c697070616765206578636565646564",
+			kind:     SCRKindReturn,
+			code:     ReturnCodeUserError,
+			message:  "Slippage exceeded",
+			outcome:  OutcomeUserError,
+		},
+		{
+			name:     "out of funds",
+			receiver: testCaller,
+			value:    "0",
+			data:     "@6f7574206f662066756e6473@696e73756666696369656e742066756e6473",
+			kind:     SCRKindReturn,
+			code:     ReturnCodeOutOfFunds,
+			message:  "insufficient funds",
+			outcome:  OutcomeOutOfFunds,
+		},
+		{
+			name:     "function not found",
+			receiver: testCaller,
+			value:    "0",
+			data:     "@66756e6374696f6e206e6f7420666f756e64@73776170546f6b656e734669786564496e707574",
+			kind:     SCRKindReturn,
+			code:     ReturnCodeFunctionNotFound,
+			message:  "swapTokensFixedInput",
+			outcome:  OutcomeFailed,
+		},
+		{
+			name:     "execution failed returned to a contract",
+			receiver: testPairContract,
+			value:    "0",
+			data:     "@657865637574696f6e206661696c6564",
+			kind:     SCRKindCallback,
+			code:     ReturnCodeExecutionFailed,
+			outcome:  OutcomeFailed,
+		},
+		{
+			name:      "call",
+			receiver:  testCaller,
+			value:     "0",
+			data:      "ESDTTransfer@555344432d633736663166@0f4240",
+			kind:      SCRKindCall,
+			arguments: []string{"555344432d633736663166", "0f4240"},
+			outcome:   OutcomeSuccess,
+		},
+		{
+			name:     "transfer",
+			receiver: testCaller,
+			value:    "1000000000000000000",
+			kind:     SCRKindTransfer,
+			outcome:  OutcomeSuccess,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := ClassifySCR(newTestSCR("scr", "swap", testPairContract, tt.receiver, tt.value, tt.data))
+
+			if r.Kind() != tt.kind || r.Code() != tt.code || r.Message() != tt.message {
+				t.Fatalf("expected %s %q %q, got %s %q %q", tt.kind, tt.code, tt.message, r.Kind(), r.Code(), r.Message())
+			}
+
+			if fmt.Sprint(r.Arguments()) != fmt.Sprint(tt.arguments) {
+				t.Fatalf("expected the arguments %v, got %v", tt.arguments, r.Arguments())
+			}
+
+			if r.Outcome() != tt.outcome {
+				t.Fatalf("expected the outcome %s, got %s", tt.outcome, r.Outcome())
+			}
+
+			if r.Code() != "" && !r.IsKnownCode() {
+				t.Fatalf("expected %q to be a known code", r.Code())
+			}
+		})
+	}
+}
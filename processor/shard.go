@@ -2,6 +2,7 @@ package processor
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 )
 
@@ -37,3 +38,55 @@ func (ss Shards) String() string {
 
 	return b.String()
 }
+
+func (ss Shards) Sort() {
+	sort.Slice(ss, func(i, j int) bool {
+		return ss[i] < ss[j]
+	})
+}
+
+func (ss Shards) Contains(s Shard) bool {
+	for _, shard := range ss {
+		if shard.Equals(s) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ss Shards) Intersect(other Shards) Shards {
+	result := make(Shards, 0, len(ss))
+	for _, shard := range ss {
+		if other.Contains(shard) {
+			result = append(result, shard)
+		}
+	}
+
+	return result
+}
+
+func (ss Shards) Difference(other Shards) Shards {
+	result := make(Shards, 0, len(ss))
+	for _, shard := range ss {
+		if !other.Contains(shard) {
+			result = append(result, shard)
+		}
+	}
+
+	return result
+}
+
+func (ss Shards) Equals(other Shards) bool {
+	if len(ss) != len(other) {
+		return false
+	}
+
+	for i := range ss {
+		if !ss[i].Equals(other[i]) {
+			return false
+		}
+	}
+
+	return true
+}
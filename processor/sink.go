@@ -0,0 +1,42 @@
+package processor
+
+import "fmt"
+
+// Sink receives every processed block. The state of a shard is only persisted once the sinks successfully flushed the
+// blocks sent to them, so that a block is never lost when delivery fails.
+type Sink interface {
+	Send(block *Block) error
+	Flush() error
+	Close() error
+}
+
+func (p *Processor) sendToSinks(block *Block) error {
+	for _, s := range p.sinks {
+		if err := s.Send(block); err != nil {
+			return fmt.Errorf("could not send block %d of %s to sink: %w", block.Nonce(), block.Shard().Name(), err)
+		}
+	}
+
+	return nil
+}
+
+func (p *Processor) flushSinks() error {
+	for _, s := range p.sinks {
+		if err := s.Flush(); err != nil {
+			return fmt.Errorf("could not flush sink: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// Close flushes and closes the sinks of the processor. It should be called on shutdown.
+func (p *Processor) Close() error {
+	for _, s := range p.sinks {
+		if err := s.Close(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
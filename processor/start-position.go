@@ -0,0 +1,60 @@
+package processor
+
+import (
+	"fmt"
+	"time"
+)
+
+func StartFromTimestamp(t time.Time) *StartPosition {
+	return &StartPosition{timestamp: t}
+}
+
+func StartFromEpoch(epoch int) *StartPosition {
+	return &StartPosition{epoch: epoch, byEpoch: true}
+}
+
+// StartPosition describes where the processor begins when no previous state can be found in the state storage.
+type StartPosition struct {
+	timestamp time.Time
+	epoch     int
+	byEpoch   bool
+}
+
+func (s *StartPosition) String() string {
+	if s.byEpoch {
+		return fmt.Sprintf("epoch %d", s.epoch)
+	}
+
+	return s.timestamp.Format(time.RFC3339)
+}
+
+// LastProcessedNonces resolves the position into the nonces that must be considered as already processed, so that
+// processing starts exactly at the first block matching the position in each shard.
+func (s *StartPosition) LastProcessedNonces(resolver *NonceResolver, shards Shards) (NonceByShard, error) {
+	var (
+		firstNonces NonceByShard
+		err         error
+	)
+
+	if s.byEpoch {
+		firstNonces, err = resolver.FirstNoncesAtEpoch(shards, s.epoch)
+	} else {
+		firstNonces, err = resolver.FirstNoncesAtTime(shards, s.timestamp)
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	for shard, nonce := range firstNonces {
+		if nonce == 0 {
+			firstNonces.PutNonce(shard, NoNonceProcessed)
+
+			continue
+		}
+
+		firstNonces.PutNonce(shard, nonce.Decrement())
+	}
+
+	return firstNonces, nil
+}
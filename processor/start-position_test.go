@@ -0,0 +1,61 @@
+package processor
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestStartPositionProcessesFromTheFirstMatchingBlock(t *testing.T) {
+	ds := newTestDataSource(Shards{0})
+	ds.setTip(3)
+
+	tests := []struct {
+		name     string
+		position *StartPosition
+		expected []Nonce
+	}{
+		{name: "genesis time", position: StartFromTimestamp(ds.start), expected: []Nonce{0, 1, 2, 3}},
+		{name: "first epoch", position: StartFromEpoch(0), expected: []Nonce{0, 1, 2, 3}},
+		{name: "later time", position: StartFromTimestamp(ds.start.Add(12 * time.Second)), expected: []Nonce{2, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var (
+				mu        sync.Mutex
+				processed = make([]Nonce, 0)
+			)
+
+			oo := Options{}
+			p, err := NewProcessor(
+				oo.DataSource(ds),
+				oo.StateStorage(NewInMemoryStateStorage()),
+				oo.StartFrom(tt.position),
+				oo.OnTransactionsReceived(func(_ Shard, nonce Nonce, _ []*Transaction, _ string) {
+					mu.Lock()
+					defer mu.Unlock()
+
+					processed = append(processed, nonce)
+				}),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if err := p.Start(); err != nil {
+				t.Fatal(err)
+			}
+
+			if len(processed) != len(tt.expected) {
+				t.Fatalf("expected nonces %v, got %v", tt.expected, processed)
+			}
+
+			for i := range tt.expected {
+				if processed[i] != tt.expected[i] {
+					t.Fatalf("expected nonces %v, got %v", tt.expected, processed)
+				}
+			}
+		})
+	}
+}
@@ -14,6 +14,12 @@ func (b *TransactionBuilder) NewTransaction() *TransactionBuilder {
 	return b
 }
 
+func (b *TransactionBuilder) Kind(k TransactionKind) *TransactionBuilder {
+	b.shardTransaction.kind = k
+
+	return b
+}
+
 func (b *TransactionBuilder) Value(v string) *TransactionBuilder {
 	b.shardTransaction.value = v
 
@@ -88,6 +94,23 @@ func (b *TransactionBuilder) GasLimit(g int) *TransactionBuilder {
 	return b
 }
 
+func (b *TransactionBuilder) Fee(f string) *TransactionBuilder {
+	b.shardTransaction.fee = f
+	return b
+}
+
+func (b *TransactionBuilder) GasUsed(g int) *TransactionBuilder {
+	b.shardTransaction.gasUsed = g
+	return b
+}
+
+func (b *TransactionBuilder) Logs(l *TransactionLog) *TransactionBuilder {
+	b.shardTransaction.logs = l
+	return b
+}
+
 func (b *TransactionBuilder) Build() *Transaction {
+	b.shardTransaction.unwrapRelayed()
+
 	return b.shardTransaction
 }
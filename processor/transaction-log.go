@@ -0,0 +1,133 @@
+package processor
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/thefabric-io/elrond-transaction-processor/abi"
+)
+
+// NewTransactionLog returns the log written by the execution of a transaction, as reported by the logs field of the
+// transaction/{hash} endpoint of the gateway.
+func NewTransactionLog(address string, events []*Event) *TransactionLog {
+	return &TransactionLog{address: address, events: events}
+}
+
+type TransactionLog struct {
+	address string
+	events  []*Event
+}
+
+func (l *TransactionLog) Address() string {
+	return l.address
+}
+
+func (l *TransactionLog) Events() []*Event {
+	return l.events
+}
+
+// FindEvents returns the events of the log with the given identifier, e.g. "ESDTLocalBurn" or "swapTokensFixedInput".
+func (l *TransactionLog) FindEvents(identifier string) []*Event {
+	events := make([]*Event, 0)
+	for _, e := range l.events {
+		if e.identifier == identifier {
+			events = append(events, e)
+		}
+	}
+
+	return events
+}
+
+// NewEvent returns an event emitted by the contract of the given address, with its topics and data base64 encoded like
+// in the responses of the gateway.
+func NewEvent(address, identifier string, topics []string, data string) *Event {
+	return &Event{address: address, identifier: identifier, topics: topics, data: data}
+}
+
+type Event struct {
+	address    string
+	identifier string
+	topics     []string
+	data       string
+	decoded    *abi.Event
+}
+
+func (e *Event) Address() string {
+	return e.address
+}
+
+func (e *Event) Identifier() string {
+	return e.identifier
+}
+
+// Topics returns the base64 encoded topics of the event.
+func (e *Event) Topics() []string {
+	return e.topics
+}
+
+// Data returns the base64 encoded data of the event.
+func (e *Event) Data() string {
+	return e.data
+}
+
+// Decoded returns the named fields of the event when the ABI of the contract which emitted it was registered with the
+// processor, see ContractABI. It is nil otherwise.
+func (e *Event) Decoded() *abi.Event {
+	return e.decoded
+}
+
+// TopicsDecoded returns the raw bytes of the topics of the event.
+func (e *Event) TopicsDecoded() ([][]byte, error) {
+	topics := make([][]byte, len(e.topics))
+	for i, t := range e.topics {
+		topic, err := base64.StdEncoding.DecodeString(t)
+		if err != nil {
+			return nil, err
+		}
+
+		topics[i] = topic
+	}
+
+	return topics, nil
+}
+
+func (e *Event) DataDecoded() ([]byte, error) {
+	return base64.StdEncoding.DecodeString(e.data)
+}
+
+type transactionLogJSON struct {
+	Address string       `json:"address"`
+	Events  []*eventJSON `json:"events"`
+}
+
+type eventJSON struct {
+	Address    string     `json:"address"`
+	Identifier string     `json:"identifier"`
+	Topics     []string   `json:"topics,omitempty"`
+	Data       string     `json:"data,omitempty"`
+	Decoded    *abi.Event `json:"decoded,omitempty"`
+}
+
+func (l *TransactionLog) MarshalJSON() ([]byte, error) {
+	v := transactionLogJSON{Address: l.address, Events: make([]*eventJSON, len(l.events))}
+	for i, e := range l.events {
+		v.Events[i] = &eventJSON{Address: e.address, Identifier: e.identifier, Topics: e.topics, Data: e.data, Decoded: e.decoded}
+	}
+
+	return json.Marshal(v)
+}
+
+func (l *TransactionLog) UnmarshalJSON(data []byte) error {
+	v := transactionLogJSON{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*l = TransactionLog{address: v.Address, events: make([]*Event, len(v.Events))}
+	for i, e := range v.Events {
+		l.events[i] = NewEvent(e.Address, e.Identifier, e.Topics, e.Data)
+		l.events[i].decoded = e.Decoded
+	}
+
+	return nil
+}
@@ -2,10 +2,25 @@ package processor
 
 import (
 	"encoding/base64"
+	"encoding/json"
 	"log"
+	"strings"
+
+	"github.com/thefabric-io/elrond-transaction-processor/abi"
+)
+
+const (
+	KindNormal              TransactionKind = "normal"
+	KindSmartContractResult TransactionKind = "unsigned"
+	KindReward              TransactionKind = "reward"
+	KindInvalid             TransactionKind = "invalid"
 )
 
+// TransactionKind is the type of a transaction as reported by the ledger.
+type TransactionKind string
+
 type Transaction struct {
+	kind                    TransactionKind
 	value                   string
 	data                    string
 	hash                    string
@@ -19,12 +34,143 @@ type Transaction struct {
 	originalTransactionHash string
 	gasPrice                int
 	gasLimit                int
+	fee                     string
+	gasUsed                 int
+	resultTree              *ResultTree
+	relayedVersion          RelayedVersion
+	innerTransaction        *Transaction
+	logs                    *TransactionLog
+	decodedCall             *abi.Call
 }
 
 func (t *Transaction) Sender() string {
 	return t.sender
 }
 
+func (t *Transaction) Kind() TransactionKind {
+	return t.kind
+}
+
+func (t *Transaction) Value() string {
+	return t.value
+}
+
+func (t *Transaction) Data() string {
+	return t.data
+}
+
+func (t *Transaction) Hash() string {
+	return t.hash
+}
+
+func (t *Transaction) Receiver() string {
+	return t.receiver
+}
+
+func (t *Transaction) Status() string {
+	return t.status
+}
+
+func (t *Transaction) SourceShard() Shard {
+	return t.sourceShard
+}
+
+func (t *Transaction) DestinationShard() Shard {
+	return t.destinationShard
+}
+
+func (t *Transaction) Nonce() Nonce {
+	return t.nonce
+}
+
+func (t *Transaction) PreviousTransactionHash() string {
+	return t.previousTransactionHash
+}
+
+func (t *Transaction) OriginalTransactionHash() string {
+	return t.originalTransactionHash
+}
+
+func (t *Transaction) GasPrice() int {
+	return t.gasPrice
+}
+
+func (t *Transaction) GasLimit() int {
+	return t.gasLimit
+}
+
+// RelayedVersion returns the version of a relayed transaction, or NotRelayed.
+func (t *Transaction) RelayedVersion() RelayedVersion {
+	return t.relayedVersion
+}
+
+func (t *Transaction) IsRelayed() bool {
+	return t.relayedVersion != NotRelayed
+}
+
+// InnerTransaction returns the transaction wrapped by a relayed transaction, or nil. The sender of the relayed
+// transaction is then the relayer.
+func (t *Transaction) InnerTransaction() *Transaction {
+	return t.innerTransaction
+}
+
+// Involves reports whether the address is the sender or the receiver of the transaction or of its inner transaction.
+func (t *Transaction) Involves(address string) bool {
+	if t.sender == address || t.receiver == address {
+		return true
+	}
+
+	return t.innerTransaction != nil && t.innerTransaction.Involves(address)
+}
+
+// ResultTree returns the smart contract results of a cross-shard transaction delivered once they are all finalized, or
+// nil for any other transaction.
+func (t *Transaction) ResultTree() *ResultTree {
+	return t.resultTree
+}
+
+// Fee returns the fee paid by the sender in the smallest denomination, computed when the processor is given a
+// FeeConfig. It is empty otherwise.
+func (t *Transaction) Fee() string {
+	return t.fee
+}
+
+// GasUsed returns the gas used by the transaction, computed along with its fee.
+func (t *Transaction) GasUsed() int {
+	return t.gasUsed
+}
+
+// Logs returns the log written by the transaction and its smart contract results, fetched when the processor enriches
+// the transaction with a LogSource. It is nil otherwise.
+func (t *Transaction) Logs() *TransactionLog {
+	return t.logs
+}
+
+// DecodedCall returns the endpoint called by the transaction and its named arguments when the ABI of the contract was
+// registered with the processor, see ContractABI. It is nil otherwise.
+func (t *Transaction) DecodedCall() *abi.Call {
+	return t.decodedCall
+}
+
+// DecodedArgument returns the text form of the decoded argument of the given name, see abi.FormatValue.
+func (t *Transaction) DecodedArgument(name string) (string, bool) {
+	if t.decodedCall == nil {
+		return "", false
+	}
+
+	return t.decodedCall.Arguments.GetString(name)
+}
+
+// CallsFunction reports whether the transaction calls the function, either directly or along with a token transfer
+// when its call was decoded.
+func (t *Transaction) CallsFunction(function string) bool {
+	if t.decodedCall != nil && t.decodedCall.Endpoint == function {
+		return true
+	}
+
+	return t.Function() == function
+}
+
 func (t *Transaction) HasOriginalTransactionHash() bool {
 	return len(t.originalTransactionHash) != 0
 }
@@ -60,6 +206,32 @@ func (t *Transaction) B64DataDecoded() (string, error) {
 	return "", nil
 }
 
+// Function returns the function called by the transaction, that is the part of its decoded data before the first "@".
+// It is empty for plain transfers and for smart contract results, whose data starts with "@".
+func (t *Transaction) Function() string {
+	data, err := t.B64DataDecoded()
+	if err != nil {
+		return ""
+	}
+
+	return strings.SplitN(data, "@", 2)[0]
+}
+
+// Arguments returns the hex encoded arguments following the function in the decoded data of the transaction.
+func (t *Transaction) Arguments() []string {
+	data, err := t.B64DataDecoded()
+	if err != nil {
+		return nil
+	}
+
+	parts := strings.Split(data, "@")
+	if len(parts) < 2 {
+		return nil
+	}
+
+	return parts[1:]
+}
+
 func (t *Transaction) DataEquals(d string) bool {
 	data, err := t.B64DataDecoded()
 	if err != nil {
@@ -69,6 +241,97 @@ func (t *Transaction) DataEquals(d string) bool {
 	return data == d
 }
 
+type transactionJSON struct {
+	Kind                    TransactionKind `json:"kind,omitempty"`
+	Hash                    string          `json:"hash"`
+	Nonce                   Nonce           `json:"nonce"`
+	Value                   string          `json:"value"`
+	Sender                  string          `json:"sender"`
+	Receiver                string          `json:"receiver"`
+	Data                    string          `json:"data,omitempty"`
+	Status                  string          `json:"status"`
+	SourceShard             Shard           `json:"sourceShard"`
+	DestinationShard        Shard           `json:"destinationShard"`
+	PreviousTransactionHash string          `json:"previousTransactionHash,omitempty"`
+	OriginalTransactionHash string          `json:"originalTransactionHash,omitempty"`
+	GasPrice                int             `json:"gasPrice"`
+	GasLimit                int             `json:"gasLimit"`
+	Fee                     string          `json:"fee,omitempty"`
+	GasUsed                 int             `json:"gasUsed,omitempty"`
+	ResultTree              *resultTreeJSON `json:"resultTree,omitempty"`
+	RelayedVersion          RelayedVersion  `json:"relayedVersion,omitempty"`
+	InnerTransaction        *Transaction    `json:"innerTransaction,omitempty"`
+	Logs                    *TransactionLog `json:"logs,omitempty"`
+	DecodedCall             *abi.Call       `json:"decodedCall,omitempty"`
+}
+
+func (t *Transaction) MarshalJSON() ([]byte, error) {
+	var resultTree *resultTreeJSON
+	if t.resultTree != nil {
+		resultTree = t.resultTree.toJSON()
+	}
+
+	return json.Marshal(transactionJSON{
+		Kind:                    t.kind,
+		Hash:                    t.hash,
+		Nonce:                   t.nonce,
+		Value:                   t.value,
+		Sender:                  t.sender,
+		Receiver:                t.receiver,
+		Data:                    t.data,
+		Status:                  t.status,
+		SourceShard:             t.sourceShard,
+		DestinationShard:        t.destinationShard,
+		PreviousTransactionHash: t.previousTransactionHash,
+		OriginalTransactionHash: t.originalTransactionHash,
+		GasPrice:                t.gasPrice,
+		GasLimit:                t.gasLimit,
+		Fee:                     t.fee,
+		GasUsed:                 t.gasUsed,
+		ResultTree:              resultTree,
+		RelayedVersion:          t.relayedVersion,
+		InnerTransaction:        t.innerTransaction,
+		Logs:                    t.logs,
+		DecodedCall:             t.decodedCall,
+	})
+}
+
+func (t *Transaction) UnmarshalJSON(data []byte) error {
+	v := transactionJSON{}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
+	}
+
+	*t = Transaction{
+		kind:                    v.Kind,
+		hash:                    v.Hash,
+		nonce:                   v.Nonce,
+		value:                   v.Value,
+		sender:                  v.Sender,
+		receiver:                v.Receiver,
+		data:                    v.Data,
+		status:                  v.Status,
+		sourceShard:             v.SourceShard,
+		destinationShard:        v.DestinationShard,
+		previousTransactionHash: v.PreviousTransactionHash,
+		originalTransactionHash: v.OriginalTransactionHash,
+		gasPrice:                v.GasPrice,
+		gasLimit:                v.GasLimit,
+		fee:                     v.Fee,
+		gasUsed:                 v.GasUsed,
+		logs:                    v.Logs,
+		decodedCall:             v.DecodedCall,
+	}
+
+	if v.ResultTree != nil {
+		t.resultTree = restoreResultTree(t, v.ResultTree)
+	}
+
+	t.unwrapRelayed()
+
+	return nil
+}
+
 type Transactions []*Transaction
 
 func (tt Transactions) FindByHash(h string) *Transaction {
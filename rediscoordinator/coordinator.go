@@ -0,0 +1,94 @@
+package rediscoordinator
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+// A lease is stored as a hash holding its owner and fencing token, expiring with the lease.
+// The fencing token of a key is kept in a separate counter that never expires, so that it keeps increasing across
+// owners.
+var acquireScript = redis.NewScript(`
+local owner = redis.call('HGET', KEYS[1], 'owner')
+if owner and owner ~= ARGV[1] then
+	return -1
+end
+if owner == ARGV[1] then
+	redis.call('PEXPIRE', KEYS[1], ARGV[2])
+	return tonumber(redis.call('HGET', KEYS[1], 'token'))
+end
+local token = redis.call('INCR', KEYS[2])
+redis.call('HSET', KEYS[1], 'owner', ARGV[1], 'token', token)
+redis.call('PEXPIRE', KEYS[1], ARGV[2])
+return token
+`)
+
+var renewScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') == ARGV[1] and redis.call('HGET', KEYS[1], 'token') == ARGV[2] then
+	return redis.call('PEXPIRE', KEYS[1], ARGV[3])
+end
+return 0
+`)
+
+var releaseScript = redis.NewScript(`
+if redis.call('HGET', KEYS[1], 'owner') == ARGV[1] and redis.call('HGET', KEYS[1], 'token') == ARGV[2] then
+	return redis.call('DEL', KEYS[1])
+end
+return 0
+`)
+
+// NewCoordinator returns a processor.Coordinator storing leases in redis under "<keyPrefix>lease:<key>".
+func NewCoordinator(client *redis.Client, keyPrefix string) *Coordinator {
+	return &Coordinator{client: client, keyPrefix: keyPrefix}
+}
+
+type Coordinator struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+func (c *Coordinator) Acquire(key, owner string, ttl time.Duration) (*processor.Lease, error) {
+	now := time.Now()
+
+	token, err := acquireScript.Run(context.Background(), c.client, []string{c.leaseKey(key), c.tokenKey(key)}, owner, ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	if token < 0 {
+		return nil, processor.ErrLeaseNotAcquired
+	}
+
+	return processor.NewLease(key, owner, processor.FencingToken(token), now.Add(ttl)), nil
+}
+
+func (c *Coordinator) Renew(lease *processor.Lease, ttl time.Duration) (*processor.Lease, error) {
+	now := time.Now()
+
+	renewed, err := renewScript.Run(context.Background(), c.client, []string{c.leaseKey(lease.Key())}, lease.Owner(), int64(lease.Token()), ttl.Milliseconds()).Int64()
+	if err != nil {
+		return nil, err
+	}
+
+	if renewed == 0 {
+		return nil, processor.ErrLeaseLost
+	}
+
+	return processor.NewLease(lease.Key(), lease.Owner(), lease.Token(), now.Add(ttl)), nil
+}
+
+func (c *Coordinator) Release(lease *processor.Lease) error {
+	return releaseScript.Run(context.Background(), c.client, []string{c.leaseKey(lease.Key())}, lease.Owner(), int64(lease.Token())).Err()
+}
+
+func (c *Coordinator) leaseKey(key string) string {
+	return fmt.Sprintf("%slease:%s", c.keyPrefix, key)
+}
+
+func (c *Coordinator) tokenKey(key string) string {
+	return fmt.Sprintf("%slease-token:%s", c.keyPrefix, key)
+}
@@ -0,0 +1,302 @@
+package rediscoordinator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const (
+	resultFieldPrefix = "result:"
+	transactionField  = "transaction"
+)
+
+// addResultScript only stores the result of a transaction that is still tracked.
+var addResultScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return 0
+end
+
+return redis.call('HSET', KEYS[1], ARGV[1], ARGV[2])
+`)
+
+// untrackSnippet stops tracking the transaction of KEYS[1] whose hash is ARGV[1], and keeps its fields in a local. The
+// indexes of the shard of the transaction are only known once the transaction is read, their keys are built from the
+// prefixes ARGV[2] for the nonces and ARGV[3] for the creation times.
+const untrackSnippet = `
+local fields = redis.call('HGETALL', KEYS[1])
+local shard = redis.call('HGET', KEYS[1], 'shard')
+redis.call('DEL', KEYS[1])
+redis.call('SREM', KEYS[2], ARGV[1])
+if shard then
+	redis.call('ZREM', ARGV[2] .. shard, ARGV[1])
+	redis.call('ZREM', ARGV[3] .. shard, ARGV[1])
+end
+`
+
+// addToCounterScript only updates the counter of a transaction that is still tracked, and stops tracking it in the
+// same step when the counter reaches zero. It returns nothing for an untracked transaction, the new counter otherwise,
+// followed by the fields of the transaction when it completed.
+var addToCounterScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+
+local counter = redis.call('HINCRBY', KEYS[1], 'counter', ARGV[4])
+if counter > 0 then
+	return {counter}
+end
+` + untrackSnippet + `
+return {counter, fields}
+`)
+
+// deleteScript stops tracking a transaction and returns its fields, or nothing when it is not tracked.
+var deleteScript = redis.NewScript(`
+if redis.call('EXISTS', KEYS[1]) == 0 then
+	return false
+end
+` + untrackSnippet + `
+return fields
+`)
+
+// NewCrossShardStore returns a processor.CrossShardStore shared by every worker of a cluster. Each pending transaction
+// is a hash holding the transaction, its counter, creation block and smart contract results. The set of pending
+// hashes is kept alongside, with the hashes of each shard sorted by creation time and by creation nonce so that the
+// expired transactions are read without going through all of them.
+func NewCrossShardStore(client *redis.Client, keyPrefix string) *CrossShardStore {
+	return &CrossShardStore{client: client, keyPrefix: keyPrefix}
+}
+
+type CrossShardStore struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+func (s *CrossShardStore) FindTransaction(h string) (*processor.CrossShardTransaction, error) {
+	values, err := s.client.HGetAll(context.Background(), s.transactionKey(h)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(values) == 0 {
+		return nil, nil
+	}
+
+	return restoreTransaction(h, values)
+}
+
+func restoreTransaction(h string, values map[string]string) (*processor.CrossShardTransaction, error) {
+	counter, err := strconv.Atoi(values["counter"])
+	if err != nil {
+		return nil, fmt.Errorf("invalid counter for cross-shard transaction %s: %w", h, err)
+	}
+
+	created, err := strconv.ParseInt(values["created"], 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid creation time for cross-shard transaction %s: %w", h, err)
+	}
+
+	// shard, nonce and the transaction itself are missing from the transactions tracked by earlier versions
+	shard, _ := strconv.Atoi(values["shard"])
+	nonce, _ := strconv.Atoi(values["nonce"])
+
+	tx := processor.NewTransactionBuilder().Hash(h).Build()
+	if value, found := values[transactionField]; found {
+		if err := json.Unmarshal([]byte(value), tx); err != nil {
+			return nil, fmt.Errorf("invalid cross-shard transaction %s: %w", h, err)
+		}
+	}
+
+	t := processor.RestoreCrossShardTransaction(tx, counter, time.Unix(created, 0), processor.Shard(shard), processor.Nonce(nonce))
+
+	resultFields := make([]string, 0)
+	for field := range values {
+		if strings.HasPrefix(field, resultFieldPrefix) {
+			resultFields = append(resultFields, field)
+		}
+	}
+
+	sort.Strings(resultFields)
+
+	for _, field := range resultFields {
+		result := &processor.Transaction{}
+		if err := json.Unmarshal([]byte(values[field]), result); err != nil {
+			return nil, fmt.Errorf("invalid result %s for cross-shard transaction %s: %w", field, h, err)
+		}
+
+		t.AddResult(result)
+	}
+
+	return t, nil
+}
+
+func (s *CrossShardStore) Set(h string, t *processor.CrossShardTransaction) error {
+	transaction, err := json.Marshal(t.Transaction())
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		pipe.HSet(context.Background(), s.transactionKey(h), "counter", t.Counter(), "created", t.Created().Unix(), "shard", int(t.Shard()), "nonce", int(t.Nonce()), transactionField, transaction)
+		pipe.SAdd(context.Background(), s.hashesKey(), h)
+		pipe.ZAdd(context.Background(), s.byTimeKey(t.Shard()), &redis.Z{Score: float64(t.Created().Unix()), Member: h})
+		pipe.ZAdd(context.Background(), s.byNonceKey(t.Shard()), &redis.Z{Score: float64(t.Nonce()), Member: h})
+
+		return nil
+	})
+
+	return err
+}
+
+func (s *CrossShardStore) Delete(h string) (*processor.CrossShardTransaction, error) {
+	reply, err := deleteScript.Run(context.Background(), s.client, s.scriptKeys(h), h, s.byNonceKeyPrefix(), s.byTimeKeyPrefix()).Slice()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	return restoreTransaction(h, fieldValues(reply))
+}
+
+func (s *CrossShardStore) Expired(shards processor.Shards, createdBefore time.Time, noncesBefore processor.NonceByShard) ([]string, error) {
+	cmds := make([]*redis.StringSliceCmd, 0, 2*len(shards))
+
+	_, err := s.client.Pipelined(context.Background(), func(pipe redis.Pipeliner) error {
+		for _, shard := range shards {
+			if !createdBefore.IsZero() {
+				cmds = append(cmds, pipe.ZRangeByScore(context.Background(), s.byTimeKey(shard), &redis.ZRangeBy{Min: "-inf", Max: scoreBelow(float64(createdBefore.UnixNano()) / float64(time.Second))}))
+			}
+
+			nonce, found := noncesBefore.Nonce(shard)
+			if !found {
+				continue
+			}
+
+			cmds = append(cmds, pipe.ZRangeByScore(context.Background(), s.byNonceKey(shard), &redis.ZRangeBy{Min: "-inf", Max: scoreBelow(float64(nonce))}))
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	hashes := make([]string, 0)
+	for _, cmd := range cmds {
+		for _, h := range cmd.Val() {
+			if !seen[h] {
+				seen[h] = true
+				hashes = append(hashes, h)
+			}
+		}
+	}
+
+	return hashes, nil
+}
+
+// scoreBelow is the exclusive upper bound of a range of scores.
+func scoreBelow(score float64) string {
+	return "(" + strconv.FormatFloat(score, 'f', -1, 64)
+}
+
+func (s *CrossShardStore) Keys() ([]string, error) {
+	hashes, err := s.client.SMembers(context.Background(), s.hashesKey()).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	return hashes, err
+}
+
+func (s *CrossShardStore) AddToCounter(h string, delta int) (int, *processor.CrossShardTransaction, error) {
+	reply, err := addToCounterScript.Run(context.Background(), s.client, s.scriptKeys(h), h, s.byNonceKeyPrefix(), s.byTimeKeyPrefix(), delta).Slice()
+	if errors.Is(err, redis.Nil) {
+		return 0, nil, processor.ErrCrossShardTransactionNotTracked
+	}
+
+	if err != nil {
+		return 0, nil, err
+	}
+
+	counter, ok := reply[0].(int64)
+	if !ok {
+		return 0, nil, fmt.Errorf("invalid counter for cross-shard transaction %s: %v", h, reply[0])
+	}
+
+	if len(reply) == 1 {
+		return int(counter), nil, nil
+	}
+
+	fields, ok := reply[1].([]interface{})
+	if !ok {
+		return 0, nil, fmt.Errorf("invalid fields for cross-shard transaction %s", h)
+	}
+
+	t, err := restoreTransaction(h, fieldValues(fields))
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return int(counter), t, nil
+}
+
+// fieldValues returns the fields of a hash read by HGETALL in a script.
+func fieldValues(fields []interface{}) map[string]string {
+	values := make(map[string]string, len(fields)/2)
+	for i := 0; i+1 < len(fields); i += 2 {
+		field, _ := fields[i].(string)
+		value, _ := fields[i+1].(string)
+		values[field] = value
+	}
+
+	return values
+}
+
+func (s *CrossShardStore) AddResult(h string, result *processor.Transaction) error {
+	value, err := json.Marshal(result)
+	if err != nil {
+		return err
+	}
+
+	return addResultScript.Run(context.Background(), s.client, []string{s.transactionKey(h)}, resultFieldPrefix+result.Hash(), value).Err()
+}
+
+func (s *CrossShardStore) transactionKey(h string) string {
+	return fmt.Sprintf("%scross-shard:%s", s.keyPrefix, h)
+}
+
+func (s *CrossShardStore) hashesKey() string {
+	return fmt.Sprintf("%scross-shard-hashes", s.keyPrefix)
+}
+
+func (s *CrossShardStore) byTimeKeyPrefix() string {
+	return fmt.Sprintf("%scross-shard-by-time:", s.keyPrefix)
+}
+
+func (s *CrossShardStore) byTimeKey(shard processor.Shard) string {
+	return fmt.Sprintf("%s%d", s.byTimeKeyPrefix(), shard)
+}
+
+func (s *CrossShardStore) byNonceKeyPrefix() string {
+	return fmt.Sprintf("%scross-shard-by-nonce:", s.keyPrefix)
+}
+
+func (s *CrossShardStore) byNonceKey(shard processor.Shard) string {
+	return fmt.Sprintf("%s%d", s.byNonceKeyPrefix(), shard)
+}
+
+func (s *CrossShardStore) scriptKeys(h string) []string {
+	return []string{s.transactionKey(h), s.hashesKey()}
+}
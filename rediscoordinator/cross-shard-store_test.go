@@ -0,0 +1,292 @@
+package rediscoordinator
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+func newTestClient(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+
+	m, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(m.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return client, m
+}
+
+func newTrackedTransaction(hash string, counter int) *processor.CrossShardTransaction {
+	tx := processor.NewTransactionBuilder().
+		Hash(hash).
+		Sender("erd1sender").
+		Receiver("erd1receiver").
+		Value("1000").
+		Data("ZGVsZWdhdGU=").
+		Status("success").
+		SourceShard(1).
+		DestinationShard(processor.ShardMetachain).
+		Build()
+
+	return processor.RestoreCrossShardTransaction(tx, counter, time.Unix(1600000000, 0), 1, 42)
+}
+
+func TestCrossShardStoreRestoresTheOriginalTransaction(t *testing.T) {
+	client, _ := newTestClient(t)
+	store := NewCrossShardStore(client, "test:")
+
+	if err := store.Set("tx", newTrackedTransaction("tx", 1)); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := store.FindTransaction("tx")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := found.Transaction()
+	if tx.Sender() != "erd1sender" || tx.Receiver() != "erd1receiver" || tx.Value() != "1000" || tx.Data() != "ZGVsZWdhdGU=" || tx.Status() != "success" {
+		t.Fatalf("transaction not restored: %+v", tx)
+	}
+
+	if found.Counter() != 1 || found.Shard() != 1 || found.Nonce() != 42 || !found.Created().Equal(time.Unix(1600000000, 0)) {
+		t.Fatalf("tracking not restored: counter %d, shard %d, nonce %d, created %s", found.Counter(), found.Shard(), found.Nonce(), found.Created())
+	}
+}
+
+func TestCrossShardStoreAddToCounter(t *testing.T) {
+	tests := []struct {
+		name          string
+		initial       int
+		track         bool
+		delta         int
+		wantCounter   int
+		wantCompleted bool
+		wantErr       error
+	}{
+		{name: "increment", initial: 1, track: true, delta: 1, wantCounter: 2},
+		{name: "decrement", initial: 2, track: true, delta: -1, wantCounter: 1},
+		{name: "decrement to zero completes", initial: 1, track: true, delta: -1, wantCounter: 0, wantCompleted: true},
+		{name: "zero delta on zero completes", initial: 0, track: true, delta: 0, wantCounter: 0, wantCompleted: true},
+		{name: "untracked increment", delta: 1, wantErr: processor.ErrCrossShardTransactionNotTracked},
+		{name: "untracked decrement", delta: -1, wantErr: processor.ErrCrossShardTransactionNotTracked},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, m := newTestClient(t)
+			store := NewCrossShardStore(client, "test:")
+
+			if tt.track {
+				if err := store.Set("tx", newTrackedTransaction("tx", tt.initial)); err != nil {
+					t.Fatal(err)
+				}
+
+				result := processor.NewTransactionBuilder().Hash("scr").OriginalTransactionHash("tx").Build()
+				if err := store.AddResult("tx", result); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			counter, completed, err := store.AddToCounter("tx", tt.delta)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("expected error %v, got %v", tt.wantErr, err)
+			}
+
+			if err != nil {
+				if m.Exists(store.transactionKey("tx")) {
+					t.Fatal("an untracked transaction was recreated")
+				}
+
+				return
+			}
+
+			if counter != tt.wantCounter {
+				t.Fatalf("expected counter %d, got %d", tt.wantCounter, counter)
+			}
+
+			if (completed != nil) != tt.wantCompleted {
+				t.Fatalf("expected completed %t, got %v", tt.wantCompleted, completed)
+			}
+
+			if !tt.wantCompleted {
+				return
+			}
+
+			if completed.Transaction().Sender() != "erd1sender" || len(completed.Results()) != 1 {
+				t.Fatalf("completed transaction not restored: %+v", completed)
+			}
+
+			keys, err := store.Keys()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if m.Exists(store.transactionKey("tx")) || len(keys) != 0 {
+				t.Fatal("a completed transaction is still tracked")
+			}
+		})
+	}
+}
+
+func TestCrossShardStoreCompletesOnceUnderConcurrency(t *testing.T) {
+	client, m := newTestClient(t)
+	store := NewCrossShardStore(client, "test:")
+
+	const (
+		transactions = 20
+		results      = 10
+	)
+
+	for i := 0; i < transactions; i++ {
+		if err := store.Set(fmt.Sprint("tx", i), newTrackedTransaction(fmt.Sprint("tx", i), results)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	var (
+		wg        sync.WaitGroup
+		mu        sync.Mutex
+		completed = map[string]int{}
+	)
+
+	/*
+		Each result is decremented by a different worker, and late decrements run after the completion of their
+		transaction, as when a result is delivered again
+	*/
+	for i := 0; i < transactions; i++ {
+		for r := 0; r < results+2; r++ {
+			wg.Add(1)
+
+			go func(hash string) {
+				defer wg.Done()
+
+				_, tx, err := store.AddToCounter(hash, -1)
+				if err != nil && !errors.Is(err, processor.ErrCrossShardTransactionNotTracked) {
+					t.Error(err)
+
+					return
+				}
+
+				if tx != nil {
+					mu.Lock()
+					completed[hash]++
+					mu.Unlock()
+				}
+			}(fmt.Sprint("tx", i))
+		}
+	}
+
+	wg.Wait()
+
+	for i := 0; i < transactions; i++ {
+		if n := completed[fmt.Sprint("tx", i)]; n != 1 {
+			t.Fatalf("tx%d completed %d times", i, n)
+		}
+	}
+
+	keys, err := store.Keys()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(keys) != 0 || len(m.Keys()) != 0 {
+		t.Fatalf("orphan keys left: %v %v", keys, m.Keys())
+	}
+}
+
+func TestCrossShardStoreExpired(t *testing.T) {
+	client, m := newTestClient(t)
+
+	stores := map[string]processor.CrossShardStore{
+		"in memory": processor.NewInMemoryCrossShardStore(),
+		"redis":     NewCrossShardStore(client, "test:"),
+	}
+
+	tracked := []struct {
+		hash    string
+		created int64
+		shard   processor.Shard
+		nonce   processor.Nonce
+	}{
+		{hash: "old", created: 1600000000, shard: 1, nonce: 42},
+		{hash: "recent", created: 1600000600, shard: 1, nonce: 100},
+		{hash: "other-shard", created: 1600000600, shard: 2, nonce: 10},
+	}
+
+	tests := []struct {
+		name          string
+		shards        processor.Shards
+		createdBefore time.Time
+		noncesBefore  processor.NonceByShard
+		expected      []string
+	}{
+		{name: "nothing expired", shards: processor.Shards{1, 2}, expected: []string{}},
+		{name: "created before", shards: processor.Shards{1, 2}, createdBefore: time.Unix(1600000300, 0), expected: []string{"old"}},
+		{name: "created at the limit", shards: processor.Shards{1, 2}, createdBefore: time.Unix(1600000000, 0), expected: []string{}},
+		{name: "created before in the shards of another worker", shards: processor.Shards{2}, createdBefore: time.Unix(1600000900, 0), expected: []string{"other-shard"}},
+		{name: "nonce below", shards: processor.Shards{1, 2}, noncesBefore: processor.NonceByShard{1: 100}, expected: []string{"old"}},
+		{name: "nonce below in another shard", shards: processor.Shards{1, 2}, noncesBefore: processor.NonceByShard{2: 11}, expected: []string{"other-shard"}},
+		{name: "nonce below in the shards of another worker", shards: processor.Shards{2}, noncesBefore: processor.NonceByShard{1: 1000}, expected: []string{}},
+		{name: "created before or nonce below", shards: processor.Shards{1, 2}, createdBefore: time.Unix(1600000300, 0), noncesBefore: processor.NonceByShard{1: 43, 2: 11}, expected: []string{"old", "other-shard"}},
+	}
+
+	for name, store := range stores {
+		for _, tr := range tracked {
+			tx := processor.NewTransactionBuilder().Hash(tr.hash).Build()
+			if err := store.Set(tr.hash, processor.RestoreCrossShardTransaction(tx, 1, time.Unix(tr.created, 0), tr.shard, tr.nonce)); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.name, func(t *testing.T) {
+				hashes, err := store.Expired(tt.shards, tt.createdBefore, tt.noncesBefore)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				sort.Strings(hashes)
+				if fmt.Sprint(hashes) != fmt.Sprint(tt.expected) {
+					t.Fatalf("expected %v, got %v", tt.expected, hashes)
+				}
+			})
+		}
+
+		/* A transaction is only returned by its first deletion, so that a single worker reports its timeout */
+		for _, tr := range tracked {
+			deleted, err := store.Delete(tr.hash)
+			if err != nil || deleted == nil || deleted.Nonce() != tr.nonce {
+				t.Fatalf("%s: expected to delete %s, got %v, %v", name, tr.hash, deleted, err)
+			}
+
+			if deleted, err := store.Delete(tr.hash); err != nil || deleted != nil {
+				t.Fatalf("%s: expected %s to be deleted once, got %v, %v", name, tr.hash, deleted, err)
+			}
+		}
+
+		hashes, err := store.Expired(processor.Shards{1, 2}, time.Unix(1700000000, 0), processor.NonceByShard{1: 1000, 2: 1000})
+		if err != nil || len(hashes) != 0 {
+			t.Fatalf("%s: expected the deleted transactions to leave the indexes, got %v, %v", name, hashes, err)
+		}
+	}
+
+	if len(m.Keys()) != 0 {
+		t.Fatalf("orphan keys left: %v", m.Keys())
+	}
+}
@@ -0,0 +1,51 @@
+package rediscoordinator
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// NewMembership returns a processor.Membership storing the members of each cluster in a sorted set scored by the
+// expiry time of their registration.
+func NewMembership(client *redis.Client, keyPrefix string) *Membership {
+	return &Membership{client: client, keyPrefix: keyPrefix}
+}
+
+type Membership struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+func (m *Membership) Join(clusterID, workerID string, ttl time.Duration) error {
+	expiresAt := time.Now().Add(ttl).UnixNano() / int64(time.Millisecond)
+
+	return m.client.ZAdd(context.Background(), m.key(clusterID), &redis.Z{Score: float64(expiresAt), Member: workerID}).Err()
+}
+
+func (m *Membership) Leave(clusterID, workerID string) error {
+	return m.client.ZRem(context.Background(), m.key(clusterID), workerID).Err()
+}
+
+func (m *Membership) Members(clusterID string) ([]string, error) {
+	ctx := context.Background()
+	now := strconv.FormatInt(time.Now().UnixNano()/int64(time.Millisecond), 10)
+
+	if err := m.client.ZRemRangeByScore(ctx, m.key(clusterID), "-inf", now).Err(); err != nil {
+		return nil, err
+	}
+
+	members, err := m.client.ZRange(ctx, m.key(clusterID), 0, -1).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	return members, nil
+}
+
+func (m *Membership) key(clusterID string) string {
+	return fmt.Sprintf("%smembers:%s", m.keyPrefix, clusterID)
+}
@@ -0,0 +1,177 @@
+package redisstate
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+// Writes are rejected when the fencing key of a shard already holds a higher token than the one of the writer. The
+// epoch progress of the shard is written along with its nonce, unless the shard made no progress yet.
+var persistFencedScript = redis.NewScript(`
+local current = tonumber(redis.call('GET', KEYS[2]) or '0')
+if current > tonumber(ARGV[2]) then
+	return 0
+end
+redis.call('SET', KEYS[2], ARGV[2])
+redis.call('SET', KEYS[1], ARGV[1])
+if ARGV[3] ~= '' then
+	redis.call('SET', KEYS[3], ARGV[3])
+end
+return 1
+`)
+
+// NewStateStorage persists the last processed nonce of each shard under "<keyPrefix><shard>", and the epoch progress
+// of each shard under "<keyPrefix>epochs:<shard>". Processors that must not disturb each other (e.g. a live
+// processor and a backfill job) should use different key prefixes.
+func NewStateStorage(client *redis.Client, keyPrefix string) *StateStorage {
+	return &StateStorage{client: client, keyPrefix: keyPrefix}
+}
+
+type StateStorage struct {
+	client    *redis.Client
+	keyPrefix string
+}
+
+func (p *StateStorage) FetchLastState(shards []processor.Shard) (*processor.State, error) {
+	var lpn = processor.NonceByShard{}
+
+	for _, shard := range shards {
+		cmd := p.client.Get(context.Background(), p.key(shard))
+		if errors.Is(cmd.Err(), redis.Nil) {
+			return nil, processor.ErrStateNotFound
+		}
+
+		nonce, err := strconv.Atoi(cmd.Val())
+		if err != nil {
+			return nil, err
+		}
+
+		lpn[shard] = processor.Nonce(nonce)
+	}
+
+	csDictionary := processor.NewCrossShardDictionary()
+
+	state := processor.NewState(csDictionary, lpn, nil)
+
+	epochProgress, err := p.FetchEpochProgress(shards)
+	if err != nil {
+		return nil, err
+	}
+
+	state.SetEpochProgress(epochProgress)
+	log.Printf("fetched last processed nonces: %v", state.LastProcessedNonces())
+
+	return state, nil
+}
+
+func (p *StateStorage) PersistLastState(shards processor.Shards, state *processor.State) error {
+	for _, shard := range shards {
+		nonce, _ := state.LastProcessedNonceInShard(shard)
+
+		epochProgress, err := p.marshalEpochProgress(shard, state)
+		if err != nil {
+			return err
+		}
+
+		_, err = p.client.TxPipelined(context.Background(), func(pipe redis.Pipeliner) error {
+			pipe.Set(context.Background(), p.key(shard), int(nonce), 0)
+			if epochProgress != "" {
+				pipe.Set(context.Background(), p.epochsKey(shard), epochProgress, 0)
+			}
+
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	log.Printf("persisted last processed nonces: %v", state.LastProcessedNonces())
+
+	return nil
+}
+
+// PersistLastStateFenced implements processor.FencedStateStorage so that an instance which lost its lease cannot
+// overwrite the progress of the instance that took over.
+func (p *StateStorage) PersistLastStateFenced(shards processor.Shards, state *processor.State, tokens map[processor.Shard]processor.FencingToken) error {
+	for _, shard := range shards {
+		nonce, _ := state.LastProcessedNonceInShard(shard)
+
+		epochProgress, err := p.marshalEpochProgress(shard, state)
+		if err != nil {
+			return err
+		}
+
+		keys := []string{p.key(shard), p.fencingKey(shard), p.epochsKey(shard)}
+		accepted, err := persistFencedScript.Run(context.Background(), p.client, keys, int(nonce), int64(tokens[shard]), epochProgress).Int()
+		if err != nil {
+			return err
+		}
+
+		if accepted == 0 {
+			return fmt.Errorf("%w: %s", processor.ErrStaleFencingToken, shard.Name())
+		}
+	}
+	log.Printf("persisted last processed nonces: %v", state.LastProcessedNonces())
+
+	return nil
+}
+
+// FetchEpochProgress implements processor.EpochProgressStorage so that the workers of a cluster see the epochs reached
+// by the shards of the other workers.
+func (p *StateStorage) FetchEpochProgress(shards processor.Shards) (*processor.EpochProgress, error) {
+	epochProgress := processor.NewEpochProgress()
+
+	for _, shard := range shards {
+		b, err := p.client.Get(context.Background(), p.epochsKey(shard)).Bytes()
+		if errors.Is(err, redis.Nil) {
+			continue
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		shardProgress := processor.NewEpochProgress()
+		if err := json.Unmarshal(b, shardProgress); err != nil {
+			return nil, err
+		}
+
+		epochProgress.Merge(shardProgress)
+	}
+
+	return epochProgress, nil
+}
+
+// marshalEpochProgress returns the epoch progress of the shard, empty when the shard made no progress.
+func (p *StateStorage) marshalEpochProgress(shard processor.Shard, state *processor.State) (string, error) {
+	shardProgress := state.EpochProgress().Shard(shard)
+	if shardProgress == nil {
+		return "", nil
+	}
+
+	b, err := json.Marshal(shardProgress)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
+
+func (p *StateStorage) key(shard processor.Shard) string {
+	return fmt.Sprintf("%s%d", p.keyPrefix, shard)
+}
+
+func (p *StateStorage) fencingKey(shard processor.Shard) string {
+	return fmt.Sprintf("%sfencing:%d", p.keyPrefix, shard)
+}
+
+func (p *StateStorage) epochsKey(shard processor.Shard) string {
+	return fmt.Sprintf("%sepochs:%d", p.keyPrefix, shard)
+}
@@ -0,0 +1,87 @@
+package redisstate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+func newTestStorage(t *testing.T) *StateStorage {
+	t.Helper()
+
+	m, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(m.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return NewStateStorage(client, "test:")
+}
+
+// newTestState returns the state of a processor of the shard, in the epoch.
+func newTestState(t *testing.T, shard processor.Shard, nonce processor.Nonce, epoch int) *processor.State {
+	t.Helper()
+
+	progress := processor.NewEpochProgress()
+	content := fmt.Sprintf(`{"shards":{"%d":{"epoch":%d,"lastNonce":%d}}}`, shard, epoch, nonce)
+	if err := json.Unmarshal([]byte(content), progress); err != nil {
+		t.Fatal(err)
+	}
+
+	state := processor.NewState(processor.NewCrossShardDictionary(), processor.NonceByShard{shard: nonce}, nil)
+	state.SetEpochProgress(progress)
+
+	return state
+}
+
+func TestStateStorageKeepsTheEpochProgressOfEachShard(t *testing.T) {
+	s := newTestStorage(t)
+
+	/* Two workers of a cluster persist the shard they process */
+	if err := s.PersistLastStateFenced(processor.Shards{0}, newTestState(t, 0, 5, 2), map[processor.Shard]processor.FencingToken{0: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := s.PersistLastStateFenced(processor.Shards{1}, newTestState(t, 1, 7, 3), map[processor.Shard]processor.FencingToken{1: 1}); err != nil {
+		t.Fatal(err)
+	}
+
+	/* A worker whose lease on shard 0 was taken over cannot overwrite the progress of the shard */
+	err := s.PersistLastStateFenced(processor.Shards{0}, newTestState(t, 0, 9, 4), map[processor.Shard]processor.FencingToken{0: 0})
+	if !errors.Is(err, processor.ErrStaleFencingToken) {
+		t.Fatalf("expected %v, got %v", processor.ErrStaleFencingToken, err)
+	}
+
+	progress, err := s.FetchEpochProgress(processor.Shards{0, 1, 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		shard processor.Shard
+		epoch int
+		found bool
+	}{
+		{shard: 0, epoch: 2, found: true},
+		{shard: 1, epoch: 3, found: true},
+		{shard: 2, found: false},
+	}
+
+	for _, tt := range tests {
+		epoch, found := progress.CurrentEpoch(tt.shard)
+		if found != tt.found || epoch != tt.epoch {
+			t.Errorf("expected epoch %d (found: %t) for %s, got %d (found: %t)", tt.epoch, tt.found, tt.shard.Name(), epoch, found)
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package redisstream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+var (
+	ErrFieldNotFound = errors.New("field not found in stream entry")
+)
+
+type MessageHandler func(message redis.XMessage) error
+
+// NewConsumer reads a stream as the member consumer of a consumer group.
+func NewConsumer(client *redis.Client, stream, group, consumer string) *Consumer {
+	return &Consumer{client: client, stream: stream, group: group, consumer: consumer}
+}
+
+type Consumer struct {
+	client   *redis.Client
+	stream   string
+	group    string
+	consumer string
+}
+
+// EnsureGroup creates the consumer group, and the stream if needed, starting at start ("0" for the whole stream, "$"
+// for new entries only). It does nothing when the group already exists.
+func (c *Consumer) EnsureGroup(start string) error {
+	err := c.client.XGroupCreateMkStream(context.Background(), c.stream, c.group, start).Err()
+	if err != nil && strings.HasPrefix(err.Error(), "BUSYGROUP") {
+		return nil
+	}
+
+	return err
+}
+
+// Read returns up to count new entries, waiting at most block for them to arrive.
+func (c *Consumer) Read(count int64, block time.Duration) ([]redis.XMessage, error) {
+	streams, err := c.client.XReadGroup(context.Background(), &redis.XReadGroupArgs{
+		Group:    c.group,
+		Consumer: c.consumer,
+		Streams:  []string{c.stream, ">"},
+		Count:    count,
+		Block:    block,
+	}).Result()
+	if errors.Is(err, redis.Nil) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, err
+	}
+
+	messages := make([]redis.XMessage, 0)
+	for _, stream := range streams {
+		messages = append(messages, stream.Messages...)
+	}
+
+	return messages, nil
+}
+
+func (c *Consumer) Ack(ids ...string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	return c.client.XAck(context.Background(), c.stream, c.group, ids...).Err()
+}
+
+// ReclaimPending takes over up to count entries delivered to any consumer of the group and not acknowledged for at
+// least minIdle, e.g. because their consumer crashed.
+func (c *Consumer) ReclaimPending(minIdle time.Duration, count int64) ([]redis.XMessage, error) {
+	messages, _, err := c.client.XAutoClaim(context.Background(), &redis.XAutoClaimArgs{
+		Stream:   c.stream,
+		Group:    c.group,
+		Consumer: c.consumer,
+		MinIdle:  minIdle,
+		Start:    "0-0",
+		Count:    count,
+	}).Result()
+
+	return messages, err
+}
+
+// Consume reclaims idle pending entries then reads new ones until stop is closed. Entries are acknowledged once
+// handler succeeds, and are left pending otherwise so that they are delivered again after minIdle.
+func (c *Consumer) Consume(handler MessageHandler, minIdle time.Duration, stop <-chan struct{}) error {
+	for {
+		select {
+		case <-stop:
+			return nil
+		default:
+		}
+
+		reclaimed, err := c.ReclaimPending(minIdle, 100)
+		if err != nil {
+			return err
+		}
+
+		messages, err := c.Read(100, time.Second)
+		if err != nil {
+			return err
+		}
+
+		for _, message := range append(reclaimed, messages...) {
+			if err := handler(message); err != nil {
+				log.Printf("could not handle entry %s of %s: %s\n", message.ID, c.stream, err)
+
+				continue
+			}
+
+			if err := c.Ack(message.ID); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// DecodeBlock decodes an entry added by EncodeBlock.
+func DecodeBlock(message redis.XMessage) (*processor.Block, error) {
+	block := &processor.Block{}
+	if err := decodeField(message, FieldBlock, block); err != nil {
+		return nil, err
+	}
+
+	return block, nil
+}
+
+// DecodeTransaction decodes an entry added by EncodeTransaction.
+func DecodeTransaction(message redis.XMessage) (*processor.Transaction, error) {
+	tx := &processor.Transaction{}
+	if err := decodeField(message, FieldTransaction, tx); err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
+func decodeField(message redis.XMessage, field string, v interface{}) error {
+	value, found := message.Values[field]
+	if !found {
+		return fmt.Errorf("%w: %s", ErrFieldNotFound, field)
+	}
+
+	s, ok := value.(string)
+	if !ok {
+		return fmt.Errorf("unexpected type %T for field %s", value, field)
+	}
+
+	return json.Unmarshal([]byte(s), v)
+}
@@ -0,0 +1,51 @@
+package redisstream
+
+import "time"
+
+const (
+	FieldShard       = "shard"
+	FieldNonce       = "nonce"
+	FieldHash        = "hash"
+	FieldBlockHash   = "blockHash"
+	FieldSender      = "sender"
+	FieldReceiver    = "receiver"
+	FieldBlock       = "block"
+	FieldTransaction = "transaction"
+)
+
+type Option func(*Writer)
+
+type Options struct{}
+
+// PerTransaction adds one entry per transaction instead of one per block. Empty blocks add no entry.
+func (oo *Options) PerTransaction() Option {
+	return func(w *Writer) {
+		w.mode = PerTransaction
+	}
+}
+
+// MaxLen trims the stream to approximately n entries on each addition.
+func (oo *Options) MaxLen(n int64) Option {
+	return func(w *Writer) {
+		w.maxLen = n
+	}
+}
+
+// DeduplicationTTL sets how long the hash of an added block is kept to skip it when it is replayed.
+func (oo *Options) DeduplicationTTL(d time.Duration) Option {
+	return func(w *Writer) {
+		w.deduplicationTTL = d
+	}
+}
+
+func (oo *Options) BlockEncoder(e BlockEncoder) Option {
+	return func(w *Writer) {
+		w.blockEncoder = e
+	}
+}
+
+func (oo *Options) TransactionEncoder(e TransactionEncoder) Option {
+	return func(w *Writer) {
+		w.transactionEncoder = e
+	}
+}
@@ -0,0 +1,184 @@
+package redisstream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	"github.com/thefabric-io/elrond-transaction-processor/sink"
+)
+
+const (
+	PerBlock EntryMode = iota
+	PerTransaction
+)
+
+const defaultDeduplicationTTL = 24 * time.Hour
+
+// EntryMode defines whether a stream entry holds a whole block or a single transaction.
+type EntryMode int
+
+type BlockEncoder func(block *processor.Block) (map[string]interface{}, error)
+
+type TransactionEncoder func(block *processor.Block, tx *processor.Transaction) (map[string]interface{}, error)
+
+// The entries of a block are only added when the block has not been added before with the same hash, which happens
+// when the processor replays its past blocks buffer. The hash of every added block is kept for the deduplication TTL.
+//
+// KEYS[1]: stream, KEYS[2]: deduplication key of the block
+// ARGV: block hash, deduplication TTL in ms, MAXLEN (0 to disable), number of entries, then for each entry its
+// number of fields followed by its fields and values.
+var addBlockScript = redis.NewScript(`
+if redis.call('GET', KEYS[2]) == ARGV[1] then
+	return 0
+end
+local maxLen = tonumber(ARGV[3])
+local i = 5
+for e = 1, tonumber(ARGV[4]) do
+	local n = tonumber(ARGV[i])
+	local args = {'XADD', KEYS[1]}
+	if maxLen > 0 then
+		table.insert(args, 'MAXLEN')
+		table.insert(args, '~')
+		table.insert(args, maxLen)
+	end
+	table.insert(args, '*')
+	for f = 1, n * 2 do
+		table.insert(args, ARGV[i + f])
+	end
+	redis.call(unpack(args))
+	i = i + n * 2 + 1
+end
+redis.call('SET', KEYS[2], ARGV[1], 'PX', ARGV[2])
+return 1
+`)
+
+// NewSink returns a sink adding the processed blocks to a redis stream, see NewWriter.
+func NewSink(client *redis.Client, stream string, opts ...Option) *sink.Sink {
+	return sink.New(NewWriter(client, stream, opts...))
+}
+
+// NewWriter returns a sink.BatchWriter adding one entry per block (default) or per transaction to stream.
+func NewWriter(client *redis.Client, stream string, opts ...Option) *Writer {
+	w := &Writer{
+		client:             client,
+		stream:             stream,
+		mode:               PerBlock,
+		deduplicationTTL:   defaultDeduplicationTTL,
+		blockEncoder:       EncodeBlock,
+		transactionEncoder: EncodeTransaction,
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	return w
+}
+
+type Writer struct {
+	client             *redis.Client
+	stream             string
+	mode               EntryMode
+	maxLen             int64
+	deduplicationTTL   time.Duration
+	blockEncoder       BlockEncoder
+	transactionEncoder TransactionEncoder
+}
+
+func (w *Writer) WriteBatch(blocks []*processor.Block) error {
+	for _, block := range blocks {
+		if err := w.addBlock(block); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *Writer) Close() error {
+	return nil
+}
+
+func (w *Writer) addBlock(block *processor.Block) error {
+	entries, err := w.entries(block)
+	if err != nil {
+		return err
+	}
+
+	args := []interface{}{block.Hash(), w.deduplicationTTL.Milliseconds(), w.maxLen, len(entries)}
+	for _, entry := range entries {
+		args = append(args, len(entry))
+		for field, value := range entry {
+			args = append(args, field, value)
+		}
+	}
+
+	keys := []string{w.stream, w.deduplicationKey(block)}
+
+	return addBlockScript.Run(context.Background(), w.client, keys, args...).Err()
+}
+
+func (w *Writer) entries(block *processor.Block) ([]map[string]interface{}, error) {
+	if w.mode == PerBlock {
+		entry, err := w.blockEncoder(block)
+		if err != nil {
+			return nil, err
+		}
+
+		return []map[string]interface{}{entry}, nil
+	}
+
+	entries := make([]map[string]interface{}, 0, len(block.Transactions()))
+	for _, tx := range block.Transactions() {
+		entry, err := w.transactionEncoder(block, tx)
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, entry)
+	}
+
+	return entries, nil
+}
+
+func (w *Writer) deduplicationKey(block *processor.Block) string {
+	return fmt.Sprintf("%s:added:%d:%d", w.stream, block.Shard(), block.Nonce())
+}
+
+// EncodeBlock is the default BlockEncoder, adding the shard, nonce and hash of the block next to its JSON encoding.
+func EncodeBlock(block *processor.Block) (map[string]interface{}, error) {
+	b, err := json.Marshal(block)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		FieldShard: int(block.Shard()),
+		FieldNonce: int(block.Nonce()),
+		FieldHash:  block.Hash(),
+		FieldBlock: string(b),
+	}, nil
+}
+
+// EncodeTransaction is the default TransactionEncoder, adding the block coordinates and the parties of the
+// transaction next to its JSON encoding.
+func EncodeTransaction(block *processor.Block, tx *processor.Transaction) (map[string]interface{}, error) {
+	b, err := json.Marshal(tx)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		FieldShard:       int(block.Shard()),
+		FieldNonce:       int(block.Nonce()),
+		FieldBlockHash:   block.Hash(),
+		FieldHash:        tx.Hash(),
+		FieldSender:      tx.Sender(),
+		FieldReceiver:    tx.Receiver(),
+		FieldTransaction: string(b),
+	}, nil
+}
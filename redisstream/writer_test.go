@@ -0,0 +1,176 @@
+package redisstream
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+func newTestClient(t *testing.T) (*redis.Client, *miniredis.Miniredis) {
+	t.Helper()
+
+	m, err := miniredis.Run()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(m.Close)
+
+	client := redis.NewClient(&redis.Options{Addr: m.Addr()})
+	t.Cleanup(func() {
+		_ = client.Close()
+	})
+
+	return client, m
+}
+
+func newTestBlock(shard processor.Shard, nonce processor.Nonce, hash string, transactions int) *processor.Block {
+	header := processor.NewBlockHeaderBuilder().Shard(shard).Nonce(nonce).Hash(hash).Timestamp(time.Unix(1600000000, 0)).Build()
+
+	txs := make(processor.Transactions, 0, transactions)
+	for i := 0; i < transactions; i++ {
+		txs = append(txs, processor.NewTransactionBuilder().Hash(fmt.Sprintf("%s-tx-%d", hash, i)).Sender("erd1sender").Receiver("erd1receiver").Value("0").Status("success").Build())
+	}
+
+	return processor.NewBlock(header, txs)
+}
+
+func TestWriterDeduplicatesBlocks(t *testing.T) {
+	type write struct {
+		block *processor.Block
+		// after is the time elapsed before the block is written.
+		after time.Duration
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		writes   []write
+		expected []string
+	}{
+		{
+			name:     "block replayed",
+			writes:   []write{{block: newTestBlock(1, 10, "a", 1)}, {block: newTestBlock(1, 10, "a", 1)}},
+			expected: []string{"a"},
+		},
+		{
+			name:     "block replaced with the same shard and nonce",
+			writes:   []write{{block: newTestBlock(1, 10, "a", 1)}, {block: newTestBlock(1, 10, "b", 1)}},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "replaced block replayed",
+			writes:   []write{{block: newTestBlock(1, 10, "a", 1)}, {block: newTestBlock(1, 10, "b", 1)}, {block: newTestBlock(1, 10, "b", 1)}},
+			expected: []string{"a", "b"},
+		},
+		{
+			name:     "same nonce in another shard",
+			writes:   []write{{block: newTestBlock(1, 10, "a", 1)}, {block: newTestBlock(2, 10, "a", 1)}},
+			expected: []string{"a", "a"},
+		},
+		{
+			name:     "block replayed after the deduplication TTL",
+			opts:     []Option{(&Options{}).DeduplicationTTL(time.Minute)},
+			writes:   []write{{block: newTestBlock(1, 10, "a", 1)}, {block: newTestBlock(1, 10, "a", 1), after: 2 * time.Minute}},
+			expected: []string{"a", "a"},
+		},
+		{
+			name:     "block replayed per transaction",
+			opts:     []Option{(&Options{}).PerTransaction()},
+			writes:   []write{{block: newTestBlock(1, 10, "a", 2)}, {block: newTestBlock(1, 10, "a", 2)}},
+			expected: []string{"a", "a"},
+		},
+		{
+			name:     "empty block per transaction",
+			opts:     []Option{(&Options{}).PerTransaction()},
+			writes:   []write{{block: newTestBlock(1, 10, "a", 0)}, {block: newTestBlock(1, 11, "b", 1)}},
+			expected: []string{"b"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client, m := newTestClient(t)
+			w := NewWriter(client, "blocks", tt.opts...)
+
+			for _, write := range tt.writes {
+				m.FastForward(write.after)
+
+				if err := w.WriteBatch([]*processor.Block{write.block}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			messages, err := client.XRange(context.Background(), "blocks", "-", "+").Result()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			hashes := make([]string, 0, len(messages))
+			for _, message := range messages {
+				field := FieldHash
+				if _, found := message.Values[FieldBlockHash]; found {
+					field = FieldBlockHash
+				}
+
+				hashes = append(hashes, fmt.Sprint(message.Values[field]))
+			}
+
+			if fmt.Sprint(hashes) != fmt.Sprint(tt.expected) {
+				t.Fatalf("expected the entries of %v, got %v", tt.expected, hashes)
+			}
+		})
+	}
+}
+
+func TestWriterEntriesDecode(t *testing.T) {
+	client, _ := newTestClient(t)
+
+	if err := NewWriter(client, "blocks").WriteBatch([]*processor.Block{newTestBlock(1, 10, "a", 2)}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := NewWriter(client, "transactions", (&Options{}).PerTransaction()).WriteBatch([]*processor.Block{newTestBlock(1, 10, "a", 2)}); err != nil {
+		t.Fatal(err)
+	}
+
+	blocks, err := client.XRange(context.Background(), "blocks", "-", "+").Result()
+	if err != nil || len(blocks) != 1 {
+		t.Fatalf("expected a single block entry, got %v, %v", blocks, err)
+	}
+
+	block, err := DecodeBlock(blocks[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if block.Shard() != 1 || block.Nonce() != 10 || block.Hash() != "a" || len(block.Transactions()) != 2 {
+		t.Fatalf("block not decoded: %+v", block)
+	}
+
+	transactions, err := client.XRange(context.Background(), "transactions", "-", "+").Result()
+	if err != nil || len(transactions) != 2 {
+		t.Fatalf("expected two transaction entries, got %v, %v", transactions, err)
+	}
+
+	for i, message := range transactions {
+		tx, err := DecodeTransaction(message)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if tx.Hash() != fmt.Sprintf("a-tx-%d", i) || tx.Sender() != "erd1sender" {
+			t.Fatalf("transaction not decoded: %+v", tx)
+		}
+	}
+
+	if _, err := DecodeTransaction(blocks[0]); !errors.Is(err, ErrFieldNotFound) {
+		t.Fatalf("expected a block entry not to decode as a transaction, got %v", err)
+	}
+}
@@ -0,0 +1,99 @@
+package sink
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+// NewJSONLFileSink writes every block as a JSON line in files of dir named "<prefix>-<creation time>-<sequence>.jsonl".
+// A new file is started once the current one would exceed maxBytes, a single file is written when maxBytes is not
+// positive.
+func NewJSONLFileSink(dir, prefix string, maxBytes int64, opts ...Option) (*Sink, error) {
+	w, err := NewJSONLFileWriter(dir, prefix, maxBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	return New(w, opts...), nil
+}
+
+func NewJSONLFileWriter(dir, prefix string, maxBytes int64) (*JSONLFileWriter, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &JSONLFileWriter{dir: dir, prefix: prefix, maxBytes: maxBytes}, nil
+}
+
+type JSONLFileWriter struct {
+	dir      string
+	prefix   string
+	maxBytes int64
+	file     *os.File
+	written  int64
+	sequence int
+}
+
+func (w *JSONLFileWriter) WriteBatch(blocks []*processor.Block) error {
+	for _, block := range blocks {
+		line, err := json.Marshal(block)
+		if err != nil {
+			return err
+		}
+
+		line = append(line, '\n')
+
+		if w.file == nil || (w.maxBytes > 0 && w.written > 0 && w.written+int64(len(line)) > w.maxBytes) {
+			if err := w.rotate(); err != nil {
+				return err
+			}
+		}
+
+		n, err := w.file.Write(line)
+		w.written += int64(n)
+		if err != nil {
+			return err
+		}
+	}
+
+	if w.file == nil {
+		return nil
+	}
+
+	return w.file.Sync()
+}
+
+func (w *JSONLFileWriter) Close() error {
+	if w.file == nil {
+		return nil
+	}
+
+	err := w.file.Close()
+	w.file = nil
+
+	return err
+}
+
+func (w *JSONLFileWriter) rotate() error {
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	w.sequence++
+	name := fmt.Sprintf("%s-%s-%04d.jsonl", w.prefix, time.Now().UTC().Format("20060102T150405"), w.sequence)
+
+	file, err := os.OpenFile(filepath.Join(w.dir, name), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	w.file = file
+	w.written = 0
+
+	return nil
+}
@@ -0,0 +1,32 @@
+package sink
+
+import "time"
+
+type Option func(*Sink)
+
+type Options struct{}
+
+// BatchSize sets the number of blocks written at once.
+func (oo *Options) BatchSize(n int) Option {
+	return func(s *Sink) {
+		if n > 0 {
+			s.batchSize = n
+		}
+	}
+}
+
+// FlushInterval writes the pending blocks at the given interval, even if the batch is not full.
+func (oo *Options) FlushInterval(d time.Duration) Option {
+	return func(s *Sink) {
+		s.flushInterval = d
+	}
+}
+
+// Retries sets how many times a failed batch is written again, waiting backoff before the first retry and twice as
+// long before each of the next ones.
+func (oo *Options) Retries(max int, backoff time.Duration) Option {
+	return func(s *Sink) {
+		s.maxRetries = max
+		s.retryBackoff = backoff
+	}
+}
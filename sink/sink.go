@@ -0,0 +1,167 @@
+package sink
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+var (
+	ErrSinkIsClosed = errors.New("sink is closed")
+)
+
+const (
+	defaultBatchSize    = 100
+	defaultMaxRetries   = 3
+	defaultRetryBackoff = 500 * time.Millisecond
+)
+
+// BatchWriter delivers a batch of blocks to its destination. A batch that failed is written again on retry, so
+// writers should tolerate receiving the same blocks more than once.
+type BatchWriter interface {
+	WriteBatch(blocks []*processor.Block) error
+	Close() error
+}
+
+// New returns a processor.Sink buffering blocks into batches delivered to w. A batch is written once it is full, on
+// every flush interval when one is set, whenever the processor flushes its sinks (at the end of each run) and on
+// Close. Failed writes are retried with an exponential backoff.
+func New(w BatchWriter, opts ...Option) *Sink {
+	s := &Sink{
+		writer:       w,
+		batchSize:    defaultBatchSize,
+		maxRetries:   defaultMaxRetries,
+		retryBackoff: defaultRetryBackoff,
+	}
+
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if s.flushInterval > 0 {
+		s.stop = make(chan struct{})
+		s.done = make(chan struct{})
+
+		go s.flushPeriodically()
+	}
+
+	return s
+}
+
+type Sink struct {
+	mu              sync.Mutex
+	writer          BatchWriter
+	batch           []*processor.Block
+	batchSize       int
+	flushInterval   time.Duration
+	maxRetries      int
+	retryBackoff    time.Duration
+	backgroundError error
+	closed          bool
+	stopOnce        sync.Once
+	stop            chan struct{}
+	done            chan struct{}
+}
+
+func (s *Sink) Send(block *processor.Block) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrSinkIsClosed
+	}
+
+	if err := s.backgroundError; err != nil {
+		s.backgroundError = nil
+
+		return err
+	}
+
+	s.batch = append(s.batch, block)
+	if len(s.batch) >= s.batchSize {
+		return s.flush()
+	}
+
+	return nil
+}
+
+func (s *Sink) Flush() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.flush()
+}
+
+// Close writes the pending blocks and closes the writer. Closing the sink again has no effect.
+func (s *Sink) Close() error {
+	if s.stop != nil {
+		s.stopOnce.Do(func() {
+			close(s.stop)
+		})
+		<-s.done
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+
+	s.closed = true
+
+	if err := s.flush(); err != nil {
+		return err
+	}
+
+	return s.writer.Close()
+}
+
+func (s *Sink) flush() error {
+	if len(s.batch) == 0 {
+		return nil
+	}
+
+	backoff := s.retryBackoff
+
+	var err error
+	for attempt := 0; attempt <= s.maxRetries; attempt++ {
+		if attempt > 0 {
+			log.Printf("could not write batch of %d block(s), retrying in %s: %s\n", len(s.batch), backoff, err)
+			time.Sleep(backoff)
+			backoff *= 2
+		}
+
+		if err = s.writer.WriteBatch(s.batch); err == nil {
+			s.batch = nil
+
+			return nil
+		}
+	}
+
+	return fmt.Errorf("could not write batch of %d block(s) after %d attempt(s): %w", len(s.batch), s.maxRetries+1, err)
+}
+
+func (s *Sink) flushPeriodically() {
+	defer close(s.done)
+
+	ticker := time.NewTicker(s.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-s.stop:
+			return
+		case <-ticker.C:
+			s.mu.Lock()
+			if err := s.flush(); err != nil {
+				s.backgroundError = err
+			}
+			s.mu.Unlock()
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package sink
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+type testWriter struct {
+	mu      sync.Mutex
+	batches int
+	closed  int
+}
+
+func (w *testWriter) WriteBatch([]*processor.Block) error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.batches++
+
+	return nil
+}
+
+func (w *testWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.closed++
+
+	return nil
+}
+
+func TestSinkCloseTwice(t *testing.T) {
+	oo := Options{}
+
+	tests := []struct {
+		name string
+		opts []Option
+	}{
+		{name: "without flush interval"},
+		{name: "with flush interval", opts: []Option{oo.FlushInterval(time.Hour)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := &testWriter{}
+			s := New(w, tt.opts...)
+
+			if err := s.Send(processor.NewBlock(processor.NewBlockHeaderBuilder().Nonce(1).Build(), nil)); err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 2; i++ {
+				if err := s.Close(); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if w.batches != 1 || w.closed != 1 {
+				t.Fatalf("expected the batch to be written and the writer closed once, got %d batch(es) and %d close(s)", w.batches, w.closed)
+			}
+
+			if err := s.Send(processor.NewBlock(processor.NewBlockHeaderBuilder().Nonce(2).Build(), nil)); !errors.Is(err, ErrSinkIsClosed) {
+				t.Fatalf("expected %v, got %v", ErrSinkIsClosed, err)
+			}
+		})
+	}
+}
+
+func TestJSONLFileWriterRotation(t *testing.T) {
+	block := processor.NewBlock(processor.NewBlockHeaderBuilder().Nonce(1).Hash("block-1").Build(), nil)
+
+	line, err := json.Marshal(block)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size := int64(len(line) + 1)
+
+	tests := []struct {
+		name     string
+		maxBytes int64
+		files    int
+	}{
+		{name: "no limit", maxBytes: 0, files: 1},
+		{name: "negative limit", maxBytes: -1, files: 1},
+		{name: "one block per file", maxBytes: size, files: 4},
+		{name: "two blocks per file", maxBytes: 2 * size, files: 2},
+		{name: "limit below a block", maxBytes: size / 2, files: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			w, err := NewJSONLFileWriter(dir, "blocks", tt.maxBytes)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			for i := 0; i < 2; i++ {
+				if err := w.WriteBatch([]*processor.Block{block, block}); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(entries) != tt.files {
+				t.Fatalf("expected %d file(s), got %d", tt.files, len(entries))
+			}
+
+			lines := 0
+			for _, e := range entries {
+				content, err := os.ReadFile(filepath.Join(dir, e.Name()))
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				lines += strings.Count(string(content), "\n")
+			}
+
+			if lines != 4 {
+				t.Fatalf("expected 4 blocks written, got %d", lines)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package sink
+
+import (
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+// NewStdoutSink writes every block as a JSON line on the standard output.
+func NewStdoutSink(opts ...Option) *Sink {
+	return New(NewStreamWriter(os.Stdout), opts...)
+}
+
+// NewStreamWriter returns a BatchWriter writing every block as a JSON line to w.
+func NewStreamWriter(w io.Writer) *StreamWriter {
+	return &StreamWriter{encoder: json.NewEncoder(w)}
+}
+
+type StreamWriter struct {
+	encoder *json.Encoder
+}
+
+func (w *StreamWriter) WriteBatch(blocks []*processor.Block) error {
+	for _, block := range blocks {
+		if err := w.encoder.Encode(block); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *StreamWriter) Close() error {
+	return nil
+}
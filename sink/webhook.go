@@ -0,0 +1,92 @@
+package sink
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const (
+	SignatureHeader = "X-Processor-Signature"
+	TimestampHeader = "X-Processor-Timestamp"
+
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// NewWebhookSink posts batches of blocks as JSON to url. Each request is signed with secret, see WebhookWriter.
+func NewWebhookSink(url, secret string, opts ...Option) *Sink {
+	return New(NewWebhookWriter(url, secret), opts...)
+}
+
+func NewWebhookWriter(url, secret string) *WebhookWriter {
+	return &WebhookWriter{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: defaultWebhookTimeout},
+	}
+}
+
+// WebhookWriter posts {"blocks": [...]} to its URL. The receiver authenticates a request by computing the hex encoded
+// HMAC-SHA256 of "<timestamp>.<body>" with the shared secret, where timestamp is the value of TimestampHeader, and
+// comparing it to the value of SignatureHeader (prefixed by "sha256=").
+type WebhookWriter struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+type webhookPayload struct {
+	Blocks []*processor.Block `json:"blocks"`
+}
+
+func (w *WebhookWriter) WriteBatch(blocks []*processor.Block) error {
+	body, err := json.Marshal(webhookPayload{Blocks: blocks})
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request, err := http.NewRequest(http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(TimestampHeader, timestamp)
+	request.Header.Set(SignatureHeader, "sha256="+Sign(w.secret, timestamp, body))
+
+	response, err := w.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status %d", w.url, response.StatusCode)
+	}
+
+	return nil
+}
+
+func (w *WebhookWriter) Close() error {
+	return nil
+}
+
+// Sign returns the hex encoded HMAC-SHA256 of "<timestamp>.<body>", as sent in SignatureHeader.
+func Sign(secret []byte, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+
+	return hex.EncodeToString(mac.Sum(nil))
+}
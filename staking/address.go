@@ -0,0 +1,44 @@
+package staking
+
+import (
+	"bytes"
+
+	"github.com/thefabric-io/elrond-transaction-processor/bech32"
+)
+
+// The system smart contracts are executed by the system VM of the metachain.
+const (
+	ValidatorContractAddress         = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqplllst77y4l"
+	ESDTContractAddress              = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqzllls8a5w6u"
+	GovernanceContractAddress        = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqrlllsrujgla"
+	DelegationManagerContractAddress = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqylllslmq6y6"
+)
+
+// systemVMPrefix and systemContractSuffix frame the public keys of the contracts of the system VM.
+var (
+	systemVMPrefix       = []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 1}
+	systemContractSuffix = []byte{0xff, 0xff}
+)
+
+var builtinSystemContracts = map[string]bool{
+	ValidatorContractAddress:         true,
+	ESDTContractAddress:              true,
+	GovernanceContractAddress:        true,
+	DelegationManagerContractAddress: true,
+}
+
+// IsSystemContract reports whether the address is a contract of the system VM, delegation contracts included.
+func IsSystemContract(address string) bool {
+	_, key, err := bech32.Decode(address)
+	if err != nil || len(key) != 32 {
+		return false
+	}
+
+	return bytes.HasPrefix(key, systemVMPrefix) && bytes.HasSuffix(key, systemContractSuffix)
+}
+
+// IsDelegationContract reports whether the address is the contract of a staking provider, created by the delegation
+// manager.
+func IsDelegationContract(address string) bool {
+	return IsSystemContract(address) && !builtinSystemContracts[address]
+}
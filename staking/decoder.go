@@ -0,0 +1,348 @@
+package staking
+
+import (
+	"encoding/hex"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/bech32"
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const (
+	defaultPendingTimeout = time.Hour
+	addressHRP            = "erd"
+)
+
+// NewDecoder returns a processor.Sink reporting the calls to the staking and delegation system contracts.
+//
+// A call sent from a shard is executed by the metachain, which sends its return, the refunds and the amounts withdrawn
+// or claimed back to the shard of the caller as smart contract results. The event of a call is therefore reported once
+// both the call and its return were delivered, in any order, or directly from the result tree of the call when the
+// processor waits for finalized cross-shard smart contract results.
+func NewDecoder(onEvent OnEventFunc, opts ...Option) *Decoder {
+	d := &Decoder{
+		onEvent:        onEvent,
+		pendingTimeout: defaultPendingTimeout,
+		pending:        map[string]*pendingCall{},
+		reported:       map[string]time.Time{},
+		blockTimes:     map[processor.Shard]time.Time{},
+	}
+
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	return d
+}
+
+type Decoder struct {
+	mu             sync.Mutex
+	onEvent        OnEventFunc
+	pendingTimeout time.Duration
+	pending        map[string]*pendingCall
+	reported       map[string]time.Time
+	// blockTimes is the time of the last block of each shard.
+	blockTimes map[processor.Shard]time.Time
+}
+
+// pendingCall gathers a call and its smart contract results, which may be delivered before the call.
+type pendingCall struct {
+	event    *Event
+	results  processor.Transactions
+	returned *processor.SCRResult
+	seenAt   time.Time
+}
+
+func (d *Decoder) Send(block *processor.Block) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	header := block.Header()
+
+	for _, tx := range block.Transactions() {
+		if tx.HasOriginalTransactionHash() {
+			d.addResult(tx, header)
+
+			continue
+		}
+
+		d.addCall(tx, header)
+	}
+
+	d.blockTimes[header.Shard()] = header.Timestamp()
+	d.expire(d.blockTime())
+
+	return nil
+}
+
+func (d *Decoder) Flush() error {
+	return nil
+}
+
+func (d *Decoder) Close() error {
+	return nil
+}
+
+func (d *Decoder) addResult(tx *processor.Transaction, header *processor.BlockHeader) {
+	if !IsSystemContract(tx.Sender()) {
+		return
+	}
+
+	hash := tx.OriginalTransactionHash()
+	if _, found := d.reported[hash]; found {
+		return
+	}
+
+	p := d.pendingCall(hash, header)
+	if !p.addResult(tx) {
+		return
+	}
+
+	d.reportIfComplete(hash, p)
+}
+
+func (d *Decoder) addCall(tx *processor.Transaction, header *processor.BlockHeader) {
+	call := tx
+	if inner := tx.InnerTransaction(); inner != nil {
+		call = inner
+	}
+
+	kind, found := kindOf(call)
+	if !found {
+		return
+	}
+
+	// The call is delivered by the source shard too when the processor includes cross-shard started transactions
+	if tx.ResultTree() == nil && !tx.IsDestinationTo(header.Shard()) {
+		return
+	}
+
+	if _, found := d.reported[tx.Hash()]; found {
+		return
+	}
+
+	p := d.pendingCall(tx.Hash(), header)
+	p.event = newEvent(kind, tx.Hash(), call, header)
+
+	if tree := tx.ResultTree(); tree != nil {
+		for _, r := range tree.Results() {
+			p.addResult(r)
+		}
+
+		p.event.Outcome = tree.Outcome()
+		if failure := tree.Failure(); failure != nil {
+			p.event.Message = failure.Message()
+		}
+
+		d.report(tx.Hash(), p)
+
+		return
+	}
+
+	if tx.Status() == "fail" || tx.Status() == "invalid" {
+		p.event.Outcome = processor.OutcomeFailed
+		d.report(tx.Hash(), p)
+
+		return
+	}
+
+	d.reportIfComplete(tx.Hash(), p)
+}
+
+func (d *Decoder) pendingCall(hash string, header *processor.BlockHeader) *pendingCall {
+	p, found := d.pending[hash]
+	if !found {
+		p = &pendingCall{seenAt: header.Timestamp()}
+		d.pending[hash] = p
+	}
+
+	return p
+}
+
+// addResult adds a result not seen yet, and reports whether it was added.
+func (p *pendingCall) addResult(tx *processor.Transaction) bool {
+	if p.results.FindByHash(tx.Hash()) != nil {
+		return false
+	}
+
+	p.results = append(p.results, tx)
+
+	result := processor.ClassifySCR(tx)
+	if result.Kind() == processor.SCRKindReturn || result.Kind() == processor.SCRKindRefund {
+		if p.returned == nil || result.IsError() {
+			p.returned = result
+		}
+	}
+
+	return true
+}
+
+func (d *Decoder) reportIfComplete(hash string, p *pendingCall) {
+	if p.event == nil || p.returned == nil {
+		return
+	}
+
+	p.event.Outcome = p.returned.Outcome()
+	p.event.Message = p.returned.Message()
+
+	d.report(hash, p)
+}
+
+func (d *Decoder) report(hash string, p *pendingCall) {
+	e := p.event
+	e.Confirmed = true
+
+	switch e.Kind {
+	case KindWithdraw, KindClaimRewards, KindUnbondTokens, KindClaim:
+		e.Amount = p.receivedAmount(e.Caller).String()
+	case KindCreateDelegationContract:
+		if p.returned != nil && !p.returned.IsError() && len(p.returned.Arguments()) != 0 {
+			e.Provider = decodeAddress(p.returned.Arguments()[0])
+		}
+	}
+
+	delete(d.pending, hash)
+	d.reported[hash] = p.seenAt
+
+	if d.onEvent != nil {
+		d.onEvent(e)
+	}
+}
+
+// receivedAmount sums the value transferred to the caller. Returns and refunds are excluded, they carry the unused gas
+// or the value of a failed call back.
+func (p *pendingCall) receivedAmount(caller string) *big.Int {
+	amount := new(big.Int)
+	for _, r := range p.results {
+		if r.Receiver() != caller || processor.ClassifySCR(r).Kind() != processor.SCRKindTransfer {
+			continue
+		}
+
+		if v, ok := new(big.Int).SetString(r.Value(), 10); ok {
+			amount.Add(amount, v)
+		}
+	}
+
+	return amount
+}
+
+// blockTime returns the time of the last block of the shard the furthest behind, the shards of the pending calls and
+// of their results being processed at their own pace.
+func (d *Decoder) blockTime() time.Time {
+	var earliest time.Time
+	for _, t := range d.blockTimes {
+		if earliest.IsZero() || t.Before(earliest) {
+			earliest = t
+		}
+	}
+
+	return earliest
+}
+
+// expire reports the calls whose return was not seen within the pending timeout as not confirmed, and forgets the
+// results of unknown calls and the calls reported long ago.
+func (d *Decoder) expire(now time.Time) {
+	limit := now.Add(-d.pendingTimeout)
+
+	for hash, p := range d.pending {
+		if !p.seenAt.Before(limit) {
+			continue
+		}
+
+		delete(d.pending, hash)
+
+		if p.event != nil && d.onEvent != nil {
+			d.reported[hash] = p.seenAt
+			d.onEvent(p.event)
+		}
+	}
+
+	for hash, seenAt := range d.reported {
+		if seenAt.Before(limit) {
+			delete(d.reported, hash)
+		}
+	}
+}
+
+func kindOf(call *processor.Transaction) (Kind, bool) {
+	var kinds map[string]Kind
+
+	switch receiver := call.Receiver(); {
+	case receiver == ValidatorContractAddress:
+		kinds = validatorFunctionKinds
+	case receiver == DelegationManagerContractAddress:
+		kinds = delegationManagerFunctionKinds
+	case IsDelegationContract(receiver):
+		kinds = delegationFunctionKinds
+	default:
+		return "", false
+	}
+
+	kind, found := kinds[call.Function()]
+
+	return kind, found
+}
+
+func newEvent(kind Kind, hash string, call *processor.Transaction, header *processor.BlockHeader) *Event {
+	e := &Event{
+		Kind:      kind,
+		Hash:      hash,
+		Caller:    call.Sender(),
+		Provider:  call.Receiver(),
+		Shard:     header.Shard(),
+		Nonce:     header.Nonce(),
+		BlockHash: header.Hash(),
+		Timestamp: header.Timestamp(),
+	}
+
+	arguments := call.Arguments()
+
+	switch kind {
+	case KindDelegate, KindStake, KindCreateDelegationContract:
+		e.Amount = call.Value()
+	case KindUndelegate, KindUnstakeTokens:
+		if len(arguments) != 0 {
+			e.Amount = decodeAmount(arguments[0])
+		}
+	}
+
+	switch kind {
+	case KindCreateDelegationContract:
+		e.Provider = ""
+	case KindStake:
+		// stake@numberOfNodes@(blsKey@signature)*numberOfNodes
+		for i := 1; i+1 < len(arguments); i += 2 {
+			e.BLSKeys = append(e.BLSKeys, arguments[i])
+		}
+	case KindUnstake, KindUnbond:
+		e.BLSKeys = arguments
+	}
+
+	return e
+}
+
+func decodeAmount(s string) string {
+	v, ok := new(big.Int).SetString(strings.TrimSpace(s), 16)
+	if !ok {
+		return ""
+	}
+
+	return v.String()
+}
+
+func decodeAddress(s string) string {
+	key, err := hex.DecodeString(s)
+	if err != nil {
+		return ""
+	}
+
+	address, err := bech32.Encode(addressHRP, key)
+	if err != nil {
+		return ""
+	}
+
+	return address
+}
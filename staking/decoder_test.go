@@ -0,0 +1,217 @@
+package staking
+
+import (
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const (
+	testDelegationContract = "erd1qqqqqqqqqqqqqqqpqqqqqqqqqqqqqqqqqqqqqqqqqqqqqqpllllsxvv3xm"
+	testCaller             = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+)
+
+var testStart = time.Unix(1600000000, 0)
+
+func newTestCall(hash, receiver, value, data string) *processor.Transaction {
+	return processor.NewTransactionBuilder().
+		Hash(hash).
+		Sender(testCaller).
+		Receiver(receiver).
+		Value(value).
+		Data(base64.StdEncoding.EncodeToString([]byte(data))).
+		Status("success").
+		SourceShard(1).
+		DestinationShard(processor.ShardMetachain).
+		Build()
+}
+
+func newTestResult(hash, call, value, data string) *processor.Transaction {
+	return processor.NewTransactionBuilder().
+		Hash(hash).
+		Sender(testDelegationContract).
+		Receiver(testCaller).
+		Value(value).
+		Data(base64.StdEncoding.EncodeToString([]byte(data))).
+		OriginalTransactionHash(call).
+		SourceShard(processor.ShardMetachain).
+		DestinationShard(1).
+		Build()
+}
+
+func newTestBlock(shard processor.Shard, after time.Duration, txs ...*processor.Transaction) *processor.Block {
+	header := processor.NewBlockHeaderBuilder().Shard(shard).Nonce(processor.Nonce(after / time.Second)).Hash(fmt.Sprint("block-", after)).Timestamp(testStart.Add(after)).Build()
+
+	return processor.NewBlock(header, txs)
+}
+
+func TestKindOf(t *testing.T) {
+	tests := []struct {
+		name     string
+		receiver string
+		data     string
+		kind     Kind
+		found    bool
+	}{
+		{name: "delegate", receiver: testDelegationContract, data: "delegate", kind: KindDelegate, found: true},
+		{name: "undelegate", receiver: testDelegationContract, data: "unDelegate@0de0b6b3a7640000", kind: KindUndelegate, found: true},
+		{name: "claim rewards", receiver: testDelegationContract, data: "claimRewards", kind: KindClaimRewards, found: true},
+		{name: "create a delegation contract", receiver: DelegationManagerContractAddress, data: "createNewDelegationContract@@0f", kind: KindCreateDelegationContract, found: true},
+		{name: "stake", receiver: ValidatorContractAddress, data: "stake@01@aa@bb", kind: KindStake, found: true},
+		{name: "unbond tokens", receiver: ValidatorContractAddress, data: "unBondTokens", kind: KindUnbondTokens, found: true},
+		{name: "function of another contract", receiver: ValidatorContractAddress, data: "delegate"},
+		{name: "unknown function", receiver: testDelegationContract, data: "changeServiceFee@0f"},
+		{name: "other system contract", receiver: ESDTContractAddress, data: "stake"},
+		{name: "user account", receiver: testCaller, data: "delegate"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kind, found := kindOf(newTestCall("tx", tt.receiver, "0", tt.data))
+			if kind != tt.kind || found != tt.found {
+				t.Fatalf("expected %q (found: %t), got %q (found: %t)", tt.kind, tt.found, kind, found)
+			}
+		})
+	}
+}
+
+func TestNewEvent(t *testing.T) {
+	tests := []struct {
+		name     string
+		kind     Kind
+		receiver string
+		value    string
+		data     string
+		provider string
+		amount   string
+		keys     []string
+	}{
+		{name: "delegate", kind: KindDelegate, receiver: testDelegationContract, value: "1000000000000000000", data: "delegate", provider: testDelegationContract, amount: "1000000000000000000"},
+		{name: "undelegate", kind: KindUndelegate, receiver: testDelegationContract, value: "0", data: "unDelegate@0de0b6b3a7640000", provider: testDelegationContract, amount: "1000000000000000000"},
+		{name: "redelegate rewards", kind: KindRedelegateRewards, receiver: testDelegationContract, value: "0", data: "reDelegateRewards", provider: testDelegationContract},
+		{name: "create a delegation contract", kind: KindCreateDelegationContract, receiver: DelegationManagerContractAddress, value: "1250000000000000000000", data: "createNewDelegationContract@@0f", amount: "1250000000000000000000"},
+		{name: "stake", kind: KindStake, receiver: ValidatorContractAddress, value: "5000000000000000000000", data: "stake@02@aa@a1@bb@b1", provider: ValidatorContractAddress, amount: "5000000000000000000000", keys: []string{"aa", "bb"}},
+		{name: "unstake", kind: KindUnstake, receiver: ValidatorContractAddress, value: "0", data: "unStake@aa@bb", provider: ValidatorContractAddress, keys: []string{"aa", "bb"}},
+		{name: "unstake tokens", kind: KindUnstakeTokens, receiver: ValidatorContractAddress, value: "0", data: "unStakeTokens@0de0b6b3a7640000", provider: ValidatorContractAddress, amount: "1000000000000000000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := newTestBlock(processor.ShardMetachain, time.Minute).Header()
+
+			e := newEvent(tt.kind, "tx", newTestCall("tx", tt.receiver, tt.value, tt.data), header)
+			if e.Kind != tt.kind || e.Caller != testCaller || e.Provider != tt.provider || e.Amount != tt.amount || strings.Join(e.BLSKeys, ",") != strings.Join(tt.keys, ",") {
+				t.Fatalf("unexpected event %+v", e)
+			}
+
+			if e.Shard != processor.ShardMetachain || e.Nonce != header.Nonce() || e.BlockHash != header.Hash() || !e.Timestamp.Equal(header.Timestamp()) {
+				t.Fatalf("expected the event in the block %s, got %+v", header.Hash(), e)
+			}
+		})
+	}
+}
+
+func TestDecoderPairsCallsAndReturns(t *testing.T) {
+	userError := "@" + hex.EncodeToString([]byte(processor.ReturnCodeUserError)) + "@" + hex.EncodeToString([]byte("delegation cap reached"))
+
+	delegate := newTestCall("delegate", testDelegationContract, "1000000000000000000", "delegate")
+	withdraw := newTestCall("withdraw", testDelegationContract, "0", "withdraw")
+
+	tests := []struct {
+		name     string
+		blocks   []*processor.Block
+		expected []string
+	}{
+		{
+			name: "return after the call",
+			blocks: []*processor.Block{
+				newTestBlock(processor.ShardMetachain, 0, delegate),
+				newTestBlock(1, 6*time.Second, newTestResult("return", "delegate", "0", "@6f6b")),
+			},
+			expected: []string{"delegate delegate 1000000000000000000 success confirmed"},
+		},
+		{
+			name: "return before the call",
+			blocks: []*processor.Block{
+				newTestBlock(1, 6*time.Second, newTestResult("return", "delegate", "0", "@6f6b")),
+				newTestBlock(processor.ShardMetachain, 0, delegate),
+			},
+			expected: []string{"delegate delegate 1000000000000000000 success confirmed"},
+		},
+		{
+			name: "error returned with the value",
+			blocks: []*processor.Block{
+				newTestBlock(processor.ShardMetachain, 0, delegate),
+				newTestBlock(1, 6*time.Second, newTestResult("return", "delegate", "1000000000000000000", userError)),
+			},
+			expected: []string{"delegate delegate 1000000000000000000 user-error confirmed delegation cap reached"},
+		},
+		{
+			name: "amount received along with the return",
+			blocks: []*processor.Block{
+				newTestBlock(processor.ShardMetachain, 0, withdraw),
+				newTestBlock(1, 6*time.Second, newTestResult("transfer", "withdraw", "2000000000000000000", ""), newTestResult("return", "withdraw", "0", "@6f6b")),
+			},
+			expected: []string{"withdraw withdraw 2000000000000000000 success confirmed"},
+		},
+		{
+			name: "call delivered again once reported",
+			blocks: []*processor.Block{
+				newTestBlock(processor.ShardMetachain, 0, delegate),
+				newTestBlock(1, 6*time.Second, newTestResult("return", "delegate", "0", "@6f6b")),
+				newTestBlock(processor.ShardMetachain, 0, delegate),
+			},
+			expected: []string{"delegate delegate 1000000000000000000 success confirmed"},
+		},
+		{
+			name: "return not seen within the timeout",
+			blocks: []*processor.Block{
+				newTestBlock(1, 0),
+				newTestBlock(processor.ShardMetachain, 0, delegate),
+				newTestBlock(1, 2*time.Minute),
+				newTestBlock(processor.ShardMetachain, 2*time.Minute),
+			},
+			expected: []string{"delegate delegate 1000000000000000000  unconfirmed"},
+		},
+		{
+			name: "shard of the return behind the shard of the call",
+			blocks: []*processor.Block{
+				newTestBlock(1, 0),
+				newTestBlock(processor.ShardMetachain, 0, delegate),
+				newTestBlock(processor.ShardMetachain, 2*time.Minute),
+				newTestBlock(1, 6*time.Second, newTestResult("return", "delegate", "0", "@6f6b")),
+			},
+			expected: []string{"delegate delegate 1000000000000000000 success confirmed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			events := make([]string, 0)
+			oo := Options{}
+			d := NewDecoder(func(e *Event) {
+				confirmed := "unconfirmed"
+				if e.Confirmed {
+					confirmed = "confirmed"
+				}
+
+				events = append(events, strings.TrimSpace(fmt.Sprintf("%s %s %s %s %s %s", e.Hash, e.Kind, e.Amount, e.Outcome, confirmed, e.Message)))
+			}, oo.PendingTimeout(time.Minute))
+
+			for _, block := range tt.blocks {
+				if err := d.Send(block); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			if strings.Join(events, "; ") != strings.Join(tt.expected, "; ") {
+				t.Fatalf("expected %q, got %q", tt.expected, events)
+			}
+		})
+	}
+}
@@ -0,0 +1,94 @@
+// Package staking recognizes the calls to the staking and delegation system smart contracts and reports them as typed
+// events once their smart contract results came back from the metachain.
+package staking
+
+import (
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+// Kind is the operation reported by an Event.
+type Kind string
+
+const (
+	// KindDelegate stakes the value of the call with a staking provider.
+	KindDelegate Kind = "delegate"
+	// KindUndelegate unstakes an amount from a staking provider, which can be withdrawn after the unbonding period.
+	KindUndelegate Kind = "undelegate"
+	// KindWithdraw withdraws the unbonded amounts from a staking provider.
+	KindWithdraw Kind = "withdraw"
+	// KindClaimRewards transfers the rewards of a delegator.
+	KindClaimRewards Kind = "claim-rewards"
+	// KindRedelegateRewards stakes the rewards of a delegator with the same provider.
+	KindRedelegateRewards Kind = "redelegate-rewards"
+	// KindCreateDelegationContract creates a staking provider with the value of the call as initial stake.
+	KindCreateDelegationContract Kind = "create-delegation-contract"
+	// KindStake stakes the value of the call for the validator nodes of the given BLS keys.
+	KindStake Kind = "stake"
+	// KindUnstake unstakes the validator nodes of the given BLS keys.
+	KindUnstake Kind = "unstake"
+	// KindUnbond unbonds the validator nodes of the given BLS keys.
+	KindUnbond Kind = "unbond"
+	// KindUnstakeTokens unstakes an amount of the top up of a validator.
+	KindUnstakeTokens Kind = "unstake-tokens"
+	// KindUnbondTokens withdraws the unstaked amounts of a validator.
+	KindUnbondTokens Kind = "unbond-tokens"
+	// KindClaim withdraws the unbonded amounts and unused stake of a validator.
+	KindClaim Kind = "claim"
+)
+
+// The kinds of the functions of the delegation contracts, of the delegation manager and of the validator contract.
+var (
+	delegationFunctionKinds = map[string]Kind{
+		"delegate":          KindDelegate,
+		"unDelegate":        KindUndelegate,
+		"withdraw":          KindWithdraw,
+		"claimRewards":      KindClaimRewards,
+		"reDelegateRewards": KindRedelegateRewards,
+	}
+	delegationManagerFunctionKinds = map[string]Kind{
+		"createNewDelegationContract": KindCreateDelegationContract,
+	}
+	validatorFunctionKinds = map[string]Kind{
+		"stake":         KindStake,
+		"unStake":       KindUnstake,
+		"unBond":        KindUnbond,
+		"unStakeTokens": KindUnstakeTokens,
+		"unBondTokens":  KindUnbondTokens,
+		"claim":         KindClaim,
+	}
+)
+
+// Event is a staking or delegation operation executed by the metachain.
+type Event struct {
+	Kind Kind `json:"kind"`
+	// Hash is the hash of the transaction calling the system contract, the relayed transaction for relayed calls.
+	Hash string `json:"hash"`
+	// Caller is the delegator or the owner of the validator nodes.
+	Caller string `json:"caller"`
+	// Provider is the delegation contract of the staking provider, or the validator contract for the staking calls. It
+	// is the created contract for KindCreateDelegationContract, when reported by the delegation manager.
+	Provider string `json:"provider,omitempty"`
+	// Amount is the amount staked, unstaked or received by the caller in the smallest denomination. It is empty when
+	// the operation does not move a known amount, e.g. for KindRedelegateRewards.
+	Amount  string   `json:"amount,omitempty"`
+	BLSKeys []string `json:"blsKeys,omitempty"`
+	// Outcome is the outcome reported by the return of the system contract, the error is described by Message.
+	Outcome processor.Outcome `json:"outcome"`
+	Message string            `json:"message,omitempty"`
+	// Confirmed is false when the return of the system contract was not seen within the pending timeout, the outcome
+	// and the amounts received are then unknown.
+	Confirmed bool            `json:"confirmed"`
+	Shard     processor.Shard `json:"shard"`
+	Nonce     processor.Nonce `json:"nonce"`
+	BlockHash string          `json:"blockHash"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// Failed reports whether the system contract returned an error.
+func (e *Event) Failed() bool {
+	return e.Confirmed && e.Outcome != processor.OutcomeSuccess
+}
+
+type OnEventFunc func(e *Event)
@@ -0,0 +1,18 @@
+package staking
+
+import "time"
+
+type Option func(*Decoder)
+
+type Options struct{}
+
+// PendingTimeout sets how long, in block time, a call waits for its return before being reported as not confirmed.
+// The calls reported are also remembered for this long to ignore the blocks delivered again. It is one hour by
+// default.
+func (oo *Options) PendingTimeout(d time.Duration) Option {
+	return func(dec *Decoder) {
+		if d > 0 {
+			dec.pendingTimeout = d
+		}
+	}
+}
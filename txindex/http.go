@@ -0,0 +1,192 @@
+package txindex
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+// NewHandler returns the HTTP query API of the index:
+//
+//	GET /transactions?since=&until=&limit=&cursor=&order=asc
+//	GET /transactions/{hash}
+//	GET /transactions/{hash}/results
+//	GET /addresses/{address}/transactions?role=sender|receiver&since=&until=&limit=&cursor=&order=asc
+//	GET /blocks/{shard}/{nonce}/transactions
+//
+// Times are unix timestamps in seconds or RFC 3339 dates. Pages are ordered newest first unless order=asc, and carry a
+// nextCursor to pass in the cursor parameter to get the following page. The results of a transaction are the
+// transactions whose original transaction it is, along with the transaction itself.
+func NewHandler(i *Index) http.Handler {
+	return &handler{index: i}
+}
+
+type handler struct {
+	index *Index
+}
+
+type resultsResponse struct {
+	Original *Entry   `json:"original,omitempty"`
+	Results  []*Entry `json:"results"`
+}
+
+func (h *handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+
+		return
+	}
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case len(parts) == 1 && parts[0] == "transactions":
+		q, err := queryFromRequest(r)
+		if err != nil {
+			writeError(w, err)
+
+			return
+		}
+
+		page, err := h.index.ByTime(q)
+		writeResult(w, page, err)
+	case len(parts) == 2 && parts[0] == "transactions":
+		entry, err := h.index.Get(parts[1])
+		writeResult(w, entry, err)
+	case len(parts) == 3 && parts[0] == "transactions" && parts[2] == "results":
+		h.serveResults(w, parts[1])
+	case len(parts) == 3 && parts[0] == "addresses" && parts[2] == "transactions":
+		q, err := queryFromRequest(r)
+		if err != nil {
+			writeError(w, err)
+
+			return
+		}
+
+		page, err := h.index.ByAddress(parts[1], q)
+		writeResult(w, page, err)
+	case len(parts) == 4 && parts[0] == "blocks" && parts[3] == "transactions":
+		shard, err := strconv.ParseUint(parts[1], 10, 32)
+		if err != nil {
+			http.Error(w, "invalid shard", http.StatusBadRequest)
+
+			return
+		}
+
+		nonce, err := strconv.ParseUint(parts[2], 10, 64)
+		if err != nil {
+			http.Error(w, "invalid nonce", http.StatusBadRequest)
+
+			return
+		}
+
+		entries, err := h.index.ByBlock(processor.Shard(shard), processor.Nonce(nonce))
+		writeResult(w, entries, err)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func (h *handler) serveResults(w http.ResponseWriter, hash string) {
+	results, err := h.index.ByOriginalTransaction(hash)
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	original, err := h.index.Get(hash)
+	if err != nil && !errors.Is(err, ErrTransactionNotFound) {
+		writeError(w, err)
+
+		return
+	}
+
+	if original == nil && len(results) == 0 {
+		writeError(w, ErrTransactionNotFound)
+
+		return
+	}
+
+	writeResult(w, resultsResponse{Original: original, Results: results}, nil)
+}
+
+func queryFromRequest(r *http.Request) (Query, error) {
+	values := r.URL.Query()
+
+	q := Query{
+		Cursor:    values.Get("cursor"),
+		Ascending: values.Get("order") == "asc",
+		Role:      Role(values.Get("role")),
+	}
+
+	switch q.Role {
+	case AnyRole, Sender, Receiver:
+	default:
+		return q, errBadRequest("role must be sender or receiver")
+	}
+
+	var err error
+	if q.Since, err = parseTime(values.Get("since")); err != nil {
+		return q, errBadRequest("invalid since")
+	}
+
+	if q.Until, err = parseTime(values.Get("until")); err != nil {
+		return q, errBadRequest("invalid until")
+	}
+
+	if limit := values.Get("limit"); limit != "" {
+		if q.Limit, err = strconv.Atoi(limit); err != nil {
+			return q, errBadRequest("invalid limit")
+		}
+	}
+
+	return q, nil
+}
+
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	return time.Parse(time.RFC3339, s)
+}
+
+type errBadRequest string
+
+func (e errBadRequest) Error() string {
+	return string(e)
+}
+
+func writeError(w http.ResponseWriter, err error) {
+	var badRequest errBadRequest
+
+	switch {
+	case errors.As(err, &badRequest), errors.Is(err, ErrInvalidCursor):
+		http.Error(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, ErrTransactionNotFound):
+		http.Error(w, err.Error(), http.StatusNotFound)
+	default:
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+	}
+}
+
+func writeResult(w http.ResponseWriter, v interface{}, err error) {
+	if err != nil {
+		writeError(w, err)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(v)
+}
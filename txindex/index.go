@@ -0,0 +1,284 @@
+package txindex
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	ErrTransactionNotFound = errors.New("transaction not found")
+)
+
+var (
+	transactionsBucket = []byte("transactions")
+	byAddressBucket    = []byte("by-address")
+	byBlockBucket      = []byte("by-block")
+	byTimeBucket       = []byte("by-time")
+	byOriginalBucket   = []byte("by-original")
+)
+
+// Entry is an indexed transaction along with the blocks it was seen in. A cross-shard transaction is seen in a block of
+// its source shard and in a block of its destination shard.
+type Entry struct {
+	Transaction *processor.Transaction `json:"transaction"`
+	Blocks      []BlockReference       `json:"blocks"`
+}
+
+type BlockReference struct {
+	Shard     processor.Shard `json:"shard"`
+	Nonce     processor.Nonce `json:"nonce"`
+	Hash      string          `json:"hash"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+// Timestamp returns the time of the first block the transaction was seen in, used to order the entries.
+func (e *Entry) Timestamp() time.Time {
+	if len(e.Blocks) == 0 {
+		return time.Time{}
+	}
+
+	first := e.Blocks[0].Timestamp
+	for _, b := range e.Blocks[1:] {
+		if b.Timestamp < first {
+			first = b.Timestamp
+		}
+	}
+
+	return time.Unix(first, 0)
+}
+
+// Open opens or creates the index stored in the file at path. The index is a processor.Sink storing the transactions by
+// hash, sender, receiver, block, time and original transaction hash.
+func Open(path string, opts ...Option) (*Index, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("could not open transaction index %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, b := range [][]byte{transactionsBucket, byAddressBucket, byBlockBucket, byTimeBucket, byOriginalBucket} {
+			if _, err := tx.CreateBucketIfNotExists(b); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		_ = db.Close()
+
+		return nil, err
+	}
+
+	i := &Index{db: db, now: time.Now}
+	for _, opt := range opts {
+		opt(i)
+	}
+
+	return i, nil
+}
+
+type Index struct {
+	db        *bolt.DB
+	retention time.Duration
+	lastPrune time.Time
+	now       func() time.Time
+}
+
+func (i *Index) Send(block *processor.Block) error {
+	reference := BlockReference{
+		Shard:     block.Shard(),
+		Nonce:     block.Nonce(),
+		Hash:      block.Hash(),
+		Timestamp: block.Header().Timestamp().Unix(),
+	}
+
+	return i.db.Update(func(tx *bolt.Tx) error {
+		for _, t := range block.Transactions() {
+			if err := put(tx, t, reference); err != nil {
+				return fmt.Errorf("could not index transaction %s: %w", t.Hash(), err)
+			}
+		}
+
+		return nil
+	})
+}
+
+// Flush prunes the entries older than the retention, at most once per hour. Blocks are written as soon as they are
+// sent.
+func (i *Index) Flush() error {
+	if i.retention <= 0 || i.now().Sub(i.lastPrune) < time.Hour {
+		return nil
+	}
+
+	if _, err := i.Prune(i.now().Add(-i.retention)); err != nil {
+		return err
+	}
+
+	i.lastPrune = i.now()
+
+	return nil
+}
+
+func (i *Index) Close() error {
+	return i.db.Close()
+}
+
+// Prune deletes the entries whose timestamp is before t and returns their number.
+func (i *Index) Prune(before time.Time) (int, error) {
+	pruned := 0
+
+	err := i.db.Update(func(tx *bolt.Tx) error {
+		hashes := make([]string, 0)
+
+		c := tx.Bucket(byTimeBucket).Cursor()
+		upper := encodeTimestamp(before.Unix())
+		for k, _ := c.First(); k != nil && bytes.Compare(k[:8], upper) < 0; k, _ = c.Next() {
+			hashes = append(hashes, string(k[8:]))
+		}
+
+		for _, h := range hashes {
+			entry, err := get(tx, h)
+			if err != nil {
+				return err
+			}
+
+			deleteKeys(tx, entry)
+			if err := tx.Bucket(transactionsBucket).Delete([]byte(h)); err != nil {
+				return err
+			}
+		}
+
+		pruned = len(hashes)
+
+		return nil
+	})
+
+	return pruned, err
+}
+
+// put stores the transaction seen in the block. The block replaces a previous one of the same shard, which happens
+// when the processor replays blocks or when a block is replaced.
+func put(tx *bolt.Tx, t *processor.Transaction, reference BlockReference) error {
+	entry, err := get(tx, t.Hash())
+	if err != nil && !errors.Is(err, ErrTransactionNotFound) {
+		return err
+	}
+
+	if entry == nil {
+		entry = &Entry{}
+	} else {
+		deleteKeys(tx, entry)
+	}
+
+	blocks := []BlockReference{reference}
+	for _, b := range entry.Blocks {
+		if b.Shard != reference.Shard {
+			blocks = append(blocks, b)
+		}
+	}
+
+	entry.Transaction = t
+	entry.Blocks = blocks
+
+	value, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Bucket(transactionsBucket).Put([]byte(t.Hash()), value); err != nil {
+		return err
+	}
+
+	return putKeys(tx, entry)
+}
+
+func get(tx *bolt.Tx, hash string) (*Entry, error) {
+	value := tx.Bucket(transactionsBucket).Get([]byte(hash))
+	if value == nil {
+		return nil, ErrTransactionNotFound
+	}
+
+	entry := &Entry{}
+	if err := json.Unmarshal(value, entry); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+func putKeys(tx *bolt.Tx, e *Entry) error {
+	t := e.Transaction
+	timestamp := e.Timestamp().Unix()
+
+	for address, role := range roles(t) {
+		if err := tx.Bucket(byAddressBucket).Put(addressKey(address, timestamp, t.Hash()), []byte{role}); err != nil {
+			return err
+		}
+	}
+
+	for _, b := range e.Blocks {
+		if err := tx.Bucket(byBlockBucket).Put(blockKey(b.Shard, b.Nonce, t.Hash()), nil); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Bucket(byTimeBucket).Put(timeKey(timestamp, t.Hash()), nil); err != nil {
+		return err
+	}
+
+	if t.HasOriginalTransactionHash() {
+		if err := tx.Bucket(byOriginalBucket).Put(originalKey(t.OriginalTransactionHash(), t.Hash()), nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deleteKeys(tx *bolt.Tx, e *Entry) {
+	t := e.Transaction
+	timestamp := e.Timestamp().Unix()
+
+	for address := range roles(t) {
+		_ = tx.Bucket(byAddressBucket).Delete(addressKey(address, timestamp, t.Hash()))
+	}
+
+	for _, b := range e.Blocks {
+		_ = tx.Bucket(byBlockBucket).Delete(blockKey(b.Shard, b.Nonce, t.Hash()))
+	}
+
+	_ = tx.Bucket(byTimeBucket).Delete(timeKey(timestamp, t.Hash()))
+
+	if t.HasOriginalTransactionHash() {
+		_ = tx.Bucket(byOriginalBucket).Delete(originalKey(t.OriginalTransactionHash(), t.Hash()))
+	}
+}
+
+// roles returns the addresses involved in the transaction, including the parties of the inner transaction of a relayed
+// transaction, along with their roles. The missing parties, e.g. the receiver of some smart contract results, are left
+// out.
+func roles(t *processor.Transaction) map[string]byte {
+	r := map[string]byte{}
+	add := func(address string, role byte) {
+		if address != "" {
+			r[address] |= role
+		}
+	}
+
+	add(t.Sender(), roleSender)
+	add(t.Receiver(), roleReceiver)
+
+	if inner := t.InnerTransaction(); inner != nil {
+		add(inner.Sender(), roleSender)
+		add(inner.Receiver(), roleReceiver)
+	}
+
+	return r
+}
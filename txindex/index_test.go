@@ -0,0 +1,255 @@
+package txindex
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+var testStart = time.Unix(1600000000, 0)
+
+func newTestIndex(t *testing.T) *Index {
+	t.Helper()
+
+	i, err := Open(filepath.Join(t.TempDir(), "index.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	t.Cleanup(func() {
+		_ = i.Close()
+	})
+
+	return i
+}
+
+func newTestTransaction(hash, sender, receiver string) *processor.Transaction {
+	return processor.NewTransactionBuilder().Hash(hash).Sender(sender).Receiver(receiver).Build()
+}
+
+// newTestBlock returns a block of the shard whose timestamp is the nonce in minutes after the start.
+func newTestBlock(shard processor.Shard, nonce processor.Nonce, hash string, txs ...*processor.Transaction) *processor.Block {
+	header := processor.NewBlockHeaderBuilder().Shard(shard).Nonce(nonce).Hash(hash).Timestamp(testStart.Add(time.Duration(nonce) * time.Minute)).Build()
+
+	return processor.NewBlock(header, txs)
+}
+
+func hashesOf(entries []*Entry) string {
+	hashes := make([]string, 0, len(entries))
+	for _, e := range entries {
+		hashes = append(hashes, e.Transaction.Hash())
+	}
+
+	return strings.Join(hashes, " ")
+}
+
+func blocksOf(e *Entry) string {
+	blocks := make([]string, 0, len(e.Blocks))
+	for _, b := range e.Blocks {
+		blocks = append(blocks, fmt.Sprintf("%d/%d/%s", b.Shard, b.Nonce, b.Hash))
+	}
+
+	return strings.Join(blocks, " ")
+}
+
+func TestIndexSend(t *testing.T) {
+	cross := newTestTransaction("cross", "erd1alice", "erd1bob")
+
+	tests := []struct {
+		name     string
+		blocks   []*processor.Block
+		blocksOf string
+		byAlice  string
+		byBlock  map[string]string
+	}{
+		{
+			name:     "block delivered again",
+			blocks:   []*processor.Block{newTestBlock(0, 1, "a", cross), newTestBlock(0, 1, "a", cross)},
+			blocksOf: "0/1/a",
+			byAlice:  "cross",
+			byBlock:  map[string]string{"0/1": "cross"},
+		},
+		{
+			name:     "transaction seen in the source and the destination shards",
+			blocks:   []*processor.Block{newTestBlock(0, 1, "a", cross), newTestBlock(1, 2, "b", cross)},
+			blocksOf: "1/2/b 0/1/a",
+			byAlice:  "cross",
+			byBlock:  map[string]string{"0/1": "cross", "1/2": "cross"},
+		},
+		{
+			name:     "replayed blocks of both shards",
+			blocks:   []*processor.Block{newTestBlock(0, 1, "a", cross), newTestBlock(1, 2, "b", cross), newTestBlock(0, 1, "a", cross), newTestBlock(1, 2, "b", cross)},
+			blocksOf: "1/2/b 0/1/a",
+			byAlice:  "cross",
+			byBlock:  map[string]string{"0/1": "cross", "1/2": "cross"},
+		},
+		{
+			name:     "transaction moved to another block of the shard",
+			blocks:   []*processor.Block{newTestBlock(0, 1, "a", cross), newTestBlock(0, 3, "c", cross)},
+			blocksOf: "0/3/c",
+			byAlice:  "cross",
+			byBlock:  map[string]string{"0/1": "", "0/3": "cross"},
+		},
+		{
+			name:     "result without receiver",
+			blocks:   []*processor.Block{newTestBlock(0, 1, "a", cross, newTestTransaction("result", "erd1alice", ""))},
+			blocksOf: "0/1/a",
+			byAlice:  "result cross",
+			byBlock:  map[string]string{"0/1": "cross result"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			i := newTestIndex(t)
+			for _, block := range tt.blocks {
+				if err := i.Send(block); err != nil {
+					t.Fatal(err)
+				}
+			}
+
+			entry, err := i.Get("cross")
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if blocks := blocksOf(entry); blocks != tt.blocksOf {
+				t.Fatalf("expected the blocks %q, got %q", tt.blocksOf, blocks)
+			}
+
+			page, err := i.ByAddress("erd1alice", Query{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if hashes := hashesOf(page.Entries); hashes != tt.byAlice {
+				t.Fatalf("expected %q by address, got %q", tt.byAlice, hashes)
+			}
+
+			for block, expected := range tt.byBlock {
+				var (
+					shard processor.Shard
+					nonce processor.Nonce
+				)
+
+				if _, err := fmt.Sscanf(block, "%d/%d", &shard, &nonce); err != nil {
+					t.Fatal(err)
+				}
+
+				entries, err := i.ByBlock(shard, nonce)
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				if hashes := hashesOf(entries); hashes != expected {
+					t.Fatalf("expected %q in block %s, got %q", expected, block, hashes)
+				}
+			}
+
+			/* No transaction is indexed under an empty address */
+			page, err = i.ByAddress("", Query{})
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(page.Entries) != 0 {
+				t.Fatalf("expected no transaction for an empty address, got %q", hashesOf(page.Entries))
+			}
+		})
+	}
+}
+
+func TestIndexQuery(t *testing.T) {
+	i := newTestIndex(t)
+
+	/* tx-n is sent in the block of nonce n, n minutes after the start */
+	for n := 1; n <= 5; n++ {
+		sender, receiver := "erd1alice", "erd1bob"
+		if n%2 == 0 {
+			sender, receiver = receiver, sender
+		}
+
+		if err := i.Send(newTestBlock(0, processor.Nonce(n), fmt.Sprint("block-", n), newTestTransaction(fmt.Sprint("tx-", n), sender, receiver))); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		name     string
+		byAlice  bool
+		query    Query
+		expected []string
+	}{
+		{name: "newest first", query: Query{Limit: 2}, expected: []string{"tx-5 tx-4", "tx-3 tx-2", "tx-1"}},
+		{name: "oldest first", query: Query{Limit: 2, Ascending: true}, expected: []string{"tx-1 tx-2", "tx-3 tx-4", "tx-5"}},
+		{name: "page of every entry", query: Query{Limit: 5}, expected: []string{"tx-5 tx-4 tx-3 tx-2 tx-1"}},
+		{name: "since", query: Query{Since: testStart.Add(3 * time.Minute), Limit: 2}, expected: []string{"tx-5 tx-4", "tx-3"}},
+		{name: "until", query: Query{Until: testStart.Add(3 * time.Minute), Limit: 2, Ascending: true}, expected: []string{"tx-1 tx-2", "tx-3"}},
+		{name: "since and until", query: Query{Since: testStart.Add(2 * time.Minute), Until: testStart.Add(4 * time.Minute)}, expected: []string{"tx-4 tx-3 tx-2"}},
+		{name: "empty window", query: Query{Since: testStart.Add(time.Hour)}, expected: []string{""}},
+		{name: "by address", byAlice: true, query: Query{Limit: 2}, expected: []string{"tx-5 tx-4", "tx-3 tx-2", "tx-1"}},
+		{name: "by sender", byAlice: true, query: Query{Role: Sender, Limit: 2, Ascending: true}, expected: []string{"tx-1 tx-3", "tx-5"}},
+		{name: "by receiver within a window", byAlice: true, query: Query{Role: Receiver, Until: testStart.Add(3 * time.Minute)}, expected: []string{"tx-2"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pages := make([]string, 0)
+
+			q := tt.query
+			for {
+				var (
+					page *Page
+					err  error
+				)
+
+				if tt.byAlice {
+					page, err = i.ByAddress("erd1alice", q)
+				} else {
+					page, err = i.ByTime(q)
+				}
+
+				if err != nil {
+					t.Fatal(err)
+				}
+
+				pages = append(pages, hashesOf(page.Entries))
+				if page.NextCursor == "" || len(pages) > len(tt.expected) {
+					break
+				}
+
+				q.Cursor = page.NextCursor
+			}
+
+			if fmt.Sprintf("%q", pages) != fmt.Sprintf("%q", tt.expected) {
+				t.Fatalf("expected the pages %q, got %q", tt.expected, pages)
+			}
+		})
+	}
+}
+
+func TestIndexQueryInvalidCursor(t *testing.T) {
+	i := newTestIndex(t)
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{name: "not encoded", cursor: "not a cursor"},
+		{name: "cursor of another address", cursor: base64.RawURLEncoding.EncodeToString(addressKey("erd1bob", 0, "tx"))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := i.ByAddress("erd1alice", Query{Cursor: tt.cursor}); !errors.Is(err, ErrInvalidCursor) {
+				t.Fatalf("expected %v, got %v", ErrInvalidCursor, err)
+			}
+		})
+	}
+}
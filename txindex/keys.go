@@ -0,0 +1,71 @@
+package txindex
+
+import (
+	"encoding/binary"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const (
+	roleSender   byte = 1
+	roleReceiver byte = 2
+
+	separator byte = 0
+)
+
+func encodeTimestamp(t int64) []byte {
+	if t < 0 {
+		t = 0
+	}
+
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, uint64(t))
+
+	return b
+}
+
+func addressPrefix(address string) []byte {
+	return append([]byte(address), separator)
+}
+
+func addressKey(address string, timestamp int64, hash string) []byte {
+	return concat(addressPrefix(address), encodeTimestamp(timestamp), []byte(hash))
+}
+
+func timeKey(timestamp int64, hash string) []byte {
+	return concat(encodeTimestamp(timestamp), []byte(hash))
+}
+
+func blockPrefix(shard processor.Shard, nonce processor.Nonce) []byte {
+	b := make([]byte, 12)
+	binary.BigEndian.PutUint32(b, uint32(shard))
+	binary.BigEndian.PutUint64(b[4:], uint64(nonce))
+
+	return b
+}
+
+func blockKey(shard processor.Shard, nonce processor.Nonce, hash string) []byte {
+	return concat(blockPrefix(shard, nonce), []byte(hash))
+}
+
+func originalPrefix(originalHash string) []byte {
+	return append([]byte(originalHash), separator)
+}
+
+func originalKey(originalHash, hash string) []byte {
+	return concat(originalPrefix(originalHash), []byte(hash))
+}
+
+func concat(parts ...[]byte) []byte {
+	n := 0
+	for _, p := range parts {
+		n += len(p)
+	}
+
+	b := make([]byte, 0, n)
+	for _, p := range parts {
+		b = append(b, p...)
+	}
+
+	return b
+}
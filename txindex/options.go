@@ -0,0 +1,14 @@
+package txindex
+
+import "time"
+
+type Option func(*Index)
+
+type Options struct{}
+
+// Retention deletes the entries older than d, checked when the processor flushes its sinks.
+func (oo *Options) Retention(d time.Duration) Option {
+	return func(i *Index) {
+		i.retention = d
+	}
+}
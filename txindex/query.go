@@ -0,0 +1,231 @@
+package txindex
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	ErrInvalidCursor = errors.New("invalid cursor")
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 1000
+)
+
+type Role string
+
+const (
+	AnyRole  Role = ""
+	Sender   Role = "sender"
+	Receiver Role = "receiver"
+)
+
+// Query selects a page of entries ordered by timestamp, newest first unless Ascending is set. Zero Since and Until
+// leave the time range open.
+type Query struct {
+	Since     time.Time
+	Until     time.Time
+	Limit     int
+	Cursor    string
+	Ascending bool
+	// Role restricts ByAddress to the transactions sent or received by the address.
+	Role Role
+}
+
+type Page struct {
+	Entries []*Entry `json:"entries"`
+	// NextCursor is set when more entries may follow, pass it in the Cursor of the same query to get them.
+	NextCursor string `json:"nextCursor,omitempty"`
+
+	lastKey []byte
+}
+
+func (i *Index) Get(hash string) (*Entry, error) {
+	var entry *Entry
+
+	err := i.db.View(func(tx *bolt.Tx) error {
+		var err error
+		entry, err = get(tx, hash)
+
+		return err
+	})
+
+	return entry, err
+}
+
+// ByAddress returns the transactions sent or received by the address.
+func (i *Index) ByAddress(address string, q Query) (*Page, error) {
+	return i.page(byAddressBucket, addressPrefix(address), q, func(v []byte) bool {
+		switch q.Role {
+		case Sender:
+			return v[0]&roleSender != 0
+		case Receiver:
+			return v[0]&roleReceiver != 0
+		default:
+			return true
+		}
+	})
+}
+
+// ByTime returns the transactions of every address.
+func (i *Index) ByTime(q Query) (*Page, error) {
+	return i.page(byTimeBucket, nil, q, nil)
+}
+
+// ByBlock returns the transactions of a block.
+func (i *Index) ByBlock(shard processor.Shard, nonce processor.Nonce) ([]*Entry, error) {
+	return i.list(byBlockBucket, blockPrefix(shard, nonce))
+}
+
+// ByOriginalTransaction returns the smart contract results and other transactions whose original transaction is hash,
+// the original transaction excluded.
+func (i *Index) ByOriginalTransaction(hash string) ([]*Entry, error) {
+	return i.list(byOriginalBucket, originalPrefix(hash))
+}
+
+func (i *Index) list(bucket, prefix []byte) ([]*Entry, error) {
+	entries := make([]*Entry, 0)
+
+	err := i.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+		for k, _ := c.Seek(prefix); k != nil && bytes.HasPrefix(k, prefix); k, _ = c.Next() {
+			entry, err := get(tx, string(k[len(prefix):]))
+			if err != nil {
+				return err
+			}
+
+			entries = append(entries, entry)
+		}
+
+		return nil
+	})
+
+	return entries, err
+}
+
+// page walks the keys made of prefix, an encoded timestamp and a transaction hash within the range of the query.
+func (i *Index) page(bucket, prefix []byte, q Query, accept func(v []byte) bool) (*Page, error) {
+	limit := q.Limit
+	if limit <= 0 {
+		limit = defaultLimit
+	}
+	if limit > maxLimit {
+		limit = maxLimit
+	}
+
+	var after []byte
+	if q.Cursor != "" {
+		var err error
+		if after, err = base64.RawURLEncoding.DecodeString(q.Cursor); err != nil || !bytes.HasPrefix(after, prefix) {
+			return nil, ErrInvalidCursor
+		}
+	}
+
+	lower := concat(prefix, encodeTimestamp(q.Since.Unix()))
+	if q.Since.IsZero() {
+		lower = prefix
+	}
+
+	var upper []byte
+	if !q.Until.IsZero() {
+		upper = concat(prefix, encodeTimestamp(q.Until.Unix()+1))
+	} else {
+		upper = prefixEnd(prefix)
+	}
+
+	page := &Page{Entries: make([]*Entry, 0)}
+
+	err := i.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(bucket).Cursor()
+
+		var k, v []byte
+		if q.Ascending {
+			k, v = c.Seek(lower)
+			if after != nil {
+				k, v = c.Seek(after)
+				if k != nil && bytes.Equal(k, after) {
+					k, v = c.Next()
+				}
+			}
+		} else {
+			from := upper
+			if after != nil {
+				from = after
+			}
+			k, v = seekBefore(c, from)
+		}
+
+		for ; k != nil; k, v = next(c, q.Ascending) {
+			if !bytes.HasPrefix(k, prefix) || bytes.Compare(k, lower) < 0 || (upper != nil && bytes.Compare(k, upper) >= 0) {
+				break
+			}
+
+			if accept != nil && !accept(v) {
+				continue
+			}
+
+			if len(page.Entries) == limit {
+				page.NextCursor = base64.RawURLEncoding.EncodeToString(page.lastKey)
+
+				break
+			}
+
+			entry, err := get(tx, string(k[len(prefix)+8:]))
+			if err != nil {
+				return err
+			}
+
+			page.Entries = append(page.Entries, entry)
+			page.lastKey = append([]byte(nil), k...)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return page, nil
+}
+
+// seekBefore positions the cursor on the greatest key lower than key, or on the last key when key is nil.
+func seekBefore(c *bolt.Cursor, key []byte) ([]byte, []byte) {
+	if key == nil {
+		return c.Last()
+	}
+
+	if k, _ := c.Seek(key); k == nil {
+		return c.Last()
+	}
+
+	return c.Prev()
+}
+
+func next(c *bolt.Cursor, ascending bool) ([]byte, []byte) {
+	if ascending {
+		return c.Next()
+	}
+
+	return c.Prev()
+}
+
+// prefixEnd returns the smallest key greater than every key starting with prefix, or nil when there is none.
+func prefixEnd(prefix []byte) []byte {
+	end := append([]byte(nil), prefix...)
+	for i := len(end) - 1; i >= 0; i-- {
+		if end[i] < 0xff {
+			end[i]++
+
+			return end[:i+1]
+		}
+	}
+
+	return nil
+}
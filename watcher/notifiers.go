@@ -0,0 +1,130 @@
+package watcher
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/sink"
+)
+
+const (
+	defaultWebhookTimeout = 10 * time.Second
+)
+
+// NewLogNotifier writes alerts to logger, or to the standard logger when it is nil.
+func NewLogNotifier(logger *log.Logger) *LogNotifier {
+	if logger == nil {
+		logger = log.Default()
+	}
+
+	return &LogNotifier{logger: logger}
+}
+
+type LogNotifier struct {
+	logger *log.Logger
+}
+
+func (n *LogNotifier) Notify(a *Alert) error {
+	label := a.Address
+	if a.Label != "" {
+		label = fmt.Sprintf("%s (%s)", a.Label, a.Address)
+	}
+
+	n.logger.Printf("alert %s on %s: transaction %s of %s from %s to %s in block %d of shard %d\n",
+		a.Rule, label, a.Transaction.Hash(), a.Transaction.Value(), a.Transaction.Sender(), a.Transaction.Receiver(), a.Nonce, a.Shard)
+
+	if a.Suppressed != 0 {
+		n.logger.Printf("alert %s on %s: %d alerts suppressed by the rate limit\n", a.Rule, label, a.Suppressed)
+	}
+
+	return nil
+}
+
+// NewFileNotifier appends alerts as JSON lines to the file at path.
+func NewFileNotifier(path string) (*FileNotifier, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return nil, err
+	}
+
+	return &FileNotifier{file: f}, nil
+}
+
+type FileNotifier struct {
+	mu   sync.Mutex
+	file *os.File
+}
+
+func (n *FileNotifier) Notify(a *Alert) error {
+	line, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	_, err = n.file.Write(append(line, '\n'))
+
+	return err
+}
+
+func (n *FileNotifier) Close() error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return n.file.Close()
+}
+
+// NewWebhookNotifier posts alerts as JSON to url, signed with secret the same way as the webhook sink, see
+// sink.WebhookWriter.
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{
+		url:    url,
+		secret: []byte(secret),
+		client: &http.Client{Timeout: defaultWebhookTimeout},
+	}
+}
+
+type WebhookNotifier struct {
+	url    string
+	secret []byte
+	client *http.Client
+}
+
+func (n *WebhookNotifier) Notify(a *Alert) error {
+	body, err := json.Marshal(a)
+	if err != nil {
+		return err
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	request, err := http.NewRequest(http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+
+	request.Header.Set("Content-Type", "application/json")
+	request.Header.Set(sink.TimestampHeader, timestamp)
+	request.Header.Set(sink.SignatureHeader, "sha256="+sink.Sign(n.secret, timestamp, body))
+
+	response, err := n.client.Do(request)
+	if err != nil {
+		return err
+	}
+	defer response.Body.Close()
+
+	if response.StatusCode < 200 || response.StatusCode >= 300 {
+		return fmt.Errorf("webhook %s responded with status %d", n.url, response.StatusCode)
+	}
+
+	return nil
+}
@@ -0,0 +1,25 @@
+package watcher
+
+import "time"
+
+type Option func(*Watcher)
+
+type Options struct{}
+
+// DeduplicationWindow sets how long a raised alert is remembered to ignore the same transaction delivered again.
+func (oo *Options) DeduplicationWindow(d time.Duration) Option {
+	return func(w *Watcher) {
+		if d > 0 {
+			w.deduplicationWindow = d
+		}
+	}
+}
+
+// RateLimit notifies at most n alerts of the same rule and address per period. The following alerts are counted in the
+// Suppressed field of the next notified one.
+func (oo *Options) RateLimit(n int, per time.Duration) Option {
+	return func(w *Watcher) {
+		w.rateLimit = n
+		w.ratePeriod = per
+	}
+}
@@ -0,0 +1,172 @@
+package watcher
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+var (
+	ErrAddressIsUndefined  = errors.New("watched address is undefined")
+	ErrRuleNameIsUndefined = errors.New("rule name is undefined")
+	ErrInvalidDirection    = errors.New("direction must be incoming, outgoing or any")
+	ErrInvalidMinimumValue = errors.New("minimum value is not a base 10 integer")
+	ErrDuplicatedRuleName  = errors.New("rule name is used twice for the same address")
+	ErrNoAddressIsWatched  = errors.New("no address is watched")
+	ErrNoNotifierIsDefined = errors.New("no notifier is defined")
+)
+
+var failedTransactionStatus = map[string]bool{"fail": true, "invalid": true}
+
+type Direction string
+
+const (
+	Incoming Direction = "incoming"
+	Outgoing Direction = "outgoing"
+	Any      Direction = "any"
+)
+
+// WatchedAddress is an address and the rules raising alerts on its transactions.
+type WatchedAddress struct {
+	Address string  `json:"address"`
+	Label   string  `json:"label,omitempty"`
+	Rules   []*Rule `json:"rules"`
+}
+
+// Rule matches the transactions of a watched address satisfying all of its criteria. Empty criteria match every
+// transaction.
+type Rule struct {
+	Name string `json:"name"`
+	// Direction matches the transactions received by (incoming), sent by (outgoing) or either (any, the default) the
+	// watched address.
+	Direction Direction `json:"direction,omitempty"`
+	// MinValue matches the transactions transferring at least this value, in the smallest denomination.
+	MinValue string `json:"minValue,omitempty"`
+	// Failed matches the failed and invalid transactions only.
+	Failed bool `json:"failed,omitempty"`
+	// Function matches the calls to this function, see processor.Transaction.CallsFunction.
+	Function string `json:"function,omitempty"`
+	// Arguments matches the calls whose decoded arguments have these values, see
+	// processor.Transaction.DecodedArgument.
+	Arguments map[string]string `json:"arguments,omitempty"`
+
+	minValue *big.Int
+}
+
+// LoadWatchList reads a JSON array of WatchedAddress from a file.
+func LoadWatchList(path string) ([]*WatchedAddress, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	addresses := make([]*WatchedAddress, 0)
+	if err := json.Unmarshal(data, &addresses); err != nil {
+		return nil, fmt.Errorf("could not read watch list %s: %w", path, err)
+	}
+
+	return addresses, nil
+}
+
+func (a *WatchedAddress) validate() error {
+	if a.Address == "" {
+		return ErrAddressIsUndefined
+	}
+
+	names := map[string]bool{}
+	for _, r := range a.Rules {
+		if err := r.compile(); err != nil {
+			return fmt.Errorf("rule %q of %s: %w", r.Name, a.Address, err)
+		}
+
+		if names[r.Name] {
+			return fmt.Errorf("rule %q of %s: %w", r.Name, a.Address, ErrDuplicatedRuleName)
+		}
+
+		names[r.Name] = true
+	}
+
+	return nil
+}
+
+func (r *Rule) compile() error {
+	if r.Name == "" {
+		return ErrRuleNameIsUndefined
+	}
+
+	switch r.Direction {
+	case "":
+		r.Direction = Any
+	case Incoming, Outgoing, Any:
+	default:
+		return ErrInvalidDirection
+	}
+
+	r.minValue = nil
+	if r.MinValue != "" {
+		v, ok := new(big.Int).SetString(r.MinValue, 10)
+		if !ok {
+			return ErrInvalidMinimumValue
+		}
+
+		r.minValue = v
+	}
+
+	return nil
+}
+
+// Matches reports whether the transaction, or the inner transaction of a relayed transaction, matches the rule for the
+// watched address. The relayer of a transaction is its sender.
+func (r *Rule) Matches(address string, tx *processor.Transaction) bool {
+	if r.matches(address, tx) {
+		return true
+	}
+
+	inner := tx.InnerTransaction()
+
+	return inner != nil && r.matches(address, inner)
+}
+
+func (r *Rule) matches(address string, tx *processor.Transaction) bool {
+	switch r.Direction {
+	case Incoming:
+		if tx.Receiver() != address {
+			return false
+		}
+	case Outgoing:
+		if tx.Sender() != address {
+			return false
+		}
+	default:
+		if tx.Sender() != address && tx.Receiver() != address {
+			return false
+		}
+	}
+
+	if r.Failed && !failedTransactionStatus[tx.Status()] {
+		return false
+	}
+
+	if r.Function != "" && !tx.CallsFunction(r.Function) {
+		return false
+	}
+
+	for name, value := range r.Arguments {
+		if v, found := tx.DecodedArgument(name); !found || v != value {
+			return false
+		}
+	}
+
+	if r.minValue != nil {
+		v, ok := new(big.Int).SetString(tx.Value(), 10)
+		if !ok || v.Cmp(r.minValue) < 0 {
+			return false
+		}
+	}
+
+	return true
+}
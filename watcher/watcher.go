@@ -0,0 +1,260 @@
+package watcher
+
+import (
+	"log"
+	"sync"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const (
+	defaultDeduplicationWindow = time.Hour
+	defaultQueueSize           = 1000
+)
+
+// Alert is raised when a transaction matches a rule of a watched address.
+type Alert struct {
+	Rule        string                 `json:"rule"`
+	Address     string                 `json:"address"`
+	Label       string                 `json:"label,omitempty"`
+	Shard       processor.Shard        `json:"shard"`
+	Nonce       processor.Nonce        `json:"nonce"`
+	BlockHash   string                 `json:"blockHash"`
+	Transaction *processor.Transaction `json:"transaction"`
+	RaisedAt    time.Time              `json:"raisedAt"`
+	// Suppressed is the number of alerts of the same rule and address dropped by the rate limit since the previous one.
+	Suppressed int `json:"suppressed,omitempty"`
+}
+
+type Notifier interface {
+	Notify(alert *Alert) error
+}
+
+// New returns a watcher raising alerts on the transactions of the watched addresses, it is meant to be given to the
+// processor through the OnTransactionsReceived option.
+//
+// The processor may deliver a transaction more than once, when it replays past blocks or when a cross-shard
+// transaction is seen in both shards, so an alert is raised once per rule, address and transaction within the
+// deduplication window. Alerts can also be rate limited per rule and address.
+//
+// The alerts are queued and notified in order by a goroutine of the watcher, so that a slow notifier does not hold
+// the processor. Close waits for the queued alerts to be notified.
+func New(addresses []*WatchedAddress, notifiers []Notifier, opts ...Option) (*Watcher, error) {
+	if len(addresses) == 0 {
+		return nil, ErrNoAddressIsWatched
+	}
+
+	if len(notifiers) == 0 {
+		return nil, ErrNoNotifierIsDefined
+	}
+
+	w := &Watcher{
+		addresses:           map[string]*WatchedAddress{},
+		notifiers:           notifiers,
+		deduplicationWindow: defaultDeduplicationWindow,
+		raised:              map[string]time.Time{},
+		limits:              map[string]*rateLimit{},
+		now:                 time.Now,
+		queue:               make(chan *Alert, defaultQueueSize),
+		done:                make(chan struct{}),
+	}
+
+	for _, a := range addresses {
+		if err := a.validate(); err != nil {
+			return nil, err
+		}
+
+		w.addresses[a.Address] = a
+	}
+
+	for _, opt := range opts {
+		opt(w)
+	}
+
+	go w.notifyQueued()
+
+	return w, nil
+}
+
+type Watcher struct {
+	mu                  sync.Mutex
+	addresses           map[string]*WatchedAddress
+	notifiers           []Notifier
+	deduplicationWindow time.Duration
+	raised              map[string]time.Time
+	lastPrune           time.Time
+	rateLimit           int
+	ratePeriod          time.Duration
+	limits              map[string]*rateLimit
+	now                 func() time.Time
+	queue               chan *Alert
+	closeMu             sync.RWMutex
+	closed              bool
+	done                chan struct{}
+}
+
+type rateLimit struct {
+	windowStart time.Time
+	count       int
+	suppressed  int
+}
+
+func (w *Watcher) OnTransactionsReceived(shard processor.Shard, nonce processor.Nonce, transactions []*processor.Transaction, blockHash string) {
+	alerts := w.match(shard, nonce, transactions, blockHash)
+
+	w.closeMu.RLock()
+	defer w.closeMu.RUnlock()
+
+	for _, alert := range alerts {
+		if w.closed {
+			log.Printf("could not notify alert %s of %s for transaction %s: watcher is closed\n", alert.Rule, alert.Address, alert.Transaction.Hash())
+
+			continue
+		}
+
+		w.queue <- alert
+	}
+}
+
+// Close notifies the queued alerts. The alerts raised afterwards are not notified.
+func (w *Watcher) Close() error {
+	w.closeMu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.queue)
+	}
+	w.closeMu.Unlock()
+
+	<-w.done
+
+	return nil
+}
+
+// match returns the alerts raised by the transactions, deduplicated and rate limited.
+func (w *Watcher) match(shard processor.Shard, nonce processor.Nonce, transactions []*processor.Transaction, blockHash string) []*Alert {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pruneRaised()
+
+	alerts := make([]*Alert, 0)
+	for _, tx := range transactions {
+		for _, address := range w.watchedAddressesOf(tx) {
+			for _, rule := range address.Rules {
+				if !rule.Matches(address.Address, tx) {
+					continue
+				}
+
+				alert := &Alert{
+					Rule:        rule.Name,
+					Address:     address.Address,
+					Label:       address.Label,
+					Shard:       shard,
+					Nonce:       nonce,
+					BlockHash:   blockHash,
+					Transaction: tx,
+					RaisedAt:    w.now(),
+				}
+
+				if w.raise(alert) {
+					alerts = append(alerts, alert)
+				}
+			}
+		}
+	}
+
+	return alerts
+}
+
+// watchedAddressesOf returns the watched addresses among the parties of the transaction and of its inner transaction.
+func (w *Watcher) watchedAddressesOf(tx *processor.Transaction) []*WatchedAddress {
+	parties := []string{tx.Sender(), tx.Receiver()}
+	if inner := tx.InnerTransaction(); inner != nil {
+		parties = append(parties, inner.Sender(), inner.Receiver())
+	}
+
+	addresses := make([]*WatchedAddress, 0, 2)
+	seen := map[string]bool{}
+
+	for _, party := range parties {
+		if a, found := w.addresses[party]; found && !seen[party] {
+			addresses = append(addresses, a)
+			seen[party] = true
+		}
+	}
+
+	return addresses
+}
+
+// raise reports whether the alert is to be notified, neither raised already nor suppressed by the rate limit.
+func (w *Watcher) raise(alert *Alert) bool {
+	key := alert.Address + "/" + alert.Rule + "/" + alert.Transaction.Hash()
+	if _, found := w.raised[key]; found {
+		return false
+	}
+
+	w.raised[key] = alert.RaisedAt
+
+	return w.allow(alert)
+}
+
+func (w *Watcher) notifyQueued() {
+	defer close(w.done)
+
+	for alert := range w.queue {
+		for _, n := range w.notifiers {
+			if err := n.Notify(alert); err != nil {
+				log.Printf("could not notify alert %s of %s for transaction %s: %v\n", alert.Rule, alert.Address, alert.Transaction.Hash(), err)
+			}
+		}
+	}
+}
+
+// allow applies the rate limit of the rule and address of the alert, and reports the alerts suppressed since the
+// previous one.
+func (w *Watcher) allow(alert *Alert) bool {
+	if w.rateLimit <= 0 {
+		return true
+	}
+
+	key := alert.Address + "/" + alert.Rule
+
+	limit, found := w.limits[key]
+	if !found || alert.RaisedAt.Sub(limit.windowStart) >= w.ratePeriod {
+		suppressed := 0
+		if found {
+			suppressed = limit.suppressed
+		}
+
+		limit = &rateLimit{windowStart: alert.RaisedAt, suppressed: suppressed}
+		w.limits[key] = limit
+	}
+
+	if limit.count >= w.rateLimit {
+		limit.suppressed++
+
+		return false
+	}
+
+	limit.count++
+	alert.Suppressed = limit.suppressed
+	limit.suppressed = 0
+
+	return true
+}
+
+func (w *Watcher) pruneRaised() {
+	now := w.now()
+	if now.Sub(w.lastPrune) < w.deduplicationWindow {
+		return
+	}
+
+	for key, raisedAt := range w.raised {
+		if now.Sub(raisedAt) >= w.deduplicationWindow {
+			delete(w.raised, key)
+		}
+	}
+
+	w.lastPrune = now
+}
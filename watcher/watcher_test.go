@@ -0,0 +1,186 @@
+package watcher
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thefabric-io/elrond-transaction-processor/processor"
+)
+
+const testAddress = "erd1qyu5wthldzr8wx5c9ucg8kjagg0jfs53s8nr3zpz3hypefsdd8ssycr6th"
+
+// testNotifier records the alerts it is notified of, and waits for release when it is set.
+type testNotifier struct {
+	mu      sync.Mutex
+	alerts  []string
+	release chan struct{}
+}
+
+func (n *testNotifier) Notify(a *Alert) error {
+	if n.release != nil {
+		<-n.release
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.alerts = append(n.alerts, fmt.Sprintf("%s/%s/%d", a.Rule, a.Transaction.Hash(), a.Suppressed))
+
+	return nil
+}
+
+func (n *testNotifier) notified() string {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	return strings.Join(n.alerts, " ")
+}
+
+func newTestTransaction(hash, value string) *processor.Transaction {
+	return processor.NewTransactionBuilder().Hash(hash).Sender(testAddress).Receiver("erd1bob").Value(value).Build()
+}
+
+func newTestWatcher(t *testing.T, n Notifier, opts ...Option) *Watcher {
+	t.Helper()
+
+	addresses := []*WatchedAddress{{
+		Address: testAddress,
+		Rules:   []*Rule{{Name: "outgoing", Direction: Outgoing}, {Name: "large", MinValue: "100"}},
+	}}
+
+	w, err := New(addresses, []Notifier{n}, opts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return w
+}
+
+func TestWatcherNotifiesAlerts(t *testing.T) {
+	oo := Options{}
+
+	type delivery struct {
+		at  time.Duration
+		txs []*processor.Transaction
+	}
+
+	tests := []struct {
+		name       string
+		opts       []Option
+		deliveries []delivery
+		expected   string
+	}{
+		{
+			name: "one alert per matching rule",
+			deliveries: []delivery{
+				{txs: []*processor.Transaction{newTestTransaction("tx-1", "1"), newTestTransaction("tx-2", "500")}},
+			},
+			expected: "outgoing/tx-1/0 outgoing/tx-2/0 large/tx-2/0",
+		},
+		{
+			name: "transaction delivered again within the deduplication window",
+			opts: []Option{oo.DeduplicationWindow(time.Hour)},
+			deliveries: []delivery{
+				{txs: []*processor.Transaction{newTestTransaction("tx-1", "1")}},
+				{at: time.Minute, txs: []*processor.Transaction{newTestTransaction("tx-1", "1")}},
+			},
+			expected: "outgoing/tx-1/0",
+		},
+		{
+			name: "transaction delivered again after the deduplication window",
+			opts: []Option{oo.DeduplicationWindow(time.Hour)},
+			deliveries: []delivery{
+				{txs: []*processor.Transaction{newTestTransaction("tx-1", "1")}},
+				{at: 2 * time.Hour, txs: []*processor.Transaction{newTestTransaction("tx-1", "1")}},
+			},
+			expected: "outgoing/tx-1/0 outgoing/tx-1/0",
+		},
+		{
+			name: "rate limit per rule",
+			opts: []Option{oo.RateLimit(2, time.Minute)},
+			deliveries: []delivery{
+				{txs: []*processor.Transaction{newTestTransaction("tx-1", "1"), newTestTransaction("tx-2", "1"), newTestTransaction("tx-3", "500")}},
+				{at: 30 * time.Second, txs: []*processor.Transaction{newTestTransaction("tx-4", "1")}},
+			},
+			expected: "outgoing/tx-1/0 outgoing/tx-2/0 large/tx-3/0",
+		},
+		{
+			name: "suppressed alerts reported by the next one",
+			opts: []Option{oo.RateLimit(1, time.Minute)},
+			deliveries: []delivery{
+				{txs: []*processor.Transaction{newTestTransaction("tx-1", "1"), newTestTransaction("tx-2", "1"), newTestTransaction("tx-3", "1")}},
+				{at: time.Minute, txs: []*processor.Transaction{newTestTransaction("tx-4", "1")}},
+			},
+			expected: "outgoing/tx-1/0 outgoing/tx-4/2",
+		},
+		{
+			name: "suppressed transaction delivered again",
+			opts: []Option{oo.RateLimit(1, time.Minute)},
+			deliveries: []delivery{
+				{txs: []*processor.Transaction{newTestTransaction("tx-1", "1"), newTestTransaction("tx-2", "1")}},
+				{at: time.Minute, txs: []*processor.Transaction{newTestTransaction("tx-2", "1")}},
+			},
+			expected: "outgoing/tx-1/0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Unix(1600000000, 0)
+			now := start
+
+			n := &testNotifier{}
+			w := newTestWatcher(t, n, tt.opts...)
+			w.now = func() time.Time {
+				return now
+			}
+
+			for i, d := range tt.deliveries {
+				now = start.Add(d.at)
+				w.OnTransactionsReceived(0, processor.Nonce(i), d.txs, fmt.Sprint("block-", i))
+			}
+
+			if err := w.Close(); err != nil {
+				t.Fatal(err)
+			}
+
+			if notified := n.notified(); notified != tt.expected {
+				t.Fatalf("expected %q, got %q", tt.expected, notified)
+			}
+		})
+	}
+}
+
+func TestWatcherDoesNotWaitForNotifiers(t *testing.T) {
+	n := &testNotifier{release: make(chan struct{})}
+	w := newTestWatcher(t, n)
+
+	delivered := make(chan struct{})
+	go func() {
+		w.OnTransactionsReceived(0, 1, []*processor.Transaction{newTestTransaction("tx-1", "1")}, "block-1")
+		w.OnTransactionsReceived(0, 2, []*processor.Transaction{newTestTransaction("tx-2", "1")}, "block-2")
+		close(delivered)
+	}()
+
+	select {
+	case <-delivered:
+	case <-time.After(time.Second):
+		t.Fatal("the delivery of the transactions waits for the notifier")
+	}
+
+	close(n.release)
+
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, notified := "outgoing/tx-1/0 outgoing/tx-2/0", n.notified(); notified != expected {
+		t.Fatalf("expected %q, got %q", expected, notified)
+	}
+
+	/* The alerts raised once the watcher is closed are not notified */
+	w.OnTransactionsReceived(0, 3, []*processor.Transaction{newTestTransaction("tx-3", "1")}, "block-3")
+}